@@ -0,0 +1,64 @@
+// Copyright 2016 Marc Lavergne <mlavergn@gmail.com>. All rights reserved.
+// Use of this source code is governed by
+// license that can be found in the LICENSE file.
+
+package godom
+
+// arenaChunkSize the number of nodes or child slots allocated at once
+const arenaChunkSize = 1024
+
+// nodeArena def
+// Chunked storage for the nodes and child slices of a DOM, the chunks are
+// released together once nothing references the DOM or its nodes. The
+// attribute maps stay individually allocated
+type nodeArena struct {
+	nodes    []DOMNode
+	children []*DOMNode
+}
+
+//
+// NewDOMWithArena Constructor
+// A DOM allocating its parsed nodes and their child slices from per-DOM
+// chunks rather than one at a time, reducing the allocations of a parse.
+// Attribute storage is not chunked: Attributes is an exported map read and
+// written directly, and Go maps can't be placed in caller owned memory, so
+// each node has its own map as with NewDOM. CompactDOM stores the attributes
+// of all nodes in shared slices instead. A node retained after the DOM is
+// dropped retains its chunk.
+//
+func NewDOMWithArena() DOM {
+	return NewDOM(WithArena())
+}
+
+//
+// nodeArena: A zeroed node from the current chunk.
+//
+func (id *nodeArena) node() *DOMNode {
+	if len(id.nodes) == 0 {
+		id.nodes = make([]DOMNode, arenaChunkSize)
+	}
+	result := &id.nodes[0]
+	id.nodes = id.nodes[1:]
+
+	return result
+}
+
+//
+// nodeArena: An empty child slice with room for size children, appending
+// past size reallocates rather than overwriting the next slice.
+//
+func (id *nodeArena) childSlice(size int) []*DOMNode {
+	if size == 0 {
+		return []*DOMNode{}
+	}
+	if size > arenaChunkSize/4 {
+		return make([]*DOMNode, 0, size)
+	}
+	if len(id.children) < size {
+		id.children = make([]*DOMNode, arenaChunkSize)
+	}
+	result := id.children[:0:size]
+	id.children = id.children[size:]
+
+	return result
+}
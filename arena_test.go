@@ -0,0 +1,45 @@
+// Copyright 2016, Marc Lavergne <mlavergn@gmail.com>. All rights reserved.
+// Use of this source code is governed by the MIT
+// license that can be found in the LICENSE file.
+
+package godom
+
+import (
+	"testing"
+)
+
+func TestNewDOMWithArena(t *testing.T) {
+	contents := loadData(t, "test_b.html")
+
+	d := NewDOM()
+	d.SetContents(contents)
+	a := NewDOMWithArena()
+	a.SetContents(contents)
+
+	if len(a.document) != len(d.document) || a.RootNode().OuterHTML() != d.RootNode().OuterHTML() {
+		t.Fatalf("failed to parse the same tree")
+	}
+	checkIndexes(t, &a)
+
+	// arena child slices must not overwrite their neighbours when grown
+	arena := &nodeArena{}
+	first := arena.childSlice(1)
+	second := arena.childSlice(1)
+	second = append(second, arena.node())
+	first = append(first, arena.node(), arena.node())
+	if len(second) != 1 || second[0] == first[1] {
+		t.Errorf("failed to isolate child slices")
+	}
+
+	node := a.Find("html", nil)[0]
+	node.SetAttr("lang", "en")
+	if node.Attr("lang") != "en" {
+		t.Errorf("failed to set attribute on arena node")
+	}
+	// nodes without attributes may be written directly
+	for _, node := range a.Find("*", nil) {
+		if node.Attributes == nil {
+			t.Fatalf("unexpected nil attributes of %s", node.Tag)
+		}
+	}
+}
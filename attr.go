@@ -0,0 +1,268 @@
+// Copyright 2016 Marc Lavergne <mlavergn@gmail.com>. All rights reserved.
+// Use of this source code is governed by
+// license that can be found in the LICENSE file.
+
+package godom
+
+import (
+	"strings"
+)
+
+//
+// attrKey : Normalize an attribute key the way the parser does.
+//
+func attrKey(key string) string {
+	return strings.ToLower(strings.TrimSpace(key))
+}
+
+//
+// HasAttr : Does the node carry the attribute key?
+//
+func (id *DOMNode) HasAttr(key string) (result bool) {
+	_, _, result = id.lookupAttr(attrKey(key))
+	return result
+}
+
+//
+// SetAttr : Set the attribute key to val, marking the node as modified. A
+// node of a frozen DOM is left unchanged.
+//
+func (id *DOMNode) SetAttr(key string, val string) {
+	key = attrKey(key)
+	if len(key) == 0 || id.frozen() {
+		return
+	}
+	if stored, _, ok := id.lookupAttr(key); ok {
+		key = stored
+	}
+
+	if id.Attributes == nil {
+		id.Attributes = DOMNodeAttributes{}
+	}
+	if current, ok := id.Attributes[key]; !ok || current != val {
+		id.Attributes[key] = val
+		id.dirty = true
+		id.attrChanged(key, current)
+	}
+}
+
+//
+// RemoveAttr : Remove the attribute key, marking the node as modified. A
+// node of a frozen DOM is left unchanged.
+//
+func (id *DOMNode) RemoveAttr(key string) {
+	if id.frozen() {
+		return
+	}
+	if stored, val, ok := id.lookupAttr(attrKey(key)); ok {
+		key = stored
+		delete(id.Attributes, key)
+		id.dirty = true
+		id.attrChanged(key, val)
+	}
+}
+
+//
+// RenameAttr : Rename the attribute key to newKey retaining its value. A
+// node of a frozen DOM is left unchanged.
+//
+func (id *DOMNode) RenameAttr(key string, newKey string) {
+	key, val, ok := id.lookupAttr(attrKey(key))
+	newKey = attrKey(newKey)
+	if !ok || len(newKey) == 0 || key == newKey || id.frozen() {
+		return
+	}
+
+	replaced := id.Attributes[newKey]
+	delete(id.Attributes, key)
+	id.Attributes[newKey] = val
+	id.dirty = true
+	id.attrChanged(key, val)
+	id.attrChanged(newKey, replaced)
+}
+
+//
+// Dirty : Has the node, its attributes, or its children been modified
+// since it was parsed?
+//
+func (id *DOMNode) Dirty() bool {
+	return id.dirty
+}
+
+//
+// DOMNode: Does the node carry all of the attributes, with class matched by token?
+//
+func (id *DOMNode) matchAttributes(attributes DOMNodeAttributes) bool {
+	return matchAttributes(attributes, id.Attr)
+}
+
+//
+// matchAttributes : Do the values read by attr, empty when missing, hold all
+// of the attributes, with class matched by token?
+//
+func matchAttributes(attributes DOMNodeAttributes, attr func(key string) string) bool {
+	for k, v := range attributes {
+		if k != "class" {
+			if attr(k) != v {
+				return false
+			}
+			continue
+		}
+
+		classes := strings.Fields(v)
+		// an empty class can only be matched exactly
+		if len(classes) == 0 && attr(k) != v {
+			return false
+		}
+		tokens := strings.Fields(attr(k))
+		for _, class := range classes {
+			found := false
+			for _, token := range tokens {
+				if token == class {
+					found = true
+					break
+				}
+			}
+			if !found {
+				return false
+			}
+		}
+	}
+
+	return true
+}
+
+//
+// Classes : The whitespace separated tokens of the class attribute.
+//
+func (id *DOMNode) Classes() []string {
+	return strings.Fields(id.Attr("class"))
+}
+
+//
+// HasClass : Is class one of the class attribute tokens?
+//
+func (id *DOMNode) HasClass(class string) bool {
+	for _, token := range id.Classes() {
+		if token == class {
+			return true
+		}
+	}
+
+	return false
+}
+
+//
+// AddClass : Add class to the class attribute if not already present.
+//
+func (id *DOMNode) AddClass(class string) {
+	class = strings.TrimSpace(class)
+	if len(class) == 0 || id.HasClass(class) {
+		return
+	}
+
+	id.SetAttr("class", strings.Join(append(id.Classes(), class), " "))
+}
+
+//
+// RemoveClass : Remove class from the class attribute, the attribute is
+// removed once no classes remain.
+//
+func (id *DOMNode) RemoveClass(class string) {
+	if !id.HasClass(class) {
+		return
+	}
+
+	classes := []string{}
+	for _, token := range id.Classes() {
+		if token != class {
+			classes = append(classes, token)
+		}
+	}
+
+	if len(classes) == 0 {
+		id.RemoveAttr("class")
+	} else {
+		id.SetAttr("class", strings.Join(classes, " "))
+	}
+}
+
+//
+// Dataset : The data-* attributes keyed by their name without the prefix and
+// camelCased, as HTMLElement.dataset, eg. data-product-id as productId.
+//
+func (id *DOMNode) Dataset() map[string]string {
+	result := map[string]string{}
+	for key, val := range id.Attributes {
+		if strings.HasPrefix(key, "data-") {
+			result[datasetKey(key[len("data-"):])] = val
+		}
+	}
+
+	return result
+}
+
+//
+// datasetKey : CamelCase the hyphenated name, a hyphen followed by a lowercase
+// letter is dropped and the letter uppercased.
+//
+func datasetKey(name string) string {
+	sb := strings.Builder{}
+	for i := 0; i < len(name); i++ {
+		if name[i] == '-' && i+1 < len(name) && name[i+1] >= 'a' && name[i+1] <= 'z' {
+			sb.WriteByte(name[i+1] - 'a' + 'A')
+			i++
+		} else {
+			sb.WriteByte(name[i])
+		}
+	}
+
+	return sb.String()
+}
+
+//
+// datasetAttr : The data-* attribute of the dataset key, which may also be
+// given hyphenated or with its data- prefix.
+//
+func datasetAttr(key string) string {
+	key = strings.TrimSpace(key)
+	if strings.HasPrefix(strings.ToLower(key), "data-") {
+		return attrKey(key)
+	}
+
+	sb := strings.Builder{}
+	sb.WriteString("data-")
+	for i := 0; i < len(key); i++ {
+		if key[i] >= 'A' && key[i] <= 'Z' {
+			sb.WriteByte('-')
+			sb.WriteByte(key[i] - 'A' + 'a')
+		} else {
+			sb.WriteByte(key[i])
+		}
+	}
+
+	return sb.String()
+}
+
+//
+// FindByData : Find the Nodes whose data-* attribute of the dataset key, eg.
+// productId or product-id, has the value.
+//
+func (id *DOM) FindByData(key string, value string) (result []*DOMNode) {
+	return id.ChildFindByData(id.RootNode(), key, value)
+}
+
+//
+// ChildFindByData : Find the child Nodes whose data-* attribute of the dataset
+// key has the value.
+//
+func (id *DOM) ChildFindByData(parent *DOMNode, key string, value string) (result []*DOMNode) {
+	name := datasetAttr(key)
+	for _, node := range id.ChildFindByAttr(parent, name) {
+		if node.Attr(name) == value {
+			result = append(result, node)
+		}
+	}
+
+	return result
+}
@@ -0,0 +1,98 @@
+// Copyright 2016, Marc Lavergne <mlavergn@gmail.com>. All rights reserved.
+// Use of this source code is governed by the MIT
+// license that can be found in the LICENSE file.
+
+package godom
+
+import (
+	"testing"
+)
+
+func TestSetAttr(t *testing.T) {
+	d := NewDOM()
+	d.SetContents("<html><a id='a' href='/foo' target='_blank'>Foo</a></html>")
+	p := d.Find("a", map[string]string{"id": "a"})
+	if len(p) != 1 {
+		d.Dump()
+		t.Fatalf("failed to find node")
+	}
+	node := p[0]
+
+	if node.Dirty() {
+		t.Errorf("parsed node marked dirty")
+	}
+	node.SetAttr("href", "/foo")
+	if node.Dirty() {
+		t.Errorf("unchanged attribute marked dirty")
+	}
+
+	node.SetAttr("REL", "nofollow")
+	node.RemoveAttr("target")
+	node.RenameAttr("id", "data-id")
+	if !node.Dirty() {
+		t.Errorf("modified node not marked dirty")
+	}
+	if !node.HasAttr("rel") || node.HasAttr("target") || node.HasAttr("id") || node.Attr("data-id") != "a" {
+		t.Errorf("failed to modify attributes %s", node.Attributes)
+	}
+	if node.OuterHTML() != "<a data-id=\"a\" href=\"/foo\" rel=\"nofollow\">Foo</a>" {
+		t.Errorf("failed to serialize attributes [%s]", node.OuterHTML())
+	}
+}
+
+func TestClasses(t *testing.T) {
+	d := NewDOM()
+	d.SetContents("<html><div id='a' class=' card  featured '>A</div><div id='b' class='card'>B</div><div id='c' class='cards'>C</div></html>")
+
+	p := d.Find("div", map[string]string{"class": "card"})
+	if len(p) != 2 {
+		d.Dump()
+		t.Fatalf("failed to match class token")
+	}
+	p = d.Find("div", map[string]string{"class": "featured card"})
+	if len(p) != 1 || p[0].Attr("id") != "a" {
+		d.Dump()
+		t.Fatalf("failed to match class tokens")
+	}
+	if d.FindTextForClass("div", "featured") != "A" {
+		t.Errorf("failed to find text for class")
+	}
+
+	node := p[0]
+	if len(node.Classes()) != 2 || !node.HasClass("featured") || node.HasClass("feat") {
+		t.Errorf("failed to tokenize classes %v", node.Classes())
+	}
+
+	node.AddClass("card")
+	node.AddClass("new")
+	node.RemoveClass("featured")
+	if node.Attr("class") != "card new" {
+		t.Errorf("failed to modify classes [%s]", node.Attr("class"))
+	}
+
+	node.RemoveClass("card")
+	node.RemoveClass("new")
+	if node.HasAttr("class") {
+		t.Errorf("failed to remove empty class attribute")
+	}
+}
+
+func TestDataset(t *testing.T) {
+	d := NewDOM()
+	d.SetContents("<html><body><div data-product-id=\"42\" data-x=\"y\" data-a--b=\"c\" id=\"p\"><span data-product-id=\"7\"></span></div></body></html>")
+	div := d.Find("div", nil)[0]
+
+	dataset := div.Dataset()
+	if len(dataset) != 3 || dataset["productId"] != "42" || dataset["x"] != "y" || dataset["a-B"] != "c" {
+		t.Errorf("unexpected dataset %v", dataset)
+	}
+
+	for _, key := range []string{"productId", "product-id", "data-product-id", "DATA-PRODUCT-ID"} {
+		if nodes := d.FindByData(key, "7"); len(nodes) != 1 || nodes[0].Tag != "span" {
+			t.Errorf("failed to find by data key %s", key)
+		}
+	}
+	if len(d.ChildFindByData(d.Find("span", nil)[0], "productId", "42")) != 0 {
+		t.Errorf("failed to scope to parent")
+	}
+}
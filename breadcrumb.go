@@ -0,0 +1,266 @@
+// Copyright 2016 Marc Lavergne <mlavergn@gmail.com>. All rights reserved.
+// Use of this source code is governed by
+// license that can be found in the LICENSE file.
+
+package godom
+
+import (
+	"encoding/json"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// breadcrumbListElements the itemprop of the steps of a BreadcrumbList
+var breadcrumbListElements = map[string]int{"itemListElement": 1}
+
+// breadcrumbSeparators texts between breadcrumbs rather than of one
+var breadcrumbSeparators = map[string]int{">": 1, "›": 1, "»": 1, "/": 1, "|": 1, "→": 1, "·": 1, "•": 1, "-": 1}
+
+// Breadcrumb def
+// A step of the breadcrumb trail, URL is nil for the current page or when the
+// href can't be parsed. Node is the element of the step, nil for JSON-LD
+type Breadcrumb struct {
+	Title string
+	Href  string
+	URL   *url.URL
+	Node  *DOMNode
+}
+
+// breadcrumbStep def
+// A breadcrumb with its declared position, 0 when missing
+type breadcrumbStep struct {
+	Breadcrumb
+	position int
+}
+
+//
+// Breadcrumbs : The breadcrumb trail of the document from the top, taken from
+// the first BreadcrumbList in JSON-LD, then in microdata, then from the links
+// of an element whose aria-label, class, or id names a breadcrumb. Steps are
+// ordered by their declared position and resolved against the base.
+//
+func (id *DOM) Breadcrumbs() []*Breadcrumb {
+	base := id.BaseURL()
+	for _, source := range []func() []breadcrumbStep{id.jsonLDBreadcrumbs, id.microdataBreadcrumbs, id.markupBreadcrumbs} {
+		steps := source()
+		if len(steps) == 0 {
+			continue
+		}
+		sort.SliceStable(steps, func(i, j int) bool { return steps[i].position < steps[j].position })
+		result := make([]*Breadcrumb, len(steps))
+		for i := range steps {
+			result[i] = &steps[i].Breadcrumb
+			if len(result[i].Href) > 0 {
+				result[i].URL = resolveURL(base, result[i].Href)
+			}
+		}
+		return result
+	}
+
+	return nil
+}
+
+//
+// DOM: The steps of the first BreadcrumbList of the JSON-LD scripts.
+//
+func (id *DOM) jsonLDBreadcrumbs() (result []breadcrumbStep) {
+	for _, node := range id.Find("script", nil) {
+		mediaType, _, _ := strings.Cut(strings.ToLower(node.Attr("type")), ";")
+		if strings.TrimSpace(mediaType) != "application/ld+json" {
+			continue
+		}
+		var value interface{}
+		if err := json.Unmarshal([]byte(node.Text()), &value); err != nil {
+			continue
+		}
+		if list := jsonLDFind(value, "BreadcrumbList"); list != nil {
+			items, _ := list["itemListElement"].([]interface{})
+			for _, item := range items {
+				if step, ok := jsonLDBreadcrumb(item); ok {
+					result = append(result, step)
+				}
+			}
+			return result
+		}
+	}
+
+	return nil
+}
+
+//
+// jsonLDFind : The first object of the type within the value, searching
+// arrays, @graph, and nested objects.
+//
+func jsonLDFind(value interface{}, typeName string) map[string]interface{} {
+	switch v := value.(type) {
+	case []interface{}:
+		for _, item := range v {
+			if found := jsonLDFind(item, typeName); found != nil {
+				return found
+			}
+		}
+	case map[string]interface{}:
+		if jsonLDIsType(v, typeName) {
+			return v
+		}
+		keys := make([]string, 0, len(v))
+		for key := range v {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			if found := jsonLDFind(v[key], typeName); found != nil {
+				return found
+			}
+		}
+	}
+
+	return nil
+}
+
+//
+// jsonLDIsType : Is the @type of the object, or one of them, the type?
+//
+func jsonLDIsType(object map[string]interface{}, typeName string) bool {
+	switch t := object["@type"].(type) {
+	case string:
+		return t == typeName || strings.HasSuffix(t, "/"+typeName)
+	case []interface{}:
+		for _, item := range t {
+			if s, ok := item.(string); ok && (s == typeName || strings.HasSuffix(s, "/"+typeName)) {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+//
+// jsonLDBreadcrumb : The step of a ListItem, whose item is a URL or an object
+// with an @id or url and a name.
+//
+func jsonLDBreadcrumb(value interface{}) (step breadcrumbStep, ok bool) {
+	listItem, ok := value.(map[string]interface{})
+	if !ok {
+		return step, false
+	}
+
+	step.position = jsonLDPosition(listItem["position"])
+	step.Title, _ = listItem["name"].(string)
+	switch item := listItem["item"].(type) {
+	case string:
+		step.Href = item
+	case map[string]interface{}:
+		if href, ok := item["@id"].(string); ok {
+			step.Href = href
+		} else if href, ok := item["url"].(string); ok {
+			step.Href = href
+		}
+		if name, ok := item["name"].(string); ok && len(step.Title) == 0 {
+			step.Title = name
+		}
+	}
+	step.Title = strings.TrimSpace(step.Title)
+	step.Href = strings.TrimSpace(step.Href)
+
+	return step, len(step.Title) > 0 || len(step.Href) > 0
+}
+
+//
+// jsonLDPosition : The position as a number or a string holding one.
+//
+func jsonLDPosition(value interface{}) int {
+	switch v := value.(type) {
+	case float64:
+		return int(v)
+	case string:
+		position, _ := strconv.Atoi(strings.TrimSpace(v))
+		return position
+	}
+
+	return 0
+}
+
+//
+// DOM: The steps of the first BreadcrumbList microdata item.
+//
+func (id *DOM) microdataBreadcrumbs() (result []breadcrumbStep) {
+	for _, node := range id.document {
+		if !node.HasAttr("itemscope") || !strings.HasSuffix(strings.TrimSpace(node.Attr("itemtype")), "schema.org/BreadcrumbList") {
+			continue
+		}
+		for _, prop := range id.microdataProperties(node) {
+			if !prop.HasAttr("itemscope") || !containsRel(strings.Fields(prop.Attr("itemprop")), breadcrumbListElements) {
+				continue
+			}
+			step := breadcrumbStep{Breadcrumb: Breadcrumb{Node: prop}}
+			for _, field := range id.microdataProperties(prop) {
+				names := strings.Fields(field.Attr("itemprop"))
+				for _, name := range names {
+					switch name {
+					case "name":
+						step.Title = strings.TrimSpace(microdataValue(field))
+					case "position":
+						step.position, _ = strconv.Atoi(strings.TrimSpace(microdataValue(field)))
+					case "item":
+						if field.HasAttr("itemscope") {
+							step.Href = strings.TrimSpace(field.Attr("itemid"))
+							if href, ok := microdataURLAttrs[field.Tag]; ok && len(step.Href) == 0 {
+								step.Href = strings.TrimSpace(field.Attr(href))
+							}
+						} else {
+							step.Href = strings.TrimSpace(microdataValue(field))
+						}
+					}
+				}
+			}
+			if len(step.Title) > 0 || len(step.Href) > 0 {
+				result = append(result, step)
+			}
+		}
+		return result
+	}
+
+	return nil
+}
+
+//
+// DOM: The steps of the first element whose aria-label, class, or id names a
+// breadcrumb, its list items or else its links.
+//
+func (id *DOM) markupBreadcrumbs() (result []breadcrumbStep) {
+	for _, node := range id.Find("*", nil) {
+		label := strings.ToLower(node.Attr("aria-label") + " " + node.Attr("class") + " " + node.Attr("id"))
+		if !strings.Contains(label, "breadcrumb") {
+			continue
+		}
+
+		items := id.ChildFind(node, "li", nil)
+		if len(items) == 0 {
+			items = id.ChildFind(node, "a", nil)
+		}
+		for _, item := range items {
+			step := breadcrumbStep{Breadcrumb: Breadcrumb{Node: item}, position: len(result) + 1}
+			link := item
+			if item.Tag != "a" {
+				link = id.ChildFindFirst(item, "a", nil)
+			}
+			if link != nil {
+				step.Href = strings.TrimSpace(link.Attr("href"))
+			}
+			step.Title = strings.Join(strings.Fields(item.ReaderTextWithOptions(TextOptions{Separator: " "})), " ")
+			if len(step.Title) == 0 || breadcrumbSeparators[step.Title] == 1 {
+				continue
+			}
+			result = append(result, step)
+		}
+		if len(result) > 0 {
+			return result
+		}
+	}
+
+	return nil
+}
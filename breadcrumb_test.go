@@ -0,0 +1,60 @@
+// Copyright 2016, Marc Lavergne <mlavergn@gmail.com>. All rights reserved.
+// Use of this source code is governed by the MIT
+// license that can be found in the LICENSE file.
+
+package godom
+
+import (
+	"net/url"
+	"testing"
+)
+
+func checkBreadcrumbs(t *testing.T, crumbs []*Breadcrumb, titles []string, urls []string) {
+	if len(crumbs) != len(titles) {
+		t.Fatalf("expected %d breadcrumbs found %d", len(titles), len(crumbs))
+	}
+	for i, crumb := range crumbs {
+		u := ""
+		if crumb.URL != nil {
+			u = crumb.URL.String()
+		}
+		if crumb.Title != titles[i] || u != urls[i] {
+			t.Errorf("expected %s %s found %s %s", titles[i], urls[i], crumb.Title, u)
+		}
+	}
+}
+
+func TestBreadcrumbs(t *testing.T) {
+	base, _ := url.Parse("https://example.com/shop/tv/1")
+
+	d := NewDOM()
+	d.SetContents(`<html><head><script type="application/ld+json">{"@context":"https://schema.org","@graph":[{"@type":"WebPage"},{"@type":"BreadcrumbList","itemListElement":[
+		{"@type":"ListItem","position":2,"name":"TV","item":"https://example.com/shop/tv"},
+		{"@type":"ListItem","position":1,"item":{"@id":"/shop","name":"Shop"}},
+		{"@type":"ListItem","position":"3","name":"Model 1"}]}]}</script></head>
+		<body><nav aria-label="Breadcrumb"><a href="/">Ignored</a></nav></body></html>`)
+	d.SetBaseURL(base)
+	checkBreadcrumbs(t, d.Breadcrumbs(), []string{"Shop", "TV", "Model 1"}, []string{"https://example.com/shop", "https://example.com/shop/tv", ""})
+
+	d = NewDOM()
+	d.SetContents(`<html><body><ol itemscope itemtype="https://schema.org/BreadcrumbList">
+		<li itemprop="itemListElement" itemscope itemtype="https://schema.org/ListItem"><a itemprop="item" href="/books"><span itemprop="name">Books</span></a><meta itemprop="position" content="1"></li>
+		<li itemprop="itemListElement" itemscope itemtype="https://schema.org/ListItem"><a itemprop="item" href="/books/sf"><span itemprop="name">Science Fiction</span></a><meta itemprop="position" content="2"></li>
+		</ol></body></html>`)
+	d.SetBaseURL(base)
+	checkBreadcrumbs(t, d.Breadcrumbs(), []string{"Books", "Science Fiction"}, []string{"https://example.com/books", "https://example.com/books/sf"})
+
+	d = NewDOM()
+	d.SetContents(`<html><body><div class="site-breadcrumbs"><ul><li><a href="/">Home</a></li><li>›</li><li><a href="/shop">Shop</a></li><li>Current  item</li></ul></div></body></html>`)
+	d.SetBaseURL(base)
+	checkBreadcrumbs(t, d.Breadcrumbs(), []string{"Home", "Shop", "Current item"}, []string{"https://example.com/", "https://example.com/shop", ""})
+	if crumbs := d.Breadcrumbs(); crumbs[0].Node.Tag != "li" {
+		t.Errorf("unexpected node %s", crumbs[0].Node.Tag)
+	}
+
+	d = NewDOM()
+	d.SetContents(`<html><body><p>None</p></body></html>`)
+	if crumbs := d.Breadcrumbs(); crumbs != nil {
+		t.Errorf("unexpected breadcrumbs %v", crumbs)
+	}
+}
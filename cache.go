@@ -0,0 +1,70 @@
+// Copyright 2016 Marc Lavergne <mlavergn@gmail.com>. All rights reserved.
+// Use of this source code is governed by
+// license that can be found in the LICENSE file.
+
+package godom
+
+import (
+	"sort"
+	"strings"
+)
+
+// findKey def
+// The arguments of a ChildFindLimit call
+type findKey struct {
+	parent     *DOMNode
+	tag        string
+	attributes string
+	limit      int
+}
+
+//
+// SetFindCache : Enable or disable the memoization of Find, ChildFind, and
+// their limited forms. Cached results are discarded whenever the tree is
+// parsed or mutated, or an attribute is set through the DOMNode setters, so
+// modifying an Attributes map directly requires disabling the cache. A frozen
+// DOM keeps the results cached before Freeze.
+//
+func (id *DOM) SetFindCache(enabled bool) {
+	if id.frozen {
+		return
+	}
+	id.findCache = nil
+	if enabled {
+		id.findCache = map[findKey][]*DOMNode{}
+	}
+}
+
+//
+// DOM: Discard the cached Find results.
+//
+func (id *DOM) invalidateFinds() {
+	if id.findCache != nil && len(id.findCache) > 0 {
+		id.findCache = map[findKey][]*DOMNode{}
+	}
+}
+
+//
+// newFindKey : The cache key of the Find arguments, the attributes in key
+// order.
+//
+func newFindKey(parent *DOMNode, tag string, attributes DOMNodeAttributes, limit int) findKey {
+	keys := make([]string, 0, len(attributes))
+	for key := range attributes {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	sb := strings.Builder{}
+	for _, key := range keys {
+		sb.WriteString(key)
+		sb.WriteByte(0)
+		sb.WriteString(attributes[key])
+		sb.WriteByte(0)
+	}
+	if limit < 0 {
+		limit = 0
+	}
+
+	return findKey{parent: parent, tag: tag, attributes: sb.String(), limit: limit}
+}
@@ -0,0 +1,52 @@
+// Copyright 2016, Marc Lavergne <mlavergn@gmail.com>. All rights reserved.
+// Use of this source code is governed by the MIT
+// license that can be found in the LICENSE file.
+
+package godom
+
+import (
+	"testing"
+)
+
+func TestFindCache(t *testing.T) {
+	d := NewDOM()
+	d.SetFindCache(true)
+	d.SetContents(`<html><body><div class="a b"><p>1</p></div><div class="b"><p>2</p></div></body></html>`)
+
+	found := d.Find("div", DOMNodeAttributes{"class": "b"})
+	if len(found) != 2 || len(d.findCache) != 1 {
+		t.Fatalf("unexpected matches %d cached %d", len(found), len(d.findCache))
+	}
+	found[0] = nil
+	if again := d.Find("div", DOMNodeAttributes{"class": "b"}); len(again) != 2 || again[0] == nil {
+		t.Error("modified the cached result")
+	}
+	if len(d.FindLimit("div", DOMNodeAttributes{"class": "b"}, 1)) != 1 || len(d.findCache) != 2 {
+		t.Error("failed to key the limit")
+	}
+
+	div := d.Find("div", nil)[1]
+	div.SetAttr("class", "c")
+	if found := d.Find("div", DOMNodeAttributes{"class": "b"}); len(found) != 1 {
+		t.Errorf("expected the attribute change to invalidate, found %d", len(found))
+	}
+	if err := div.AppendChild(d.Find("p", nil)[0]); err != nil {
+		t.Fatal(err)
+	}
+	if found := d.ChildFind(div, "p", nil); len(found) != 2 {
+		t.Errorf("expected the move to invalidate, found %d", len(found))
+	}
+
+	d.Freeze()
+	cached := len(d.findCache)
+	if found := d.Find("span", nil); len(found) != 0 || len(d.findCache) != cached {
+		t.Error("wrote the cache of a frozen DOM")
+	}
+
+	d = NewDOM()
+	d.SetContents(`<p>x</p>`)
+	d.Find("p", nil)
+	if d.findCache != nil {
+		t.Error("cached without enabling")
+	}
+}
@@ -0,0 +1,156 @@
+// Copyright 2016 Marc Lavergne <mlavergn@gmail.com>. All rights reserved.
+// Use of this source code is governed by
+// license that can be found in the LICENSE file.
+
+package godom
+
+import (
+	"strings"
+)
+
+//
+// TagName : The tag as written in the contents, eg. foreignObject, when
+// parsed with the PreserveCase option, otherwise Tag. Tag remains lowercase
+// for HTML so lookups ignore case.
+//
+func (id *DOMNode) TagName() string {
+	if len(id.name) > 0 {
+		return id.name
+	}
+
+	return id.Tag
+}
+
+//
+// DOMNode: The stored key and the value of the attribute key. The case of
+// the key is ignored when the node carries keys with upper case, eg. the
+// viewBox of an svg element.
+//
+func (id *DOMNode) lookupAttr(key string) (string, string, bool) {
+	if val, ok := id.Attributes[key]; ok || !id.foldAttrs {
+		return key, val, ok
+	}
+
+	return id.foldAttr(key)
+}
+
+//
+// DOMNode: The stored key matching the key ignoring case, and its value.
+//
+func (id *DOMNode) foldAttr(key string) (string, string, bool) {
+	for stored, val := range id.Attributes {
+		if strings.EqualFold(stored, key) {
+			return stored, val, true
+		}
+	}
+
+	return key, "", false
+}
+
+//
+// DOMNode: Flag HTML nodes carrying attribute keys with upper case so their
+// lookups ignore case. XML keys are matched exactly.
+//
+func (id *DOMNode) foldCase() {
+	id.foldAttrs = false
+	if id.dom != nil && id.dom.xml {
+		return
+	}
+	for key := range id.Attributes {
+		if hasUpper(key) {
+			id.foldAttrs = true
+			return
+		}
+	}
+}
+
+//
+// DOMNode: Restore the case of the tag and attribute keys as written in the
+// start tag. Names written in lowercase keep the case given by the parser,
+// eg. the adjusted SVG names.
+//
+func (id *DOMNode) setCase(name string, keys []string) {
+	if name != id.Tag && strings.EqualFold(name, id.Tag) {
+		id.name = name
+	}
+	for _, key := range keys {
+		if !hasUpper(key) {
+			continue
+		}
+		if stored, val, ok := id.foldAttr(key); ok && stored != key {
+			delete(id.Attributes, stored)
+			id.Attributes[key] = val
+		}
+	}
+	id.foldCase()
+}
+
+//
+// hasUpper : Does the ASCII string hold an upper case letter?
+//
+func hasUpper(s string) bool {
+	for i := 0; i < len(s); i++ {
+		if s[i] >= 'A' && s[i] <= 'Z' {
+			return true
+		}
+	}
+
+	return false
+}
+
+//
+// rawTagCase : The tag and attribute keys of a raw start tag as written,
+// scanned as the tokenizer does.
+//
+func rawTagCase(raw string) (name string, keys []string) {
+	if len(raw) < 2 || raw[0] != '<' {
+		return "", nil
+	}
+
+	isSpace := func(c byte) bool { return c == ' ' || c == '\t' || c == '\n' || c == '\r' || c == '\f' }
+	i := 1
+	for i < len(raw) && !isSpace(raw[i]) && raw[i] != '/' && raw[i] != '>' {
+		i++
+	}
+	name = raw[1:i]
+
+	for i < len(raw) {
+		for i < len(raw) && (isSpace(raw[i]) || raw[i] == '/') {
+			i++
+		}
+		if i >= len(raw) || raw[i] == '>' {
+			break
+		}
+		start := i
+		// a key may begin with =
+		i++
+		for i < len(raw) && !isSpace(raw[i]) && raw[i] != '/' && raw[i] != '>' && raw[i] != '=' {
+			i++
+		}
+		keys = append(keys, raw[start:i])
+
+		for i < len(raw) && isSpace(raw[i]) {
+			i++
+		}
+		if i >= len(raw) || raw[i] != '=' {
+			continue
+		}
+		i++
+		for i < len(raw) && isSpace(raw[i]) {
+			i++
+		}
+		if i < len(raw) && (raw[i] == '"' || raw[i] == '\'') {
+			end := strings.IndexByte(raw[i+1:], raw[i])
+			if end < 0 {
+				break
+			}
+			i += end + 2
+			continue
+		}
+		for i < len(raw) && !isSpace(raw[i]) && raw[i] != '>' {
+			i++
+		}
+	}
+
+	return name, keys
+}
@@ -0,0 +1,94 @@
+// Copyright 2016, Marc Lavergne <mlavergn@gmail.com>. All rights reserved.
+// Use of this source code is governed by the MIT
+// license that can be found in the LICENSE file.
+
+package godom
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestPreserveCase(t *testing.T) {
+	contents := `<html><body><DIV Data-Id="1"><svg viewBox="0 0 10 10" preserveAspectRatio="none"><foreignObject><p>x</p></foreignObject><linearGradient></linearGradient></svg></DIV></body></html>`
+
+	d := NewDOM(WithPreserveCase())
+	if err := d.SetContents(contents); err != nil {
+		t.Fatal(err)
+	}
+	div := d.FindFirst("DIV", nil)
+	if div == nil || div.Tag != "div" || div.TagName() != "DIV" {
+		t.Fatalf("expected the div to keep its case, got %v", div)
+	}
+	if div.Attr("data-id") != "1" || !div.HasAttr("DATA-ID") || d.FindFirst("div", DOMNodeAttributes{"data-id": "1"}) != div {
+		t.Errorf("expected the attribute lookups to ignore case, got %v", div.Attributes)
+	}
+	if fo := d.FindFirst("foreignObject", nil); fo == nil || fo.TagName() != "foreignObject" {
+		t.Errorf("expected foreignObject, got %v", fo)
+	}
+	svg := d.FindFirst("svg", nil)
+	if svg.Attr("viewbox") != "0 0 10 10" || !MustParseSelector("svg[viewbox]").Match(svg) {
+		t.Errorf("expected viewBox to match ignoring case, got %v", svg.Attributes)
+	}
+	svg.SetAttr("viewbox", "0 0 20 20")
+	if svg.Attributes["viewBox"] != "0 0 20 20" || len(svg.Attributes) != 2 {
+		t.Errorf("expected SetAttr to update viewBox, got %v", svg.Attributes)
+	}
+
+	html := d.RootNode().OuterHTML()
+	for _, expected := range []string{`<DIV Data-Id="1">`, `</DIV>`, `<svg preserveAspectRatio="none" viewBox="0 0 20 20">`, `<foreignObject>`, `</linearGradient>`} {
+		if !strings.Contains(html, expected) {
+			t.Errorf("expected %s in %s", expected, html)
+		}
+	}
+
+	// the default keeps HTML names lowercase
+	d = NewDOM()
+	d.SetContents(contents)
+	if div := d.FindFirst("div", nil); div.TagName() != "div" || !div.HasAttr("data-id") {
+		t.Errorf("unexpected default case %s %v", div.TagName(), div.Attributes)
+	}
+	if svg := d.FindFirst("svg", nil); !svg.HasAttr("viewBox") {
+		t.Errorf("expected viewBox to be found ignoring case")
+	}
+}
+
+func TestPreserveCaseMutate(t *testing.T) {
+	d := NewDOM(WithPreserveCase())
+	d.SetContents(`<html><body><div ID="x" CLASS="a b"><p>one</p><p>two</p></div></body></html>`)
+	div := d.FindFirst("div", nil)
+	if div.Attributes["ID"] != "x" || strings.Join(div.Classes(), " ") != "a b" || !div.HasClass("a") {
+		t.Fatalf("unexpected classes %v of %v", div.Classes(), div.Attributes)
+	}
+
+	// the removal reindexes the ids
+	if err := div.RemoveChild(d.FindFirst("p", nil)); err != nil {
+		t.Fatal(err)
+	}
+	if d.ByID("x") != div || !div.HasClass("b") || div.CSSPath() != "#x" {
+		t.Errorf("expected the id and classes after the mutation, got %v %s", d.ByID("x"), div.CSSPath())
+	}
+	div.AddClass("c")
+	if div.Attributes["CLASS"] != "a b c" || len(div.Attributes) != 2 {
+		t.Errorf("expected AddClass to update CLASS, got %v", div.Attributes)
+	}
+}
+
+func TestPreserveCaseStream(t *testing.T) {
+	d := NewDOM(WithPreserveCase())
+	d.SetContentsFromReader(strings.NewReader(`<Item Key='a' Flag><SubItem/></Item>`))
+	item := d.FindFirst("item", nil)
+	if item == nil || item.TagName() != "Item" || item.Attr("key") != "a" || !item.HasAttr("flag") {
+		t.Fatalf("unexpected item %v", item)
+	}
+	if sub := d.FindFirst("subitem", nil); sub == nil || sub.TagName() != "SubItem" {
+		t.Errorf("unexpected sub item %v", sub)
+	}
+}
+
+func TestRawTagCase(t *testing.T) {
+	name, keys := rawTagCase(`<Svg viewBox = "0 0 1 1" data-X='a>b' hidden/>`)
+	if name != "Svg" || strings.Join(keys, ",") != "viewBox,data-X,hidden" {
+		t.Errorf("unexpected %s %v", name, keys)
+	}
+}
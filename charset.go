@@ -0,0 +1,88 @@
+// Copyright 2016 Marc Lavergne <mlavergn@gmail.com>. All rights reserved.
+// Use of this source code is governed by
+// license that can be found in the LICENSE file.
+
+package godom
+
+import (
+	"bufio"
+	"golang.org/x/net/html/charset"
+	"io"
+	"mime"
+	"strings"
+)
+
+// charsetSniffLength bytes examined for a BOM or <meta charset>
+const charsetSniffLength = 1024
+
+//
+// SetContentsWithContentType : decode the raw contents to UTF-8 and parse them.
+// The encoding is taken from a byte order mark, the charset parameter of
+// contentType (eg. the Content-Type header, may be empty), a <meta charset>
+// within the first 1024 bytes, or sniffed, in that order.
+//
+func (id *DOM) SetContentsWithContentType(contents []byte, contentType string) error {
+	if id.frozen {
+		return ErrFrozen
+	}
+
+	encoding, name, _ := charset.DetermineEncoding(contents, contentType)
+	decoded, err := encoding.NewDecoder().Bytes(contents)
+	if err != nil {
+		return err
+	}
+	id.charset = name
+
+	return id.SetContents(string(decoded))
+}
+
+//
+// SetContentsFromReaderWithContentType : decode the html stream to UTF-8 and
+// tokenize it, detecting the encoding as SetContentsWithContentType does.
+//
+func (id *DOM) SetContentsFromReaderWithContentType(r io.Reader, contentType string) error {
+	if id.frozen {
+		return ErrFrozen
+	}
+
+	buffered := bufio.NewReaderSize(r, charsetSniffLength)
+	// a short peek is expected for small documents
+	prefix, err := buffered.Peek(charsetSniffLength)
+	if err != nil && err != io.EOF && err != bufio.ErrBufferFull {
+		return err
+	}
+
+	encoding, name, _ := charset.DetermineEncoding(prefix, contentType)
+	id.charset = name
+
+	return id.SetContentsFromReader(encoding.NewDecoder().Reader(buffered))
+}
+
+//
+// Charset : The name of the encoding the contents were decoded from. For
+// contents supplied as a string, the encoding declared by a <meta charset> or
+// <meta http-equiv="Content-Type">, empty when there is none.
+//
+func (id *DOM) Charset() string {
+	if len(id.charset) > 0 {
+		return id.charset
+	}
+
+	for _, node := range id.Find("meta", nil) {
+		label := node.Attr("charset")
+		if len(label) == 0 && strings.EqualFold(strings.TrimSpace(node.Attr("http-equiv")), "content-type") {
+			if _, params, err := mime.ParseMediaType(node.Attr("content")); err == nil {
+				label = params["charset"]
+			}
+		}
+		if label = strings.TrimSpace(label); len(label) > 0 {
+			// the canonical name of a known label
+			if encoding, name := charset.Lookup(label); encoding != nil {
+				return name
+			}
+			return strings.ToLower(label)
+		}
+	}
+
+	return ""
+}
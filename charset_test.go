@@ -0,0 +1,46 @@
+// Copyright 2016, Marc Lavergne <mlavergn@gmail.com>. All rights reserved.
+// Use of this source code is governed by the MIT
+// license that can be found in the LICENSE file.
+
+package godom
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestSetContentsWithContentType(t *testing.T) {
+	// café in ISO-8859-1
+	contents := []byte("<html><body><p>caf\xe9</p></body></html>")
+
+	d := NewDOM()
+	err := d.SetContentsWithContentType(contents, "text/html; charset=ISO-8859-1")
+	if err != nil {
+		t.Errorf("failed to parse contents %s", err)
+	}
+	if p := d.Find("p", nil); len(p) != 1 || p[0].Text() != "café" {
+		d.Dump()
+		t.Errorf("failed to decode contents")
+	}
+	if d.Charset() != "windows-1252" {
+		t.Errorf("failed to record charset [%s]", d.Charset())
+	}
+
+	d = NewDOM()
+	contents = []byte("<html><head><meta charset=\"iso-8859-1\"></head><body><p>caf\xe9</p></body></html>")
+	err = d.SetContentsFromReaderWithContentType(bytes.NewReader(contents), "")
+	if err != nil {
+		t.Errorf("failed to parse reader %s", err)
+	}
+	if p := d.Find("p", nil); len(p) != 1 || p[0].Text() != "café" {
+		d.Dump()
+		t.Errorf("failed to decode reader using meta charset")
+	}
+
+	d = NewDOM()
+	d.SetContentsWithContentType([]byte("<html><p>café</p></html>"), "")
+	if p := d.Find("p", nil); len(p) != 1 || p[0].Text() != "café" || d.Charset() != "utf-8" {
+		d.Dump()
+		t.Errorf("failed to detect UTF-8 [%s]", d.Charset())
+	}
+}
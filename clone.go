@@ -0,0 +1,189 @@
+// Copyright 2016 Marc Lavergne <mlavergn@gmail.com>. All rights reserved.
+// Use of this source code is governed by
+// license that can be found in the LICENSE file.
+
+package godom
+
+import (
+	"strings"
+)
+
+//
+// Clone : A fully independent copy of the DOM, including comment and
+// doctype nodes, with its own nodes and tag index. The copy is not frozen.
+//
+func (id *DOM) Clone() *DOM {
+	result := NewDOM()
+	result.contents = id.contents
+	result.charset = id.charset
+	result.options = id.options
+	result.tags = id.tags
+	result.xml = id.xml
+	result.externalStyles = id.externalStyles
+	result.logger = id.logger
+	if id.url != nil {
+		u := *id.url
+		result.url = &u
+	}
+	if id.baseURL != nil {
+		u := *id.baseURL
+		result.baseURL = &u
+	}
+
+	copies := make(map[*DOMNode]*DOMNode, len(id.document))
+	for _, node := range id.document {
+		copies[node] = node.copyNode()
+		copies[node].dom = &result
+		result.document = append(result.document, copies[node])
+	}
+	for _, node := range id.document {
+		nodeCopy := copies[node]
+		nodeCopy.Parent = copies[node.Parent]
+		for _, child := range node.Children {
+			nodeCopy.Children = append(nodeCopy.Children, copies[child])
+		}
+	}
+	result.reindex()
+
+	return &result
+}
+
+//
+// CloneSubtree : A detached copy of the node and its element descendants,
+// numbered in document order from 1. Comments are not copied as they only
+// belong to a DOM.
+//
+func (id *DOMNode) CloneSubtree() *DOMNode {
+	result := id.clone()
+	for i, node := range result.elementNodes() {
+		node.Index = i + 1
+	}
+
+	return result
+}
+
+//
+// DOMNode: A detached deep copy of the node and its element descendants.
+//
+func (id *DOMNode) clone() *DOMNode {
+	result := id.copyNode()
+	for _, child := range id.Children {
+		childClone := child.clone()
+		childClone.Parent = result
+		result.Children = append(result.Children, childClone)
+	}
+
+	return result
+}
+
+//
+// DOMNode: A copy of the node content without its parent or children.
+//
+func (id *DOMNode) copyNode() *DOMNode {
+	result := &DOMNode{
+		Index:         id.Index,
+		Tag:           id.Tag,
+		Attributes:    DOMNodeAttributes{},
+		TextFragments: append([]string(nil), id.TextFragments...),
+		Children:      make([]*DOMNode, 0, len(id.Children)),
+		textIndex:     append([]int(nil), id.textIndex...),
+		dirty:         id.dirty,
+		preserveSpace: id.preserveSpace,
+		name:          id.name,
+		foldAttrs:     id.foldAttrs,
+		textBefore:    id.textBefore,
+		sourceOffset:  id.sourceOffset,
+		sourceEnd:     id.sourceEnd,
+		sourceLine:    id.sourceLine,
+		sourceColumn:  id.sourceColumn,
+	}
+	for key, val := range id.Attributes {
+		result.Attributes[key] = val
+	}
+
+	return result
+}
+
+//
+// ImportNode : A detached copy of the node from another DOM, ready to be
+// inserted into this DOM with AppendChild or InsertBefore, which number it in
+// document order. A deep import copies the element descendants, a shallow one
+// copies the attributes and text of the node only. The source is unchanged.
+//
+func (id *DOM) ImportNode(n *DOMNode, deep bool) *DOMNode {
+	var result *DOMNode
+	if deep {
+		result = n.clone()
+	} else {
+		result = n.copyNode()
+		// the text of the node precedes its missing children
+		result.textIndex = make([]int, len(result.TextFragments))
+	}
+
+	for i, node := range result.elementNodes() {
+		node.Index = i + 1
+		if !id.xml && n.dom != nil && n.dom.xml {
+			node.Tag = strings.ToLower(node.Tag)
+		}
+		// source positions refer to the contents of the other DOM
+		node.sourceOffset, node.sourceEnd, node.sourceLine, node.sourceColumn = 0, 0, 0, 0
+	}
+
+	return result
+}
+
+//
+// MergeInto : Append copies of the content of src to target, a node of this
+// DOM. A parsed HTML document contributes the text and children of its body,
+// any other the top level elements. Comments are not copied. The imported
+// nodes are returned in document order and src is unchanged.
+//
+func (id *DOM) MergeInto(target *DOMNode, src *DOM) (result []*DOMNode, err error) {
+	if target == nil || target.dom != id {
+		return nil, ErrNotChild
+	}
+	if id.frozen {
+		return nil, ErrFrozen
+	}
+
+	var body *DOMNode
+	if !src.xml {
+		if bodies := src.Find("body", nil); len(bodies) > 0 {
+			body = bodies[0]
+		}
+	}
+
+	// the copies are appended together to update the DOM once
+	position := len(target.Children)
+	if body != nil {
+		var texts []string
+		var textIndex []int
+		body.eachContent(func(text string) {
+			texts = append(texts, text)
+			textIndex = append(textIndex, position+len(result))
+		}, func(child *DOMNode) {
+			result = append(result, id.ImportNode(child, true))
+		})
+		if err = target.insertDetached(position, result); err != nil {
+			return nil, err
+		}
+		if len(texts) > 0 {
+			target.TextFragments = append(target.TextFragments, texts...)
+			target.textIndex = append(target.textIndex, textIndex...)
+			target.dirty = true
+		}
+		return result, nil
+	}
+
+	for _, child := range src.document {
+		if child.Parent != nil || pseudoTags[child.Tag] == 1 {
+			continue
+		}
+		result = append(result, id.ImportNode(child, true))
+	}
+	if err = target.insertDetached(position, result); err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
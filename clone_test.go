@@ -0,0 +1,95 @@
+// Copyright 2016, Marc Lavergne <mlavergn@gmail.com>. All rights reserved.
+// Use of this source code is governed by the MIT
+// license that can be found in the LICENSE file.
+
+package godom
+
+import (
+	"testing"
+)
+
+func TestClone(t *testing.T) {
+	d := NewDOM()
+	d.SetContents("<html><body><!-- note --><div id=\"a\">one <b>two</b></div></body></html>")
+	d.Freeze()
+
+	c := d.Clone()
+	checkIndexes(t, c)
+	if c.Frozen() || len(c.document) != len(d.document) {
+		t.Fatalf("failed to clone document")
+	}
+
+	div := c.Find("div", nil)
+	if len(div) != 1 || div[0] == d.Find("div", nil)[0] || div[0].dom != c {
+		t.Fatalf("failed to copy nodes")
+	}
+	div[0].SetAttr("id", "b")
+	err := div[0].RemoveChild(div[0].Children[0])
+	if err != nil {
+		t.Fatalf("failed to modify clone %v", err)
+	}
+	checkIndexes(t, c)
+	if d.Find("div", nil)[0].Attr("id") != "a" || len(d.Find("b", nil)) != 1 {
+		t.Errorf("failed to isolate original")
+	}
+
+	subtree := d.Find("div", nil)[0].CloneSubtree()
+	if subtree.Parent != nil || subtree.Index != 1 || subtree.Children[0].Index != 2 || subtree.Children[0].Parent != subtree {
+		t.Errorf("failed to clone subtree")
+	}
+	if subtree.OuterHTML() != d.Find("div", nil)[0].OuterHTML() {
+		t.Errorf("failed to copy subtree [%s]", subtree.OuterHTML())
+	}
+}
+
+func TestImportNode(t *testing.T) {
+	page := NewDOM()
+	page.SetContents(`<html><body><main id="main"></main></body></html>`)
+	widget := NewDOM()
+	widget.SetContents(`<html><body><div class="widget"><p>Hi <b>there</b></p></div></body></html>`)
+
+	source := widget.Find("div", nil)[0]
+	imported := page.ImportNode(source, true)
+	if imported.Parent != nil || imported.dom != nil || imported.Index != 1 || len(imported.Children) != 1 {
+		t.Fatalf("failed to import node")
+	}
+	shallow := page.ImportNode(widget.Find("p", nil)[0], false)
+	if len(shallow.Children) != 0 || shallow.Text() != "Hi" {
+		t.Errorf("failed to import shallow node")
+	}
+
+	main := page.Find("main", nil)[0]
+	if err := main.AppendChild(imported); err != nil {
+		t.Fatalf("failed to insert imported node %v", err)
+	}
+	checkIndexes(t, &page)
+	if len(page.Find("b", nil)) != 1 || len(widget.Find("b", nil)) != 1 || source.Parent == nil {
+		t.Errorf("failed to copy node between DOMs")
+	}
+}
+
+func TestMergeInto(t *testing.T) {
+	page := NewDOM()
+	page.SetContents(`<html><body><header></header><main></main></body></html>`)
+	fragment := NewDOM()
+	fragment.SetContents(`<html><body>Intro<p>One</p><!-- skipped -->and<p>Two</p>end</body></html>`)
+
+	main := page.Find("main", nil)[0]
+	nodes, err := page.MergeInto(main, &fragment)
+	if err != nil || len(nodes) != 2 {
+		t.Fatalf("failed to merge %v %d", err, len(nodes))
+	}
+	checkIndexes(t, &page)
+	if main.OuterHTML() != "<main>Intro<p>One</p>and<p>Two</p>end</main>" || len(page.Comments()) != 0 {
+		t.Errorf("unexpected merge %s", main.OuterHTML())
+	}
+	if nodes[0].dom != &page || page.Find("p", nil)[1] != nodes[1] || len(fragment.Find("p", nil)) != 2 {
+		t.Errorf("failed to own merged nodes")
+	}
+
+	other := NewDOM()
+	other.SetContents(`<html><body><div></div></body></html>`)
+	if _, err := page.MergeInto(other.Find("div", nil)[0], &fragment); err != ErrNotChild {
+		t.Errorf("expected foreign target error")
+	}
+}
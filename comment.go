@@ -0,0 +1,32 @@
+// Copyright 2016 Marc Lavergne <mlavergn@gmail.com>. All rights reserved.
+// Use of this source code is governed by
+// license that can be found in the LICENSE file.
+
+package godom
+
+//
+// Comments : The comment nodes of the document in document order, including
+// those outside of the root node. The comment text is the node Text().
+//
+func (id *DOM) Comments() (result []*DOMNode) {
+	for _, node := range id.document {
+		if node.Tag == "comment" {
+			result = append(result, node)
+		}
+	}
+
+	return result
+}
+
+//
+// ChildComments : The comment nodes within the parent in document order.
+//
+func (id *DOM) ChildComments(parent *DOMNode) (result []*DOMNode) {
+	for _, node := range id.Comments() {
+		if isAncestorNode(parent, node) {
+			result = append(result, node)
+		}
+	}
+
+	return result
+}
@@ -0,0 +1,43 @@
+// Copyright 2016, Marc Lavergne <mlavergn@gmail.com>. All rights reserved.
+// Use of this source code is governed by the MIT
+// license that can be found in the LICENSE file.
+
+package godom
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestComments(t *testing.T) {
+	contents := "<!-- top --><html><body><div><!-- {\"id\": 7} --><p>x</p></div><!-- end --></body></html>"
+	for _, indexed := range []bool{false, true} {
+		d := NewDOM()
+		d.SetParseOptions(ParseOptions{IndexComments: indexed})
+		d.SetContents(contents)
+
+		comments := d.Comments()
+		if len(comments) != 3 || comments[0].Text() != " top " || comments[1].Text() != " {\"id\": 7} " {
+			d.Dump()
+			t.Fatalf("failed to keep comment text [indexed %t]", indexed)
+		}
+		if nodes := d.ChildComments(d.Find("div", nil)[0]); len(nodes) != 1 || nodes[0] != comments[1] {
+			t.Errorf("failed to find child comments [indexed %t]", indexed)
+		}
+		if nodes := d.FindWithKey("comment", "\"id\""); len(nodes) != 1 || strings.TrimSpace(nodes[0].Text()) != "{\"id\": 7}" {
+			t.Errorf("failed to find comment with key [indexed %t]", indexed)
+		}
+		if len(d.Find("comment", nil)) != 2 {
+			t.Errorf("failed to find comments within the root [indexed %t]", indexed)
+		}
+		if comments[2].OuterHTML() != "<!-- end -->" {
+			t.Errorf("failed to serialize comment [%s]", comments[2].OuterHTML())
+		}
+	}
+
+	d := NewDOM()
+	d.SetContentsFromReader(strings.NewReader(contents))
+	if comments := d.Comments(); len(comments) != 3 || comments[2].Text() != " end " {
+		t.Errorf("failed to keep streamed comment text")
+	}
+}
@@ -0,0 +1,495 @@
+// Copyright 2016 Marc Lavergne <mlavergn@gmail.com>. All rights reserved.
+// Use of this source code is governed by
+// license that can be found in the LICENSE file.
+
+package godom
+
+import (
+	"strings"
+)
+
+// compactNone the position of a missing parent, child, or sibling
+const compactNone = -1
+
+// CompactDOM def
+// A DOM stored in flat slices indexed by document position rather than
+// linked nodes with an attribute map each. Position i holds the node of
+// Index i+1, pseudo nodes included. Parents, children, and siblings are
+// positions, -1 when missing. At and Root offer the nodes as CompactNode
+type CompactDOM struct {
+	contents string
+	charset  string
+	options  ParseOptions
+	xml      bool
+	// names are the distinct tags, tags the name of each node
+	names []string
+	tags  []int32
+	// parents, firstChildren, and nextSiblings link the element tree
+	parents       []int32
+	firstChildren []int32
+	nextSiblings  []int32
+	// lasts are the positions of the last node within each subtree
+	lasts []int32
+	// attrStarts and textStarts hold the offsets of each node into the shared
+	// attribute and text slices, with a final entry for the end
+	attrStarts []int32
+	attrKeys   []string
+	attrValues []string
+	textStarts []int32
+	texts      []string
+	// textIndexes are the child positions preceding each text fragment
+	textIndexes []int32
+	// preserveSpace is set for nodes whose text keeps its whitespace
+	preserveSpace []bool
+}
+
+//
+// ParseCompact : Parse the raw html contents with the options into a
+// CompactDOM, the linked nodes of the parse are dropped once stored.
+//
+func ParseCompact(htmlString string, opts ParseOptions) (*CompactDOM, error) {
+	dom := NewDOMWithArena()
+	dom.SetParseOptions(opts)
+	if err := dom.SetContents(htmlString); err != nil {
+		return nil, err
+	}
+
+	return dom.Compact(), nil
+}
+
+//
+// Compact : The DOM stored as a CompactDOM. Source positions, the dirty
+// state, and the preserved tag case of the nodes are not kept.
+//
+func (id *DOM) Compact() *CompactDOM {
+	count := len(id.document)
+	result := &CompactDOM{
+		contents:      id.contents,
+		charset:       id.charset,
+		options:       id.options,
+		xml:           id.xml,
+		tags:          make([]int32, count),
+		parents:       make([]int32, count),
+		firstChildren: make([]int32, count),
+		nextSiblings:  make([]int32, count),
+		lasts:         make([]int32, count),
+		attrStarts:    make([]int32, 0, count+1),
+		textStarts:    make([]int32, 0, count+1),
+		preserveSpace: make([]bool, count),
+	}
+
+	// siblings are linked from their parent, which precedes them
+	for i := range result.nextSiblings {
+		result.nextSiblings[i] = compactNone
+	}
+
+	names := map[string]int32{}
+	for i, node := range id.document {
+		name, ok := names[node.Tag]
+		if !ok {
+			name = int32(len(result.names))
+			names[node.Tag] = name
+			result.names = append(result.names, node.Tag)
+		}
+		result.tags[i] = name
+
+		result.parents[i] = compactNone
+		if node.Parent != nil {
+			result.parents[i] = int32(node.Parent.Index - 1)
+		}
+		result.firstChildren[i] = compactNone
+		if len(node.Children) > 0 {
+			result.firstChildren[i] = int32(node.Children[0].Index - 1)
+		}
+		for j := 1; j < len(node.Children); j++ {
+			result.nextSiblings[node.Children[j-1].Index-1] = int32(node.Children[j].Index - 1)
+		}
+
+		result.attrStarts = append(result.attrStarts, int32(len(result.attrKeys)))
+		for key, val := range node.Attributes {
+			result.attrKeys = append(result.attrKeys, key)
+			result.attrValues = append(result.attrValues, val)
+		}
+		result.textStarts = append(result.textStarts, int32(len(result.texts)))
+		for j, text := range node.TextFragments {
+			result.texts = append(result.texts, text)
+			position := len(node.Children)
+			if j < len(node.textIndex) {
+				position = node.textIndex[j]
+			}
+			result.textIndexes = append(result.textIndexes, int32(position))
+		}
+		result.preserveSpace[i] = node.preserveSpace
+	}
+	result.attrStarts = append(result.attrStarts, int32(len(result.attrKeys)))
+	result.textStarts = append(result.textStarts, int32(len(result.texts)))
+
+	// descendants follow their ancestors, so each subtree is complete once
+	// the positions after it are visited
+	for i := count - 1; i >= 0; i-- {
+		if result.lasts[i] < int32(i) {
+			result.lasts[i] = int32(i)
+		}
+		if parent := result.parents[i]; parent != compactNone && result.lasts[parent] < result.lasts[i] {
+			result.lasts[parent] = result.lasts[i]
+		}
+	}
+
+	return result
+}
+
+//
+// Len : The number of nodes, pseudo nodes included.
+//
+func (id *CompactDOM) Len() int {
+	return len(id.tags)
+}
+
+//
+// Tag : The tag of the node at position i.
+//
+func (id *CompactDOM) Tag(i int) string {
+	return id.names[id.tags[i]]
+}
+
+//
+// Parent : The position of the parent of the node at position i.
+//
+func (id *CompactDOM) Parent(i int) int {
+	return int(id.parents[i])
+}
+
+//
+// FirstChild : The position of the first child element of the node at
+// position i.
+//
+func (id *CompactDOM) FirstChild(i int) int {
+	return int(id.firstChildren[i])
+}
+
+//
+// NextSibling : The position of the element following the node at position i
+// under the same parent.
+//
+func (id *CompactDOM) NextSibling(i int) int {
+	return int(id.nextSiblings[i])
+}
+
+//
+// Attr : The value of the attribute key of the node at position i, and
+// whether it is set.
+//
+func (id *CompactDOM) Attr(i int, key string) (string, bool) {
+	for j := id.attrStarts[i]; j < id.attrStarts[i+1]; j++ {
+		if id.attrKeys[j] == key {
+			return id.attrValues[j], true
+		}
+	}
+
+	return "", false
+}
+
+//
+// Text : The text fragments of the node at position i joined as
+// DOMNode.Text() joins them.
+//
+func (id *CompactDOM) Text(i int) string {
+	return strings.Join(id.texts[id.textStarts[i]:id.textStarts[i+1]], " ")
+}
+
+//
+// Find : The positions of the elements of type tag with the specified
+// attributes in document order, matching as DOM.Find does across the whole
+// document.
+//
+func (id *CompactDOM) Find(tag string, attributes DOMNodeAttributes) []int {
+	return id.find(0, len(id.tags)-1, tag, attributes, 0)
+}
+
+//
+// CompactDOM: The positions from first to last of the nodes of type tag with
+// the attributes, at most limit unless 0.
+//
+func (id *CompactDOM) find(first int, last int, tag string, attributes DOMNodeAttributes, limit int) (result []int) {
+	if !id.xml {
+		// HTML tags are held in lowercase
+		tag = strings.ToLower(tag)
+	}
+	for i := first; i <= last; i++ {
+		name := id.Tag(i)
+		if pseudoTags[name] == 1 && name != tag {
+			continue
+		}
+		attr := func(key string) string {
+			val, _ := id.Attr(i, key)
+			return val
+		}
+		if (tag == "*" || name == tag) && matchAttributes(attributes, attr) {
+			result = append(result, i)
+			if len(result) == limit {
+				break
+			}
+		}
+	}
+
+	return result
+}
+
+//
+// Node : A detached copy of the node at position i without its parent or
+// children.
+//
+func (id *CompactDOM) Node(i int) *DOMNode {
+	node := id.node(i)
+	// the text of the node precedes its missing children
+	node.textIndex = make([]int, len(node.TextFragments))
+
+	return node
+}
+
+//
+// CompactDOM: The node at position i without links.
+//
+func (id *CompactDOM) node(i int) *DOMNode {
+	node := &DOMNode{
+		Index:         i + 1,
+		Tag:           id.Tag(i),
+		Attributes:    make(DOMNodeAttributes, id.attrStarts[i+1]-id.attrStarts[i]),
+		Children:      []*DOMNode{},
+		preserveSpace: id.preserveSpace[i],
+	}
+	for j := id.attrStarts[i]; j < id.attrStarts[i+1]; j++ {
+		node.Attributes[id.attrKeys[j]] = id.attrValues[j]
+	}
+	for j := id.textStarts[i]; j < id.textStarts[i+1]; j++ {
+		node.TextFragments = append(node.TextFragments, id.texts[j])
+		node.textIndex = append(node.textIndex, int(id.textIndexes[j]))
+	}
+	if !id.xml {
+		node.foldCase()
+	}
+
+	return node
+}
+
+//
+// DOM : The linked DOM of the stored nodes, offering the full DOM and DOMNode
+// API. The CompactDOM is unchanged.
+//
+func (id *CompactDOM) DOM() *DOM {
+	result := NewDOM()
+	result.contents = id.contents
+	result.charset = id.charset
+	result.xml = id.xml
+	result.SetParseOptions(id.options)
+
+	result.document = make([]*DOMNode, len(id.tags))
+	for i := range id.tags {
+		result.document[i] = id.node(i)
+		result.document[i].dom = &result
+	}
+	for i, node := range result.document {
+		if parent := id.parents[i]; parent != compactNone {
+			node.Parent = result.document[parent]
+		}
+		for child := id.firstChildren[i]; child != compactNone; child = id.nextSiblings[child] {
+			node.Children = append(node.Children, result.document[child])
+		}
+	}
+	result.reindex()
+
+	return &result
+}
+
+// CompactNode def
+// A node of a CompactDOM read from its slices, offering the DOMNode accessors
+// without building linked nodes. The zero value is no node, as returned for a
+// missing parent, child, or sibling
+type CompactNode struct {
+	dom      *CompactDOM
+	position int
+}
+
+//
+// At : The node at position i, the zero CompactNode when out of range.
+//
+func (id *CompactDOM) At(i int) CompactNode {
+	if i < 0 || i >= len(id.tags) {
+		return CompactNode{}
+	}
+
+	return CompactNode{dom: id, position: i}
+}
+
+//
+// Root : The root node as DOM.RootNode finds it, the html element or the
+// XML document element.
+//
+func (id *CompactDOM) Root() CompactNode {
+	for i := range id.tags {
+		name := id.Tag(i)
+		if (id.xml && id.parents[i] == compactNone && pseudoTags[name] == 0) || (!id.xml && name == "html") {
+			return id.At(i)
+		}
+	}
+
+	return CompactNode{}
+}
+
+//
+// IsZero : Is this the zero CompactNode rather than a node?
+//
+func (id CompactNode) IsZero() bool {
+	return id.dom == nil
+}
+
+//
+// Position : The position of the node in the CompactDOM.
+//
+func (id CompactNode) Position() int {
+	return id.position
+}
+
+//
+// Index : The Index of the node as in the DOM it was compacted from.
+//
+func (id CompactNode) Index() int {
+	return id.position + 1
+}
+
+//
+// Tag : The tag of the node.
+//
+func (id CompactNode) Tag() string {
+	return id.dom.Tag(id.position)
+}
+
+//
+// Attr : The value of the attribute key, empty when missing.
+//
+func (id CompactNode) Attr(key string) string {
+	val, _ := id.dom.Attr(id.position, key)
+	return val
+}
+
+//
+// HasAttr : Does the node carry the attribute key?
+//
+func (id CompactNode) HasAttr(key string) bool {
+	_, ok := id.dom.Attr(id.position, key)
+	return ok
+}
+
+//
+// Attributes : A copy of the attributes of the node.
+//
+func (id CompactNode) Attributes() DOMNodeAttributes {
+	first, last := id.dom.attrStarts[id.position], id.dom.attrStarts[id.position+1]
+	result := make(DOMNodeAttributes, last-first)
+	for j := first; j < last; j++ {
+		result[id.dom.attrKeys[j]] = id.dom.attrValues[j]
+	}
+
+	return result
+}
+
+//
+// Classes : The whitespace separated tokens of the class attribute.
+//
+func (id CompactNode) Classes() []string {
+	return strings.Fields(id.Attr("class"))
+}
+
+//
+// HasClass : Does the class attribute hold the class token?
+//
+func (id CompactNode) HasClass(class string) bool {
+	return matchAttributes(DOMNodeAttributes{"class": class}, id.Attr)
+}
+
+//
+// TextFragments : The text fragments of the node, sharing the storage of the
+// CompactDOM.
+//
+func (id CompactNode) TextFragments() []string {
+	return id.dom.texts[id.dom.textStarts[id.position]:id.dom.textStarts[id.position+1]:id.dom.textStarts[id.position+1]]
+}
+
+//
+// Text : The text fragments of the node joined as DOMNode.Text() joins them.
+//
+func (id CompactNode) Text() string {
+	return id.dom.Text(id.position)
+}
+
+//
+// Parent : The parent of the node, the zero CompactNode for a root.
+//
+func (id CompactNode) Parent() CompactNode {
+	return id.dom.At(id.dom.Parent(id.position))
+}
+
+//
+// FirstChild : The first child element of the node.
+//
+func (id CompactNode) FirstChild() CompactNode {
+	return id.dom.At(id.dom.FirstChild(id.position))
+}
+
+//
+// NextSibling : The element following the node under the same parent.
+//
+func (id CompactNode) NextSibling() CompactNode {
+	return id.dom.At(id.dom.NextSibling(id.position))
+}
+
+//
+// Children : The child elements of the node.
+//
+func (id CompactNode) Children() (result []CompactNode) {
+	for child := id.FirstChild(); !child.IsZero(); child = child.NextSibling() {
+		result = append(result, child)
+	}
+
+	return result
+}
+
+//
+// Find : The node and its descendants of type tag with the specified
+// attributes, matching as DOM.ChildFind does.
+//
+func (id CompactNode) Find(tag string, attributes DOMNodeAttributes) []CompactNode {
+	return id.nodes(id.dom.find(id.position, int(id.dom.lasts[id.position]), tag, attributes, 0))
+}
+
+//
+// FindFirst : The first node Find would return, the zero CompactNode when
+// there is none.
+//
+func (id CompactNode) FindFirst(tag string, attributes DOMNodeAttributes) CompactNode {
+	if found := id.dom.find(id.position, int(id.dom.lasts[id.position]), tag, attributes, 1); len(found) > 0 {
+		return id.dom.At(found[0])
+	}
+
+	return CompactNode{}
+}
+
+//
+// Node : A detached copy of the node without its parent or children, see
+// CompactDOM.Node.
+//
+func (id CompactNode) Node() *DOMNode {
+	return id.dom.Node(id.position)
+}
+
+//
+// CompactNode: The nodes of the positions.
+//
+func (id CompactNode) nodes(positions []int) []CompactNode {
+	result := make([]CompactNode, len(positions))
+	for i, position := range positions {
+		result[i] = id.dom.At(position)
+	}
+
+	return result
+}
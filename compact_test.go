@@ -0,0 +1,86 @@
+// Copyright 2016, Marc Lavergne <mlavergn@gmail.com>. All rights reserved.
+// Use of this source code is governed by the MIT
+// license that can be found in the LICENSE file.
+
+package godom
+
+import (
+	"testing"
+)
+
+func TestCompact(t *testing.T) {
+	contents := `<!DOCTYPE html><html><head><title>T</title></head><body><!-- c --><div id="a" class="x y">One<b>two</b>three</div><p class="y">Four</p></body></html>`
+	compact, err := ParseCompact(contents, ParseOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	divs := compact.Find("div", DOMNodeAttributes{"class": "y"})
+	if len(divs) != 1 || compact.Tag(divs[0]) != "div" || compact.Text(divs[0]) != "One three" {
+		t.Fatalf("unexpected matches %v", divs)
+	}
+	div := divs[0]
+	if upper := compact.Find("DIV", nil); len(upper) != 1 || upper[0] != div {
+		t.Errorf("failed to ignore the case of the HTML tag %v", upper)
+	}
+	if val, ok := compact.Attr(div, "id"); !ok || val != "a" {
+		t.Errorf("unexpected id %q", val)
+	}
+	if _, ok := compact.Attr(div, "title"); ok {
+		t.Error("found a missing attribute")
+	}
+	b := compact.FirstChild(div)
+	if compact.Tag(b) != "b" || compact.Parent(b) != div || compact.NextSibling(b) != -1 {
+		t.Errorf("unexpected links for %d", b)
+	}
+	if p := compact.NextSibling(div); compact.Tag(p) != "p" || len(compact.Find("*", DOMNodeAttributes{"class": "y"})) != 2 {
+		t.Errorf("unexpected sibling %d", p)
+	}
+	if comments := compact.Find("comment", nil); len(comments) != 1 || compact.Text(comments[0]) != " c " {
+		t.Errorf("unexpected comments %v", comments)
+	}
+
+	node := compact.Node(div)
+	if node.Index != div+1 || node.Attr("class") != "x y" || node.Parent != nil || len(node.Children) != 0 || node.Text() != "One three" {
+		t.Errorf("unexpected node %v", node)
+	}
+
+	d := NewDOM()
+	d.SetContents(contents)
+	expanded := compact.DOM()
+	if expanded.RootNode().OuterHTML() != d.RootNode().OuterHTML() || expanded.Contents() != contents {
+		t.Errorf("failed to expand %s", expanded.RootNode().OuterHTML())
+	}
+	if expanded.ByID("a") == nil || compact.Len() != len(expanded.document) {
+		t.Error("failed to index the expanded DOM")
+	}
+	checkIndexes(t, expanded)
+}
+
+func TestCompactNode(t *testing.T) {
+	compact, err := ParseCompact(`<html><body><div id="a" class="x y">One<b class="y">two</b></div><div><b>three</b></div></body></html>`, ParseOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	root := compact.Root()
+	if root.IsZero() || root.Tag() != "html" || !root.Parent().IsZero() {
+		t.Fatalf("unexpected root %v", root)
+	}
+	div := root.FindFirst("div", DOMNodeAttributes{"id": "a"})
+	if div.IsZero() || !div.HasClass("x") || div.HasClass("z") || div.Attr("id") != "a" || len(div.Attributes()) != 2 {
+		t.Fatalf("unexpected div %v", div)
+	}
+	if children := div.Children(); len(children) != 1 || children[0].Text() != "two" || children[0].Parent() != div {
+		t.Errorf("unexpected children %v", children)
+	}
+	if bs := div.Find("b", nil); len(bs) != 1 || len(root.Find("b", nil)) != 2 || len(root.Find("*", DOMNodeAttributes{"class": "y"})) != 2 {
+		t.Errorf("expected the find to stay within the subtree, got %v", bs)
+	}
+	if next := div.NextSibling(); next.Tag() != "div" || !next.NextSibling().IsZero() || next.FirstChild().Text() != "three" {
+		t.Errorf("unexpected sibling %v", next)
+	}
+	if !compact.At(-1).IsZero() || compact.At(div.Position()) != div || div.Index() != div.Node().Index {
+		t.Error("unexpected positions")
+	}
+}
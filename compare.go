@@ -0,0 +1,142 @@
+// Copyright 2016 Marc Lavergne <mlavergn@gmail.com>. All rights reserved.
+// Use of this source code is governed by
+// license that can be found in the LICENSE file.
+
+package godom
+
+import (
+	"sort"
+	"strings"
+)
+
+// CompareOptions def
+// Controls the comparison made by Equal, the zero value compares exactly
+type CompareOptions struct {
+	// NormalizeWhitespace collapses whitespace in attribute values and text,
+	// and ignores text made up of whitespace
+	NormalizeWhitespace bool
+	// UnorderedClasses compares the class attribute as a set of tokens
+	UnorderedClasses bool
+	// IgnoreText compares the element structure only
+	IgnoreText bool
+	// IgnoreAttributes lists the attributes left out of the comparison
+	IgnoreAttributes []string
+}
+
+// compareItem def
+// A text fragment or child in document order
+type compareItem struct {
+	text  string
+	child *DOMNode
+}
+
+//
+// Equal : Are the nodes of the same tag, with the same attributes, text, and
+// descendant structure? Comments are not compared. Attribute order never
+// matters as attributes are held in a map.
+//
+func (id *DOMNode) Equal(other *DOMNode, opts CompareOptions) bool {
+	if id == nil || other == nil {
+		return id == other
+	}
+
+	ignored := map[string]int{}
+	for _, key := range opts.IgnoreAttributes {
+		ignored[attrKey(key)] = 1
+	}
+
+	return id.equal(other, &opts, ignored)
+}
+
+//
+// DOMNode: Compare the nodes and their content.
+//
+func (id *DOMNode) equal(other *DOMNode, opts *CompareOptions, ignored map[string]int) bool {
+	if id.Tag != other.Tag {
+		return false
+	}
+	if !id.equalAttributes(other, opts, ignored) {
+		return false
+	}
+
+	items := id.compareItems(opts)
+	otherItems := other.compareItems(opts)
+	if len(items) != len(otherItems) {
+		return false
+	}
+	for i, item := range items {
+		switch {
+		case (item.child == nil) != (otherItems[i].child == nil):
+			return false
+		case item.child == nil && item.text != otherItems[i].text:
+			return false
+		case item.child != nil && !item.child.equal(otherItems[i].child, opts, ignored):
+			return false
+		}
+	}
+
+	return true
+}
+
+//
+// DOMNode: Compare the attributes not ignored under the options.
+//
+func (id *DOMNode) equalAttributes(other *DOMNode, opts *CompareOptions, ignored map[string]int) bool {
+	count := 0
+	for key, val := range id.Attributes {
+		if ignored[key] == 1 {
+			continue
+		}
+		count++
+		otherVal, ok := other.Attributes[key]
+		if !ok || compareValue(key, val, opts) != compareValue(key, otherVal, opts) {
+			return false
+		}
+	}
+
+	for key := range other.Attributes {
+		if ignored[key] == 0 {
+			count--
+		}
+	}
+
+	return count == 0
+}
+
+//
+// DOMNode: The text fragments and children compared under the options.
+//
+func (id *DOMNode) compareItems(opts *CompareOptions) (result []compareItem) {
+	id.eachContent(func(text string) {
+		if opts.IgnoreText {
+			return
+		}
+		if opts.NormalizeWhitespace {
+			text = strings.Join(strings.Fields(text), " ")
+			if len(text) == 0 {
+				return
+			}
+		}
+		result = append(result, compareItem{text: text})
+	}, func(child *DOMNode) {
+		result = append(result, compareItem{child: child})
+	})
+
+	return result
+}
+
+//
+// compareValue : The attribute value normalized under the options.
+//
+func compareValue(key string, val string, opts *CompareOptions) string {
+	if key == "class" && opts.UnorderedClasses {
+		classes := strings.Fields(val)
+		sort.Strings(classes)
+		return strings.Join(classes, " ")
+	}
+	if opts.NormalizeWhitespace {
+		return strings.Join(strings.Fields(val), " ")
+	}
+
+	return val
+}
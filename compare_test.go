@@ -0,0 +1,32 @@
+// Copyright 2016, Marc Lavergne <mlavergn@gmail.com>. All rights reserved.
+// Use of this source code is governed by the MIT
+// license that can be found in the LICENSE file.
+
+package godom
+
+import (
+	"testing"
+)
+
+func TestEqual(t *testing.T) {
+	d := NewDOM()
+	d.SetContents("<html><body><div class=\"a b\" id=\"x\">one <b>two</b></div><div class=\"b  a\" id=\"y\">one <b>two</b></div><div class=\"a b\">one <i>two</i></div></body></html>")
+	divs := d.Find("div", nil)
+	if len(divs) != 3 {
+		d.Dump()
+		t.Fatalf("failed to find nodes")
+	}
+
+	if !divs[0].Equal(divs[0].CloneSubtree(), CompareOptions{}) {
+		t.Errorf("failed to match clone")
+	}
+	if divs[0].Equal(divs[1], CompareOptions{}) {
+		t.Errorf("failed to detect attribute difference")
+	}
+	if !divs[0].Equal(divs[1], CompareOptions{UnorderedClasses: true, IgnoreAttributes: []string{"id"}}) {
+		t.Errorf("failed to match unordered classes")
+	}
+	if divs[0].Equal(divs[2], CompareOptions{IgnoreAttributes: []string{"id"}}) {
+		t.Errorf("failed to detect structure difference")
+	}
+}
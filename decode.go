@@ -0,0 +1,206 @@
+// Copyright 2016 Marc Lavergne <mlavergn@gmail.com>. All rights reserved.
+// Use of this source code is governed by
+// license that can be found in the LICENSE file.
+
+package godom
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// ErrDecodeTarget error returned when the decode target is not a pointer to a struct
+var ErrDecodeTarget = errors.New("godom: decode target must be a non-nil pointer to a struct")
+
+// decodeNodeType the type of a *DOMNode field
+var decodeNodeType = reflect.TypeOf((*DOMNode)(nil))
+
+//
+// Decode : Fill the struct pointed to by target from the DOM. Each field tagged
+// dom:"selector" takes the trimmed reader text of the first node matching the CSS
+// selector, or the attribute value with dom:"selector attr=name". An empty
+// selector names the node in scope. Fields may be strings, bools, numbers,
+// *DOMNode, structs decoded within the first match, and slices of these which
+// take every match. Fields without matches are left unchanged.
+//
+func (id *DOM) Decode(target interface{}) error {
+	root := id.RootNode()
+	if root == nil {
+		return decodeTarget(target, func(reflect.Value) error { return nil })
+	}
+
+	return decodeTarget(target, func(v reflect.Value) error {
+		return decodeStruct(root, true, v)
+	})
+}
+
+//
+// Decode : Fill the struct pointed to by target from the descendants of the
+// node, as DOM.Decode.
+//
+func (id *DOMNode) Decode(target interface{}) error {
+	return decodeTarget(target, func(v reflect.Value) error {
+		return decodeStruct(id, false, v)
+	})
+}
+
+//
+// decodeTarget : Check the target is a pointer to a struct before decoding it.
+//
+func decodeTarget(target interface{}, fn func(reflect.Value) error) error {
+	v := reflect.ValueOf(target)
+	if v.Kind() != reflect.Ptr || v.IsNil() || v.Elem().Kind() != reflect.Struct {
+		return ErrDecodeTarget
+	}
+
+	return fn(v.Elem())
+}
+
+//
+// decodeStruct : Decode the tagged fields of the struct from the scope, self is
+// set when the scope itself may match a selector.
+//
+func decodeStruct(scope *DOMNode, self bool, v reflect.Value) error {
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		tag, ok := field.Tag.Lookup("dom")
+		if !ok || tag == "-" || len(field.PkgPath) > 0 {
+			continue
+		}
+
+		selector, attr, err := parseDecodeTag(tag)
+		if err != nil {
+			return fmt.Errorf("godom: invalid dom tag %q on field %s", tag, field.Name)
+		}
+
+		err = decodeField(v.Field(i), decodeMatches(scope, self, selector), attr)
+		if err != nil {
+			return fmt.Errorf("godom: field %s: %v", field.Name, strings.TrimPrefix(err.Error(), "godom: "))
+		}
+	}
+
+	return nil
+}
+
+//
+// parseDecodeTag : Split the dom tag into its selector, nil when empty, and
+// the attribute of the attr= option.
+//
+func parseDecodeTag(tag string) (selector *Selector, attr string, err error) {
+	fields := strings.Fields(tag)
+	if len(fields) > 0 && strings.HasPrefix(fields[len(fields)-1], "attr=") {
+		attr = attrKey(strings.TrimPrefix(fields[len(fields)-1], "attr="))
+		fields = fields[:len(fields)-1]
+	}
+	if len(fields) > 0 {
+		selector, err = ParseSelector(strings.Join(fields, " "))
+	}
+
+	return selector, attr, err
+}
+
+//
+// decodeMatches : The nodes of the scope matching the selector in document
+// order, the scope itself for a nil selector.
+//
+func decodeMatches(scope *DOMNode, self bool, selector *Selector) (result []*DOMNode) {
+	if selector == nil {
+		return []*DOMNode{scope}
+	}
+
+	if self && selector.Match(scope) {
+		result = append(result, scope)
+	}
+	for node := range scope.Descendants() {
+		if selector.Match(node) {
+			result = append(result, node)
+		}
+	}
+
+	return result
+}
+
+//
+// decodeField : Decode the matches into the field, a slice takes every match
+// and any other type the first.
+//
+func decodeField(v reflect.Value, matches []*DOMNode, attr string) error {
+	if len(matches) == 0 {
+		return nil
+	}
+
+	if v.Kind() == reflect.Slice {
+		slice := reflect.MakeSlice(v.Type(), 0, len(matches))
+		for _, node := range matches {
+			elem := reflect.New(v.Type().Elem()).Elem()
+			if err := decodeValue(elem, node, attr); err != nil {
+				return err
+			}
+			slice = reflect.Append(slice, elem)
+		}
+		v.Set(slice)
+		return nil
+	}
+
+	return decodeValue(v, matches[0], attr)
+}
+
+//
+// decodeValue : Decode the node into the value.
+//
+func decodeValue(v reflect.Value, node *DOMNode, attr string) error {
+	switch {
+	case v.Type() == decodeNodeType:
+		v.Set(reflect.ValueOf(node))
+		return nil
+	case v.Kind() == reflect.Ptr:
+		if v.IsNil() {
+			v.Set(reflect.New(v.Type().Elem()))
+		}
+		return decodeValue(v.Elem(), node, attr)
+	case v.Kind() == reflect.Struct:
+		return decodeStruct(node, false, v)
+	}
+
+	text := strings.TrimSpace(node.ReaderText())
+	if len(attr) > 0 {
+		text = strings.TrimSpace(node.Attr(attr))
+	}
+
+	switch v.Kind() {
+	case reflect.String:
+		v.SetString(text)
+	case reflect.Bool:
+		val, err := strconv.ParseBool(text)
+		if err != nil {
+			return err
+		}
+		v.SetBool(val)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		val, err := strconv.ParseInt(text, 10, v.Type().Bits())
+		if err != nil {
+			return err
+		}
+		v.SetInt(val)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		val, err := strconv.ParseUint(text, 10, v.Type().Bits())
+		if err != nil {
+			return err
+		}
+		v.SetUint(val)
+	case reflect.Float32, reflect.Float64:
+		val, err := strconv.ParseFloat(text, v.Type().Bits())
+		if err != nil {
+			return err
+		}
+		v.SetFloat(val)
+	default:
+		return fmt.Errorf("godom: unsupported type %s", v.Type())
+	}
+
+	return nil
+}
@@ -0,0 +1,69 @@
+// Copyright 2016, Marc Lavergne <mlavergn@gmail.com>. All rights reserved.
+// Use of this source code is governed by the MIT
+// license that can be found in the LICENSE file.
+
+package godom
+
+import (
+	"testing"
+)
+
+func TestDecode(t *testing.T) {
+	d := NewDOM()
+	d.SetContents("<html><body><h1 data-id=\"7\">Catalog</h1>" +
+		"<div class=\"item\" data-sku=\"a1\"><span class=\"name\">Lamp</span><span class=\"price\">19.5</span><a href=\"/lamp\">more</a></div>" +
+		"<div class=\"item\" data-sku=\"b2\"><span class=\"name\">Desk</span><span class=\"price\">120</span><a>none</a></div>" +
+		"</body></html>")
+
+	type item struct {
+		SKU   string   `dom:"attr=data-sku"`
+		Name  string   `dom:"span.name"`
+		Price float64  `dom:"span.price"`
+		Link  string   `dom:"a[href] attr=href"`
+		Node  *DOMNode `dom:""`
+	}
+	var page struct {
+		Title   string   `dom:"h1"`
+		ID      int      `dom:"h1 attr=data-id"`
+		Items   []item   `dom:"div.item"`
+		First   *item    `dom:"div.item"`
+		Names   []string `dom:"div.item > span.name"`
+		Missing string   `dom:"table"`
+		Ignored string
+		skipped string `dom:"h1"`
+	}
+	page.Missing = "kept"
+
+	if err := d.Decode(&page); err != nil {
+		t.Fatalf("failed to decode [%v]", err)
+	}
+	if page.Title != "Catalog" || page.ID != 7 || page.Missing != "kept" || len(page.skipped) != 0 {
+		t.Errorf("failed to decode fields %+v", page)
+	}
+	if len(page.Items) != 2 || page.Items[0].SKU != "a1" || page.Items[0].Price != 19.5 || page.Items[0].Link != "/lamp" {
+		t.Fatalf("failed to decode struct slice %+v", page.Items)
+	}
+	if page.Items[1].Name != "Desk" || len(page.Items[1].Link) != 0 || page.Items[1].Node.Attr("data-sku") != "b2" {
+		t.Errorf("failed to decode second item %+v", page.Items[1])
+	}
+	if page.First == nil || page.First.Name != "Lamp" || len(page.Names) != 2 || page.Names[1] != "Desk" {
+		t.Errorf("failed to decode pointer and string slice")
+	}
+
+	var bad struct {
+		Title int `dom:"h1"`
+	}
+	if err := d.Decode(&bad); err == nil {
+		t.Errorf("failed to report unparsable number")
+	}
+	if err := d.Decode(page); err != ErrDecodeTarget {
+		t.Errorf("failed to reject non-pointer target")
+	}
+
+	var scoped struct {
+		Name string `dom:"span.name"`
+	}
+	if err := d.Find("div", nil)[1].Decode(&scoped); err != nil || scoped.Name != "Desk" {
+		t.Errorf("failed to decode within node")
+	}
+}
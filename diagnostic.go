@@ -0,0 +1,110 @@
+// Copyright 2016 Marc Lavergne <mlavergn@gmail.com>. All rights reserved.
+// Use of this source code is governed by
+// license that can be found in the LICENSE file.
+
+package godom
+
+import (
+	"fmt"
+	"unicode/utf8"
+)
+
+// diagnosticDataLength bytes of offending data kept by a Diagnostic
+const diagnosticDataLength = 256
+
+// DiagnosticKind type
+// The kind of parse anomaly a Diagnostic reports
+type DiagnosticKind int
+
+// DiagnosticKind values
+const (
+	// DiagnosticErrorNode an error node was added to the document
+	DiagnosticErrorNode DiagnosticKind = iota
+	// DiagnosticFragment text was parsed as escaped HTML
+	DiagnosticFragment
+	// DiagnosticFragmentError text could not be parsed as escaped HTML
+	DiagnosticFragmentError
+	// DiagnosticSkipped an element was left out by ParseOptions.SkipTags
+	DiagnosticSkipped
+	// DiagnosticStrayEndTag an end tag without an open element was ignored
+	DiagnosticStrayEndTag
+	// DiagnosticUnclosed an element was still open at the end of the contents
+	DiagnosticUnclosed
+)
+
+// optionalEndTags elements whose end tag may be left out
+var optionalEndTags = map[string]int{
+	"html": 1, "head": 1, "body": 1, "p": 1, "li": 1, "dt": 1, "dd": 1, "option": 1, "optgroup": 1,
+	"rb": 1, "rp": 1, "rt": 1, "rtc": 1, "caption": 1, "colgroup": 1, "thead": 1, "tbody": 1, "tfoot": 1,
+	"tr": 1, "td": 1, "th": 1,
+}
+
+// diagnosticNames the names of the diagnostic kinds
+var diagnosticNames = map[DiagnosticKind]string{
+	DiagnosticErrorNode:     "error node",
+	DiagnosticFragment:      "escaped HTML",
+	DiagnosticFragmentError: "escaped HTML error",
+	DiagnosticSkipped:       "skipped element",
+	DiagnosticStrayEndTag:   "stray end tag",
+	DiagnosticUnclosed:      "unclosed element",
+}
+
+// Diagnostic def
+// A parse anomaly, a sign the DOM may not reflect the contents as intended
+type Diagnostic struct {
+	Kind DiagnosticKind
+	// Node is the node concerned, or the parent of the text or skipped
+	// element, nil at the top level
+	Node *DOMNode
+	// Data is the offending text, tag, or error message, truncated to 256 bytes
+	Data string
+}
+
+//
+// String : A description of the diagnostic.
+//
+func (id Diagnostic) String() string {
+	if id.Node == nil {
+		return fmt.Sprintf("%s: %q", diagnosticNames[id.Kind], id.Data)
+	}
+
+	return fmt.Sprintf("%s in %s at %d: %q", diagnosticNames[id.Kind], id.Node.Tag, id.Node.Index, id.Data)
+}
+
+//
+// Diagnostics : The anomalies found by the parses of the DOM, in the order
+// found. The parse of a well formed document reports none.
+//
+func (id *DOM) Diagnostics() []Diagnostic {
+	return append([]Diagnostic(nil), id.diagnostics...)
+}
+
+//
+// DOM: Record the elements closed without their end tag, other than those
+// whose end tag is optional.
+//
+func (id *DOM) diagnoseUnclosed(nodes []*DOMNode) {
+	for _, node := range nodes {
+		if optionalEndTags[node.Tag] == 0 {
+			id.diagnose(DiagnosticUnclosed, node, node.Tag)
+		}
+	}
+}
+
+//
+// DOM: Record a parse anomaly.
+//
+func (id *DOM) diagnose(kind DiagnosticKind, node *DOMNode, data string) {
+	if len(data) > diagnosticDataLength {
+		end := diagnosticDataLength
+		for end > 0 && !utf8.RuneStart(data[end]) {
+			end--
+		}
+		data = data[:end]
+	}
+	diagnostic := Diagnostic{Kind: kind, Node: node, Data: data}
+	id.diagnostics = append(id.diagnostics, diagnostic)
+	if logger := id.Logger(); !silent(logger) {
+		logger.Debug("godom: parse diagnostic", "diagnostic", diagnostic.String())
+	}
+}
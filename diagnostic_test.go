@@ -0,0 +1,51 @@
+// Copyright 2016, Marc Lavergne <mlavergn@gmail.com>. All rights reserved.
+// Use of this source code is governed by the MIT
+// license that can be found in the LICENSE file.
+
+package godom
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestDiagnostics(t *testing.T) {
+	d := NewDOM()
+	d.SetContents("<html><body><p>fine</p><ul><li>one<li>two</ul></body></html>")
+	if diagnostics := d.Diagnostics(); len(diagnostics) != 0 {
+		t.Errorf("unexpected diagnostics %v", diagnostics)
+	}
+
+	d = NewDOM()
+	d.SetParseOptions(ParseOptions{SkipTags: []string{"svg"}})
+	d.SetContents("<html><body><div>&lt;b&gt;bold&lt;/b&gt;</div><svg></svg></body></html>")
+	diagnostics := d.Diagnostics()
+	if len(diagnostics) != 2 || diagnostics[0].Kind != DiagnosticFragment || diagnostics[0].Node.Tag != "div" || diagnostics[0].Data != "<b>bold</b>" {
+		t.Fatalf("unexpected tree diagnostics %v", diagnostics)
+	}
+	if diagnostics[1].Kind != DiagnosticSkipped || diagnostics[1].Data != "svg" || diagnostics[1].Node.Tag != "body" {
+		t.Errorf("unexpected skip diagnostic %v", diagnostics[1])
+	}
+	if diagnostics[0].String() != `escaped HTML in div at 5: "<b>bold</b>"` {
+		t.Errorf("unexpected description %s", diagnostics[0])
+	}
+
+	d = NewDOM()
+	d.SetContentsFromReader(strings.NewReader("<div><span>a</b><p>b<section>c</div>"))
+	diagnostics = d.Diagnostics()
+	if len(diagnostics) != 3 {
+		t.Fatalf("unexpected stream diagnostics %v", diagnostics)
+	}
+	if diagnostics[0].Kind != DiagnosticStrayEndTag || diagnostics[0].Data != "b" || diagnostics[0].Node.Tag != "span" {
+		t.Errorf("unexpected stray end tag %v", diagnostics[0])
+	}
+	if diagnostics[1].Kind != DiagnosticUnclosed || diagnostics[1].Node.Tag != "span" || diagnostics[2].Node.Tag != "section" {
+		t.Errorf("unexpected unclosed elements %v", diagnostics[1:])
+	}
+
+	long := strings.Repeat("é", 200)
+	d.diagnose(DiagnosticErrorNode, nil, long)
+	if data := d.Diagnostics()[3].Data; len(data) != 256 || !strings.HasPrefix(long, data) {
+		t.Errorf("failed to truncate data %d", len(data))
+	}
+}
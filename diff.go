@@ -0,0 +1,535 @@
+// Copyright 2016 Marc Lavergne <mlavergn@gmail.com>. All rights reserved.
+// Use of this source code is governed by
+// license that can be found in the LICENSE file.
+
+package godom
+
+import (
+	"errors"
+	"hash/fnv"
+	"sort"
+	"strconv"
+)
+
+// ErrChangePath the path of a change does not locate a node in the DOM
+var ErrChangePath = errors.New("godom: change path not found")
+
+// ChangeType type
+// The kind of difference reported by Diff
+type ChangeType int
+
+const (
+	// ChangeAdded a node and its subtree were added, Node is the node in b
+	ChangeAdded ChangeType = iota
+	// ChangeRemoved a node and its subtree were removed, Node is the node in a
+	ChangeRemoved
+	// ChangeMoved an unchanged node and its subtree moved from Path to Target
+	ChangeMoved
+	// ChangeAttributes the attributes of the node changed
+	ChangeAttributes
+	// ChangeText the text of the node, or its position among the children, changed
+	ChangeText
+)
+
+//
+// String : The name of the change type.
+//
+func (id ChangeType) String() string {
+	switch id {
+	case ChangeAdded:
+		return "added"
+	case ChangeRemoved:
+		return "removed"
+	case ChangeMoved:
+		return "moved"
+	case ChangeAttributes:
+		return "attributes"
+	case ChangeText:
+		return "text"
+	}
+
+	return "ChangeType(" + strconv.Itoa(int(id)) + ")"
+}
+
+// Change def
+// A difference between two documents. Paths are the child positions leading
+// from the root node, Path locates the node in a and Target locates it in b.
+type Change struct {
+	Type ChangeType
+	// Path is nil for added nodes
+	Path []int
+	// Target is nil for removed nodes
+	Target []int
+	// Node is the node in a, or in b when added
+	Node          *DOMNode
+	OldAttributes DOMNodeAttributes
+	NewAttributes DOMNodeAttributes
+	OldText       []string
+	NewText       []string
+	// textIndex positions NewText among the children
+	textIndex []int
+}
+
+//
+// Diff : Compute the changes turning the element tree of a into that of b.
+// Siblings are paired preferring identical subtrees, then by tag and id, and
+// unpaired subtrees found unchanged elsewhere are reported as moved. Comments
+// are not compared.
+//
+func Diff(a *DOM, b *DOM) []Change {
+	differ := &differ{hashes: map[*DOMNode]uint64{}}
+
+	rootA, rootB := a.RootNode(), b.RootNode()
+	switch {
+	case rootA == nil && rootB == nil:
+	case rootA != nil && rootB != nil && rootA.Tag == rootB.Tag:
+		differ.node(rootA, rootB)
+	default:
+		if rootA != nil {
+			differ.leftA = append(differ.leftA, rootA)
+		}
+		if rootB != nil {
+			differ.leftB = append(differ.leftB, rootB)
+		}
+	}
+	differ.unpaired()
+
+	return differ.changes
+}
+
+//
+// Apply : Apply the changes computed by Diff(a, b) to a DOM holding the
+// element tree of a, bringing it up to date with b. Added subtrees are
+// copied from b. The paths of every change are resolved before the DOM is
+// modified, an unresolvable insertion may leave the DOM partially updated.
+//
+func (id *DOM) Apply(changes []Change) error {
+	if id.frozen {
+		return ErrFrozen
+	}
+
+	// resolve the nodes against the unmodified tree
+	nodes := make([]*DOMNode, len(changes))
+	for i, change := range changes {
+		if change.Type == ChangeAdded {
+			continue
+		}
+		nodes[i] = id.pathNode(change.Path)
+		if nodes[i] == nil {
+			return ErrChangePath
+		}
+	}
+
+	// detach the removed and moved subtrees
+	inserts := []int{}
+	for i, change := range changes {
+		switch change.Type {
+		case ChangeAdded:
+			inserts = append(inserts, i)
+		case ChangeMoved, ChangeRemoved:
+			if change.Type == ChangeMoved {
+				inserts = append(inserts, i)
+			}
+			err := id.detachNode(nodes[i])
+			if err != nil {
+				return err
+			}
+		case ChangeAttributes:
+			nodes[i].Attributes = DOMNodeAttributes{}
+			for key, val := range change.NewAttributes {
+				nodes[i].Attributes[key] = val
+			}
+			nodes[i].dirty = true
+		}
+	}
+
+	// insert in target document order so that the preceding siblings and
+	// the parent of each target are in place
+	sort.SliceStable(inserts, func(i, j int) bool {
+		return comparePaths(changes[inserts[i]].Target, changes[inserts[j]].Target) < 0
+	})
+	for _, i := range inserts {
+		node := nodes[i]
+		if node == nil {
+			node = changes[i].Node.clone()
+		}
+		err := id.insertNode(changes[i].Target, node)
+		if err != nil {
+			return err
+		}
+	}
+
+	// text positions refer to the final children
+	for i, change := range changes {
+		if change.Type == ChangeText {
+			nodes[i].TextFragments = append([]string(nil), change.NewText...)
+			nodes[i].textIndex = append([]int(nil), change.textIndex...)
+			nodes[i].dirty = true
+		}
+	}
+
+	// attribute changes bypass the index maintenance of SetAttr
+	id.reindex()
+
+	return nil
+}
+
+//
+// DOM: The node at the child positions leading from the root node, or nil.
+//
+func (id *DOM) pathNode(path []int) *DOMNode {
+	node := id.RootNode()
+	for _, position := range path {
+		if node == nil || position < 0 || position >= len(node.Children) {
+			return nil
+		}
+		node = node.Children[position]
+	}
+
+	return node
+}
+
+//
+// DOM: Detach the node from its parent, or from the document when it is
+// the root node.
+//
+func (id *DOM) detachNode(node *DOMNode) error {
+	if node.Parent != nil {
+		return node.Parent.RemoveChild(node)
+	}
+	if node.dom == id {
+		id.removeDocumentNodes(id.subtreeNodes(node))
+		node.setDOM(nil)
+		id.reindex()
+	}
+
+	return nil
+}
+
+//
+// DOM: Insert the detached node at the path, an empty path makes it the
+// root node of a DOM without one.
+//
+func (id *DOM) insertNode(path []int, node *DOMNode) error {
+	if len(path) == 0 {
+		if id.RootNode() != nil {
+			return ErrChangePath
+		}
+		id.document = append(id.document, node.elementNodes()...)
+		node.setDOM(id)
+		id.reindex()
+		return nil
+	}
+
+	parent := id.pathNode(path[:len(path)-1])
+	position := path[len(path)-1]
+	if parent == nil || position < 0 || position > len(parent.Children) {
+		return ErrChangePath
+	}
+
+	return parent.insertChild(node, position)
+}
+
+//
+// comparePaths : Order the paths as their nodes are in document order.
+//
+func comparePaths(a []int, b []int) int {
+	for i := 0; i < len(a) && i < len(b); i++ {
+		if a[i] != b[i] {
+			return a[i] - b[i]
+		}
+	}
+
+	return len(a) - len(b)
+}
+
+// differ def
+// The state of a Diff
+type differ struct {
+	changes []Change
+	hashes  map[*DOMNode]uint64
+	// leftA and leftB are the subtrees left unpaired by the sibling matching
+	leftA []*DOMNode
+	leftB []*DOMNode
+}
+
+//
+// differ: Compare the paired nodes and their children.
+//
+func (id *differ) node(a *DOMNode, b *DOMNode) {
+	if !equalAttributes(a.Attributes, b.Attributes) {
+		id.changes = append(id.changes, Change{
+			Type:          ChangeAttributes,
+			Path:          nodePath(a),
+			Target:        nodePath(b),
+			Node:          a,
+			OldAttributes: a.Attributes,
+			NewAttributes: b.Attributes,
+		})
+	}
+	if !equalText(a, b) {
+		id.changes = append(id.changes, Change{
+			Type:      ChangeText,
+			Path:      nodePath(a),
+			Target:    nodePath(b),
+			Node:      a,
+			OldText:   a.TextFragments,
+			NewText:   b.TextFragments,
+			textIndex: b.textIndex,
+		})
+	}
+
+	id.children(a.Children, b.Children)
+}
+
+//
+// differ: Pair the children, preferring identical subtrees over siblings
+// which only share their tag and id.
+//
+func (id *differ) children(childrenA []*DOMNode, childrenB []*DOMNode) {
+	pairs := lcsPairs(len(childrenA), len(childrenB), func(i int, j int) int {
+		a, b := childrenA[i], childrenB[j]
+		switch {
+		case id.hash(a) == id.hash(b):
+			return 3
+		case a.Tag == b.Tag && a.Attr("id") == b.Attr("id"):
+			return 2
+		}
+		return 0
+	})
+
+	pairedA := map[int]bool{}
+	pairedB := map[int]bool{}
+	for _, pair := range pairs {
+		pairedA[pair[0]] = true
+		pairedB[pair[1]] = true
+		if a, b := childrenA[pair[0]], childrenB[pair[1]]; id.hash(a) != id.hash(b) {
+			id.node(a, b)
+		}
+	}
+	for i, node := range childrenA {
+		if !pairedA[i] {
+			id.leftA = append(id.leftA, node)
+		}
+	}
+	for j, node := range childrenB {
+		if !pairedB[j] {
+			id.leftB = append(id.leftB, node)
+		}
+	}
+}
+
+//
+// differ: Report the unpaired subtrees, pairing identical ones as moves.
+//
+func (id *differ) unpaired() {
+	candidates := map[uint64][]*DOMNode{}
+	for _, node := range id.leftA {
+		candidates[id.hash(node)] = append(candidates[id.hash(node)], node)
+	}
+
+	moved := map[*DOMNode]bool{}
+	for _, node := range id.leftB {
+		hash := id.hash(node)
+		if matches := candidates[hash]; len(matches) > 0 {
+			candidates[hash] = matches[1:]
+			moved[matches[0]] = true
+			id.changes = append(id.changes, Change{
+				Type:   ChangeMoved,
+				Path:   nodePath(matches[0]),
+				Target: nodePath(node),
+				Node:   matches[0],
+			})
+			continue
+		}
+		id.changes = append(id.changes, Change{
+			Type:   ChangeAdded,
+			Target: nodePath(node),
+			Node:   node,
+		})
+	}
+
+	for _, node := range id.leftA {
+		if !moved[node] {
+			id.changes = append(id.changes, Change{
+				Type: ChangeRemoved,
+				Path: nodePath(node),
+				Node: node,
+			})
+		}
+	}
+}
+
+//
+// differ: The memoized hash of the tag, attributes, text, and children of
+// the subtree.
+//
+func (id *differ) hash(node *DOMNode) uint64 {
+	if hash, ok := id.hashes[node]; ok {
+		return hash
+	}
+
+	h := fnv.New64a()
+	write := func(s string) {
+		h.Write([]byte(s))
+		h.Write([]byte{0})
+	}
+	write(node.Tag)
+	keys := make([]string, 0, len(node.Attributes))
+	for key := range node.Attributes {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		write(key)
+		write(node.Attributes[key])
+	}
+	for i, text := range node.TextFragments {
+		write(strconv.Itoa(node.textPosition(i)))
+		write(text)
+	}
+	for _, child := range node.Children {
+		write(strconv.FormatUint(id.hash(child), 16))
+	}
+
+	id.hashes[node] = h.Sum64()
+
+	return id.hashes[node]
+}
+
+//
+// nodePath : The child positions leading from the root to the node.
+//
+func nodePath(node *DOMNode) (result []int) {
+	for ; node.Parent != nil; node = node.Parent {
+		result = append(result, node.Parent.childPosition(node))
+	}
+	for i, j := 0, len(result)-1; i < j; i, j = i+1, j-1 {
+		result[i], result[j] = result[j], result[i]
+	}
+
+	return result
+}
+
+//
+// equalAttributes : Do the attribute maps hold the same keys and values?
+//
+func equalAttributes(a DOMNodeAttributes, b DOMNodeAttributes) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for key, val := range a {
+		if other, ok := b[key]; !ok || other != val {
+			return false
+		}
+	}
+
+	return true
+}
+
+//
+// equalText : Do the nodes hold the same text fragments at the same
+// positions among their children?
+//
+func equalText(a *DOMNode, b *DOMNode) bool {
+	if len(a.TextFragments) != len(b.TextFragments) {
+		return false
+	}
+	for i := range a.TextFragments {
+		if a.TextFragments[i] != b.TextFragments[i] || a.textPosition(i) != b.textPosition(i) {
+			return false
+		}
+	}
+
+	return true
+}
+
+//
+// lcsPairs : The index pairs of the common subsequence of two sequences of
+// lengths n and m with the highest total score, a score of 0 never pairs.
+// Hirschberg's method keeps the space linear in m.
+//
+func lcsPairs(n int, m int, score func(i int, j int) int) (result [][2]int) {
+	lcs := lcsScorer{score: score}
+	lcs.pairs(0, n, 0, m)
+
+	return lcs.result
+}
+
+// lcsScorer def
+// The pairs found by lcsPairs so far, in order
+type lcsScorer struct {
+	score  func(i int, j int) int
+	result [][2]int
+}
+
+//
+// lcsScorer: Pair a[aLo:aHi] with b[bLo:bHi], splitting a in half around the
+// position of b the best alignment passes through.
+//
+func (id *lcsScorer) pairs(aLo int, aHi int, bLo int, bHi int) {
+	if aLo == aHi || bLo == bHi {
+		return
+	}
+	if aHi-aLo == 1 {
+		best, bestJ := 0, -1
+		for j := bLo; j < bHi; j++ {
+			if s := id.score(aLo, j); s > best {
+				best, bestJ = s, j
+			}
+		}
+		if bestJ >= 0 {
+			id.result = append(id.result, [2]int{aLo, bestJ})
+		}
+		return
+	}
+
+	mid := (aLo + aHi) / 2
+	forward := id.totals(aLo, mid, bLo, bHi, false)
+	backward := id.totals(mid, aHi, bLo, bHi, true)
+	split, best := 0, -1
+	for k := range forward {
+		if total := forward[k] + backward[k]; total > best {
+			split, best = k, total
+		}
+	}
+	id.pairs(aLo, mid, bLo, bLo+split)
+	id.pairs(mid, aHi, bLo+split, bHi)
+}
+
+//
+// lcsScorer: The best scores of a[aLo:aHi] against each prefix of b[bLo:bHi],
+// b[bLo:bLo+k] at k, or against each suffix, b[bLo+k:bHi] at k, when reversed.
+//
+func (id *lcsScorer) totals(aLo int, aHi int, bLo int, bHi int, reversed bool) []int {
+	m := bHi - bLo
+	row := make([]int, m+1)
+	previous := make([]int, m+1)
+	for step := 0; step < aHi-aLo; step++ {
+		i := aLo + step
+		if reversed {
+			i = aHi - 1 - step
+		}
+		row, previous = previous, row
+		row[0], row[m] = 0, 0
+		for k := 1; k <= m; k++ {
+			if !reversed {
+				j := bLo + k - 1
+				row[k] = max(previous[k], row[k-1])
+				if s := id.score(i, j); s > 0 {
+					row[k] = max(row[k], previous[k-1]+s)
+				}
+				continue
+			}
+			// suffixes fill from the end
+			at := m - k
+			j := bLo + at
+			row[at] = max(previous[at], row[at+1])
+			if s := id.score(i, j); s > 0 {
+				row[at] = max(row[at], previous[at+1]+s)
+			}
+		}
+	}
+
+	return row
+}
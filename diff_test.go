@@ -0,0 +1,104 @@
+// Copyright 2016, Marc Lavergne <mlavergn@gmail.com>. All rights reserved.
+// Use of this source code is governed by the MIT
+// license that can be found in the LICENSE file.
+
+package godom
+
+import (
+	"math/rand"
+	"reflect"
+	"testing"
+)
+
+func TestDiff(t *testing.T) {
+	a := NewDOM()
+	a.SetContents("<html><body><div id=\"a\" class=\"x\">one</div><ul><li>1</li><li>2</li></ul><p>gone</p><span>moved</span></body></html>")
+	b := NewDOM()
+	b.SetContents("<html><body><span>moved</span><div id=\"a\" class=\"y\">two</div><ul><li>1</li><li>2</li><li>3</li></ul></body></html>")
+
+	changes := Diff(&a, &b)
+	found := map[ChangeType][]Change{}
+	for _, change := range changes {
+		found[change.Type] = append(found[change.Type], change)
+	}
+
+	if len(found[ChangeAttributes]) != 1 || found[ChangeAttributes][0].NewAttributes["class"] != "y" {
+		t.Errorf("failed to detect attribute change %v", changes)
+	}
+	if len(found[ChangeText]) != 1 || found[ChangeText][0].NewText[0] != "two" {
+		t.Errorf("failed to detect text change %v", changes)
+	}
+	if len(found[ChangeAdded]) != 1 || found[ChangeAdded][0].Node.Text() != "3" {
+		t.Errorf("failed to detect added node %v", changes)
+	}
+	if len(found[ChangeRemoved]) != 1 || found[ChangeRemoved][0].Node.Tag != "p" {
+		t.Errorf("failed to detect removed node %v", changes)
+	}
+	if len(found[ChangeMoved]) != 1 || !reflect.DeepEqual(found[ChangeMoved][0].Target, []int{1, 0}) {
+		t.Errorf("failed to detect moved node %v", changes)
+	}
+
+	if len(Diff(&a, &a)) != 0 {
+		t.Errorf("failed to match identical documents")
+	}
+}
+
+func TestApply(t *testing.T) {
+	a := NewDOM()
+	a.SetContents("<html><body><div id=\"a\" class=\"x\">one</div><ul><li>1</li><li>2</li></ul><p>gone</p><span>moved</span></body></html>")
+	b := NewDOM()
+	b.SetContents("<html><body><span>moved</span><div id=\"a\" class=\"y\">two<b>bold</b></div><ul><li>0</li><li>1</li><li>2</li></ul></body></html>")
+
+	err := a.Apply(Diff(&a, &b))
+	if err != nil {
+		t.Fatalf("failed to apply changes %v", err)
+	}
+	checkIndexes(t, &a)
+
+	if a.RootNode().OuterHTML() != b.RootNode().OuterHTML() {
+		t.Errorf("failed to patch document [%s]", a.RootNode().OuterHTML())
+	}
+	if len(Diff(&a, &b)) != 0 {
+		t.Errorf("failed to converge %v", Diff(&a, &b))
+	}
+}
+
+func TestLCSPairs(t *testing.T) {
+	random := rand.New(rand.NewSource(1))
+	for trial := 0; trial < 200; trial++ {
+		n, m := random.Intn(12), random.Intn(12)
+		scores := make([][]int, n)
+		for i := range scores {
+			scores[i] = make([]int, m)
+			for j := range scores[i] {
+				scores[i][j] = random.Intn(4)
+			}
+		}
+
+		// the quadratic table of the best suffix scores
+		totals := make([][]int, n+1)
+		for i := range totals {
+			totals[i] = make([]int, m+1)
+		}
+		for i := n - 1; i >= 0; i-- {
+			for j := m - 1; j >= 0; j-- {
+				totals[i][j] = max(totals[i+1][j], totals[i][j+1])
+				if scores[i][j] > 0 {
+					totals[i][j] = max(totals[i][j], totals[i+1][j+1]+scores[i][j])
+				}
+			}
+		}
+
+		pairs := lcsPairs(n, m, func(i int, j int) int { return scores[i][j] })
+		total := 0
+		for k, pair := range pairs {
+			if scores[pair[0]][pair[1]] == 0 || (k > 0 && (pair[0] <= pairs[k-1][0] || pair[1] <= pairs[k-1][1])) {
+				t.Fatalf("invalid pairs %v", pairs)
+			}
+			total += scores[pair[0]][pair[1]]
+		}
+		if total != totals[0][0] {
+			t.Fatalf("expected a score of %d, got %d for %v", totals[0][0], total, pairs)
+		}
+	}
+}
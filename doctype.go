@@ -0,0 +1,195 @@
+// Copyright 2016 Marc Lavergne <mlavergn@gmail.com>. All rights reserved.
+// Use of this source code is governed by
+// license that can be found in the LICENSE file.
+
+package godom
+
+import (
+	"strings"
+)
+
+// doctypeQuirkyIDs public identifier prefixes which trigger quirks mode
+var doctypeQuirkyIDs = []string{
+	"+//silmaril//dtd html pro v0r11 19970101//",
+	"-//advasoft ltd//dtd html 3.0 aswedit + extensions//",
+	"-//as//dtd html 3.0 aswedit + extensions//",
+	"-//ietf//dtd html 2.0 level 1//",
+	"-//ietf//dtd html 2.0 level 2//",
+	"-//ietf//dtd html 2.0 strict level 1//",
+	"-//ietf//dtd html 2.0 strict level 2//",
+	"-//ietf//dtd html 2.0 strict//",
+	"-//ietf//dtd html 2.0//",
+	"-//ietf//dtd html 2.1e//",
+	"-//ietf//dtd html 3.0//",
+	"-//ietf//dtd html 3.2 final//",
+	"-//ietf//dtd html 3.2//",
+	"-//ietf//dtd html 3//",
+	"-//ietf//dtd html level 0//",
+	"-//ietf//dtd html level 1//",
+	"-//ietf//dtd html level 2//",
+	"-//ietf//dtd html level 3//",
+	"-//ietf//dtd html strict level 0//",
+	"-//ietf//dtd html strict level 1//",
+	"-//ietf//dtd html strict level 2//",
+	"-//ietf//dtd html strict level 3//",
+	"-//ietf//dtd html strict//",
+	"-//ietf//dtd html//",
+	"-//metrius//dtd metrius presentational//",
+	"-//microsoft//dtd internet explorer 2.0 html strict//",
+	"-//microsoft//dtd internet explorer 2.0 html//",
+	"-//microsoft//dtd internet explorer 2.0 tables//",
+	"-//microsoft//dtd internet explorer 3.0 html strict//",
+	"-//microsoft//dtd internet explorer 3.0 html//",
+	"-//microsoft//dtd internet explorer 3.0 tables//",
+	"-//netscape comm. corp.//dtd html//",
+	"-//netscape comm. corp.//dtd strict html//",
+	"-//o'reilly and associates//dtd html 2.0//",
+	"-//o'reilly and associates//dtd html extended 1.0//",
+	"-//o'reilly and associates//dtd html extended relaxed 1.0//",
+	"-//softquad software//dtd hotmetal pro 6.0::19990601::extensions to html 4.0//",
+	"-//softquad//dtd hotmetal pro 4.0::19971010::extensions to html 4.0//",
+	"-//spyglass//dtd html 2.0 extended//",
+	"-//sq//dtd html 2.0 hotmetal + extensions//",
+	"-//sun microsystems corp.//dtd hotjava html//",
+	"-//sun microsystems corp.//dtd hotjava strict html//",
+	"-//w3c//dtd html 3 1995-03-24//",
+	"-//w3c//dtd html 3.2 draft//",
+	"-//w3c//dtd html 3.2 final//",
+	"-//w3c//dtd html 3.2//",
+	"-//w3c//dtd html 3.2s draft//",
+	"-//w3c//dtd html 4.0 frameset//",
+	"-//w3c//dtd html 4.0 transitional//",
+	"-//w3c//dtd html experimental 19960712//",
+	"-//w3c//dtd html experimental 970421//",
+	"-//w3c//dtd w3 html//",
+	"-//w3o//dtd w3 html 3.0//",
+	"-//webtechs//dtd mozilla html 2.0//",
+	"-//webtechs//dtd mozilla html//",
+}
+
+// Doctype def
+// The document type declaration, Quirks is set when browsers render the
+// document in quirks mode, which includes a missing declaration
+type Doctype struct {
+	Name     string
+	PublicID string
+	SystemID string
+	Quirks   bool
+}
+
+//
+// Doctype : The document type declaration, an empty Name when the document
+// has none.
+//
+func (id *DOM) Doctype() Doctype {
+	for _, node := range id.document {
+		if node.Tag == "doctype" {
+			result := Doctype{
+				Name:     node.Text(),
+				PublicID: node.Attributes["public"],
+				SystemID: node.Attributes["system"],
+			}
+			_, hasSystem := node.Attributes["system"]
+			result.Quirks = result.doctypeQuirks(hasSystem)
+			return result
+		}
+	}
+
+	return Doctype{Quirks: true}
+}
+
+//
+// Doctype: Does the declaration trigger quirks mode?
+//
+func (id Doctype) doctypeQuirks(hasSystem bool) bool {
+	if id.Name != "html" {
+		return true
+	}
+
+	public := strings.ToLower(id.PublicID)
+	switch public {
+	case "-//w3o//dtd w3 html strict 3.0//en//", "-/w3d/dtd html 4.0 transitional/en", "html":
+		return true
+	}
+	for _, prefix := range doctypeQuirkyIDs {
+		if strings.HasPrefix(public, prefix) {
+			return true
+		}
+	}
+	// these only trigger quirks mode without a system identifier
+	if !hasSystem && (strings.HasPrefix(public, "-//w3c//dtd html 4.01 frameset//") ||
+		strings.HasPrefix(public, "-//w3c//dtd html 4.01 transitional//")) {
+		return true
+	}
+
+	return strings.EqualFold(id.SystemID, "http://www.ibm.com/data/dtd/v11/ibmxhtml1-transitional.dtd")
+}
+
+//
+// parseDoctype : Split the contents of a doctype token into the lowercased
+// name and its public and system identifier attributes, as the tree
+// builder does.
+//
+func parseDoctype(s string) (name string, attrs DOMNodeAttributes) {
+	attrs = DOMNodeAttributes{}
+	s = strings.TrimSpace(s)
+	space := strings.IndexAny(s, " \t\n\f\r")
+	if space < 0 {
+		space = len(s)
+	}
+	name = strings.ToLower(s[:space])
+	s = strings.TrimSpace(s[space:])
+	if len(s) < 6 {
+		return name, attrs
+	}
+
+	key := strings.ToLower(s[:6])
+	s = s[6:]
+	for key == "public" || key == "system" {
+		s = strings.TrimSpace(s)
+		if len(s) == 0 || (s[0] != '"' && s[0] != '\'') {
+			break
+		}
+		quote := s[0]
+		s = s[1:]
+		end := strings.IndexByte(s, quote)
+		if end < 0 {
+			end = len(s)
+		}
+		attrs[key] = s[:end]
+		s = s[min(end+1, len(s)):]
+		if key == "public" {
+			key = "system"
+		} else {
+			key = ""
+		}
+	}
+
+	return name, attrs
+}
+
+//
+// Lang : The language of the document, from the lang or xml:lang attribute
+// of the root node or a Content-Language <meta http-equiv>.
+//
+func (id *DOM) Lang() string {
+	if root := id.RootNode(); root != nil {
+		for _, key := range []string{"lang", "xml:lang"} {
+			if lang := strings.TrimSpace(root.Attr(key)); len(lang) > 0 {
+				return lang
+			}
+		}
+	}
+
+	for _, node := range id.Find("meta", nil) {
+		if strings.EqualFold(strings.TrimSpace(node.Attr("http-equiv")), "content-language") {
+			// the first of a list of languages
+			lang, _, _ := strings.Cut(node.Attr("content"), ",")
+			if lang = strings.TrimSpace(lang); len(lang) > 0 {
+				return lang
+			}
+		}
+	}
+
+	return ""
+}
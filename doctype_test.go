@@ -0,0 +1,67 @@
+// Copyright 2016, Marc Lavergne <mlavergn@gmail.com>. All rights reserved.
+// Use of this source code is governed by the MIT
+// license that can be found in the LICENSE file.
+
+package godom
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestDoctype(t *testing.T) {
+	checks := []struct {
+		contents string
+		expected Doctype
+	}{
+		{"<!DOCTYPE html><html></html>", Doctype{Name: "html"}},
+		{"<!DOCTYPE HTML PUBLIC \"-//W3C//DTD HTML 4.01//EN\" \"http://www.w3.org/TR/html4/strict.dtd\"><html></html>",
+			Doctype{Name: "html", PublicID: "-//W3C//DTD HTML 4.01//EN", SystemID: "http://www.w3.org/TR/html4/strict.dtd"}},
+		{"<!DOCTYPE html PUBLIC \"-//W3C//DTD HTML 4.01 Transitional//EN\"><html></html>",
+			Doctype{Name: "html", PublicID: "-//W3C//DTD HTML 4.01 Transitional//EN", Quirks: true}},
+		{"<html></html>", Doctype{Quirks: true}},
+	}
+
+	for _, check := range checks {
+		for _, stream := range []bool{false, true} {
+			d := NewDOM()
+			if stream {
+				d.SetContentsFromReader(strings.NewReader(check.contents))
+			} else {
+				d.SetContents(check.contents)
+			}
+			if doctype := d.Doctype(); doctype != check.expected {
+				t.Errorf("unexpected doctype %+v for %s [stream %t]", doctype, check.contents, stream)
+			}
+
+			// the rendered declaration keeps the rendering mode
+			sb := strings.Builder{}
+			d.Render(&sb, RenderOptions{})
+			rendered := NewDOM()
+			rendered.SetContents(sb.String())
+			if doctype := rendered.Doctype(); doctype != check.expected {
+				t.Errorf("unexpected rendered doctype %+v for %s", doctype, sb.String())
+			}
+		}
+	}
+}
+
+func TestCharsetAndLang(t *testing.T) {
+	d := NewDOM()
+	d.SetContents("<html lang=\"fr-CA\"><head><meta charset=\"latin1\"></head></html>")
+	if d.Charset() != "windows-1252" || d.Lang() != "fr-CA" {
+		t.Errorf("unexpected charset or lang [%s] [%s]", d.Charset(), d.Lang())
+	}
+
+	d = NewDOM()
+	d.SetContents("<html><head><meta http-equiv=\"Content-Type\" content=\"text/html; charset=UTF-8\"><meta http-equiv=\"content-language\" content=\"de, en\"></head></html>")
+	if d.Charset() != "utf-8" || d.Lang() != "de" {
+		t.Errorf("unexpected meta charset or lang [%s] [%s]", d.Charset(), d.Lang())
+	}
+
+	d = NewDOM()
+	d.SetContents("<html></html>")
+	if len(d.Charset()) != 0 || len(d.Lang()) != 0 {
+		t.Errorf("unexpected charset or lang without declarations")
+	}
+}
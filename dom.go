@@ -5,12 +5,13 @@
 package godom
 
 import (
-	"encoding/json"
+	"context"
 	"fmt"
 	"golang.org/x/net/html"
 	"log"
+	"net/url"
+	"sort"
 	"strings"
-	"sync"
 )
 
 // DOMNodeAttributes map of strings keyed by strings
@@ -22,6 +23,13 @@ type JSONMap map[string]interface{}
 // JSONDelimiter type
 type JSONDelimiter [2]string
 
+// ScriptJSON def
+// A JSON payload found in a script
+type ScriptJSON struct {
+	Node  *DOMNode
+	Value JSONMap
+}
+
 // JSONArrayDelimiter type
 var JSONArrayDelimiter = JSONDelimiter{"[", "]"}
 
@@ -37,6 +45,34 @@ type DOMNode struct {
 	TextFragments []string
 	Parent        *DOMNode
 	Children      []*DOMNode
+	// textIndex is the child position preceding each text fragment
+	textIndex []int
+	// dom is the owning document, nil when detached
+	dom *DOM
+	// dirty is set when the node is modified after parse
+	dirty bool
+	// preserveSpace is set when the text of the node keeps its whitespace
+	preserveSpace bool
+	// name is the tag as written when it differs from Tag
+	name string
+	// foldAttrs is set when an attribute key has upper case, lookups then
+	// ignore case
+	foldAttrs bool
+	// depth is the number of ancestors, subtreeSize the descendant element
+	// count plus one, 0 until counted
+	depth       int
+	subtreeSize int
+	// textBefore is the number of text fragments of the parent preceding a
+	// comment, which is not one of its children
+	textBefore int
+	// last is the Index of the last document node within the subtree, so
+	// the descendants are numbered from Index to last
+	last int
+	// source positions of the start tag, the line is 0 when unknown
+	sourceOffset int
+	sourceEnd    int
+	sourceLine   int
+	sourceColumn int
 }
 
 //
@@ -69,11 +105,20 @@ func (id *DOMNode) String() (desc string) {
 	return desc
 }
 
+//
+// appendText : Append a text fragment after the current children.
+//
+func (id *DOMNode) appendText(text string) {
+	id.TextFragments = append(id.TextFragments, text)
+	id.textIndex = append(id.textIndex, len(id.Children))
+}
+
 //
 // Attr Node: String with value of the provided attribute key.
 //
 func (id *DOMNode) Attr(key string) string {
-	return id.Attributes[key]
+	_, val, _ := id.lookupAttr(key)
+	return val
 }
 
 // Text export
@@ -142,15 +187,60 @@ type DOM struct {
 	nodes     map[string][]*DOMNode
 	rootNode  *DOMNode
 	nodeCount int
+	// parseErr is the first fragment error of the current parse
+	parseErr error
+	frozen   bool
+	charset  string
+	url      *url.URL
+	// baseURL is set by SetBaseURL, overriding the document base
+	baseURL *url.URL
+	options ParseOptions
+	// tags are the tag sets of the options
+	tags parseTags
+	// arena is nil unless created with NewDOMWithArena
+	arena *nodeArena
+	// xml is set once parsed by SetContentsXML, tags keep their case
+	xml bool
+	// ids indexes the first element carrying each id
+	ids map[string]*DOMNode
+	// attrs indexes the elements by attribute name, nil unless enabled
+	attrs map[string][]*DOMNode
+	// styles are the rules of the <style> elements, nil until needed
+	styles []*StyleRule
+	// externalStyles are the rules added by AddStylesheet
+	externalStyles []*StyleRule
+	// limiter enforces the context and limits of the current parse
+	limiter *parseLimiter
+	// diagnostics are the anomalies found by the parses
+	diagnostics []Diagnostic
+	// numbered is set while the subtree intervals of the nodes are current
+	numbered bool
+	// source is the DOM sharing its nodes with a scoped view
+	source *DOM
+	// findCache holds the Find results, nil unless enabled
+	findCache map[findKey][]*DOMNode
+	// logger receives the messages of the DOM, nil for the package Logger
+	logger Logger
 }
 
 //
 // NewDOM Constructor
+// The options apply in order, a DOM without options parses with the zero
+// ParseOptions.
 //
-func NewDOM() DOM {
-	return DOM{
+func NewDOM(opts ...Option) DOM {
+	result := DOM{
 		nodes: map[string][]*DOMNode{},
+		ids:   map[string]*DOMNode{},
 	}
+	if len(opts) > 0 {
+		for _, opt := range opts {
+			opt(&result)
+		}
+		result.SetParseOptions(result.options)
+	}
+
+	return result
 }
 
 //
@@ -166,16 +256,11 @@ func (id *DOM) String() (result string) {
 
 //
 // SetContents : parse the raw html contents.
+// A failure to parse escaped HTML embedded in text is reported once the
+// rest of the document has been parsed.
 //
-func (id *DOM) SetContents(htmlString string) {
-	id.contents = htmlString
-
-	doc, err := html.Parse(strings.NewReader(htmlString))
-	if err != nil {
-		log.Println(err)
-		return
-	}
-	id.parseHTMLNode(nil, doc, false)
+func (id *DOM) SetContents(htmlString string) error {
+	return id.SetContentsContext(context.Background(), htmlString)
 }
 
 //
@@ -196,11 +281,18 @@ func (id *DOM) ContentLength() int {
 // RootNode : The HTML root node
 //
 func (id *DOM) RootNode() (result *DOMNode) {
-	if id.rootNode == nil {
+	// a frozen DOM is read-only so the memo is never written
+	if id.rootNode == nil && !id.frozen {
 		// we're looking for the tidy-ed HTML node at index 1
 		// there's the childless DOCUMENT node at index 0
 		for i := 0; i < len(id.document); i++ {
-			if id.document[i].Tag == "html" {
+			if id.xml {
+				// the XML root is the document element
+				if id.document[i].Parent == nil && pseudoTags[id.document[i].Tag] == 0 {
+					id.rootNode = id.document[i]
+					break
+				}
+			} else if id.document[i].Tag == "html" {
 				id.rootNode = id.document[i]
 			}
 		}
@@ -210,104 +302,313 @@ func (id *DOM) RootNode() (result *DOMNode) {
 }
 
 //
-// Dump : dump the textual representation of the DOM
+// Freeze : Finalize the lazily built state of the DOM. Once frozen, the DOM
+// rejects parsing and tree mutation, and any number of goroutines may query
+// it concurrently. The attribute setters then leave the nodes unchanged.
+//
+func (id *DOM) Freeze() {
+	id.RootNode()
+	id.styleRules()
+	id.number()
+	id.frozen = true
+}
+
+//
+// Frozen : Has the DOM been frozen for concurrent queries?
+//
+func (id *DOM) Frozen() bool {
+	return id.frozen
+}
+
+//
+// Reset : Clear the parsed contents so the DOM can parse another document,
+// keeping the allocated capacity of its document and indexes along with its
+// parse options and the stylesheets added by AddStylesheet. The nodes of the
+// previous parse are detached, and a frozen DOM is thawed once no goroutine
+// queries it. A scoped view only clears its own indexes, the nodes remain
+// attached to the DOM they were parsed into.
+//
+func (id *DOM) Reset() {
+	for i, node := range id.document {
+		if id.source == nil {
+			node.dom = nil
+		}
+		id.document[i] = nil
+	}
+	id.document = id.document[:0]
+	if id.nodes == nil {
+		id.nodes = map[string][]*DOMNode{}
+	}
+	for tag, nodes := range id.nodes {
+		id.nodes[tag] = clearNodes(nodes)
+	}
+	if id.ids == nil {
+		id.ids = map[string]*DOMNode{}
+	}
+	for key := range id.ids {
+		delete(id.ids, key)
+	}
+	for key, nodes := range id.attrs {
+		id.attrs[key] = clearNodes(nodes)
+	}
+	if id.arena != nil {
+		// retained nodes keep their chunks
+		id.arena = &nodeArena{}
+	}
+
+	id.contents = ""
+	id.rootNode = nil
+	id.nodeCount = 0
+	id.parseErr = nil
+	id.frozen = false
+	id.charset = ""
+	id.url = nil
+	id.baseURL = nil
+	id.xml = false
+	id.styles = nil
+	id.limiter = nil
+	id.diagnostics = id.diagnostics[:0]
+	id.numbered = false
+	id.source = nil
+	id.invalidateFinds()
+}
+
+//
+// clearNodes : The empty slice of nodes keeping its capacity, the entries
+// are cleared so the nodes can be collected.
+//
+func clearNodes(nodes []*DOMNode) []*DOMNode {
+	for i := range nodes {
+		nodes[i] = nil
+	}
+
+	return nodes[:0]
+}
+
+//
+// Dump : dump the textual representation of the DOM to the standard log, or
+// to the debug level of the Logger when one is set, see Tree for a readable
+// indented form
 //
 func (id *DOM) Dump() {
-	log.Println(id.document)
+	logger := id.Logger()
+	if silent(logger) {
+		log.Println(id.document)
+		return
+	}
+	logger.Debug("godom: dump", "document", fmt.Sprint(id.document))
 }
 
 //
 // DOM: Parse the Token attributes into a map.
 //
 func (id *DOM) parseHTMLNodeAttributes(node *html.Node) (attrs DOMNodeAttributes) {
-	attrs = make(DOMNodeAttributes)
+	return id.parseHTMLAttributes(node.Attr)
+}
+
+//
+// DOM: Parse the []html.Attribute into a map.
+//
+func (id *DOM) parseHTMLAttributes(htmlAttrs []html.Attribute) (attrs DOMNodeAttributes) {
+	attrs = make(DOMNodeAttributes, len(htmlAttrs))
 
 	// NOTE: keys never have whitespace once parsed / values (even IDs) retain whitespace
 	// parse the []html.Attribute into a hashmap
-	for _, attr := range node.Attr {
-		attrs[attr.Key] = attr.Val
+	for _, attr := range htmlAttrs {
+		attrs[attr.Key] = id.entities(attr.Val, true)
 	}
 
 	return attrs
 }
 
 //
-// DOM: Parse the Token attributes into a map.
+// DOM: Create a node and append it to the document.
 //
-func (id *DOM) parseHTMLFragment(parent *DOMNode, current *html.Node, contents string) {
+func (id *DOM) addNode(parent *DOMNode, tag string, attributes DOMNodeAttributes) *DOMNode {
+	id.nodeCount++
+	var domNode *DOMNode
+	if id.arena != nil {
+		domNode = id.arena.node()
+	} else {
+		domNode = &DOMNode{}
+	}
+	*domNode = NewDOMNode(id.nodeCount, parent, tag, attributes)
+	if id.xml {
+		domNode.Tag = tag
+	}
+	domNode.dom = id
+	id.numbered = false
+	id.invalidateFinds()
+	if parent != nil {
+		domNode.depth = parent.depth + 1
+		domNode.textBefore = len(parent.TextFragments)
+	}
+	id.document = append(id.document, domNode)
+
+	return domNode
+}
+
+//
+// DOM: Create an element node, link it to its parent, and index it by tag.
+//
+func (id *DOM) addElementNode(parent *DOMNode, tag string, attributes DOMNodeAttributes) *DOMNode {
+	domNode := id.addNode(parent, tag, attributes)
+	// set the children
+	if parent != nil {
+		parent.Children = append(parent.Children, domNode)
+	}
+	domNode.preserveSpace = id.tags.preserve[domNode.Tag] == 1 || (parent != nil && parent.preserveSpace)
+	domNode.foldCase()
+	if domNode.Tag == "style" {
+		id.styles = nil
+	}
+	id.indexNode(domNode)
+
+	return domNode
+}
+
+//
+// DOM: The candidate nodes of the tag in document order, every element for
+// * and the comments when they are not indexed.
+//
+func (id *DOM) tagNodes(tag string) []*DOMNode {
+	switch {
+	case tag == "*":
+		return elementsOf(id.document)
+	case tag == "comment" && !id.options.IndexComments:
+		return id.Comments()
+	case !id.xml:
+		// HTML tags are indexed in lowercase
+		tag = strings.ToLower(tag)
+	}
+
+	return id.nodes[tag]
+}
+
+//
+// DOMNode: Is the node of type tag? HTML tags ignore case as the tag index
+// does, XML tags do not.
+//
+func (id *DOMNode) isTag(tag string) bool {
+	if id.dom != nil && id.dom.xml {
+		return id.Tag == tag
+	}
+
+	return strings.EqualFold(id.Tag, tag)
+}
+
+//
+// DOM: Add the node to the tag index.
+//
+func (id *DOM) indexNode(domNode *DOMNode) {
+	nodeArr := id.nodes[domNode.Tag]
+	if nodeArr != nil {
+		id.nodes[domNode.Tag] = append(nodeArr, domNode)
+	} else {
+		id.nodes[domNode.Tag] = []*DOMNode{domNode}
+	}
+	id.indexID(domNode)
+	id.indexAttributes(domNode)
+}
+
+//
+// DOM: Parse the escaped HTML contents of a text node as a fragment.
+//
+func (id *DOM) parseHTMLFragment(parent *DOMNode, current *html.Node, contents string) error {
 	nodes, err := html.ParseFragment(strings.NewReader(contents), current)
-	if err == nil {
-		for _, node := range nodes {
-			id.parseHTMLNode(parent, node, true)
-		}
+	if err != nil {
+		return err
 	}
+	for _, node := range nodes {
+		id.parseHTMLNode(parent, node, true)
+	}
+
+	return nil
 }
 
+// fragmentSkipTags elements implied by parsing a fragment
+var fragmentSkipTags = map[string]int{"html": 1, "head": 1, "body": 1}
+
 //
 // DOM: Walk the DOM and parse the HTML tokens into Nodes.
 //
-var (
-	parseSkipTags    map[string]int
-	fragmentSkipTags map[string]int
-	once             sync.Once
-)
-
 func (id *DOM) parseHTMLNode(parent *DOMNode, current *html.Node, fragment bool) {
-	// constant candidates
-	once.Do(func() {
-		parseSkipTags = map[string]int{"script": 1, "style": 1, "body": 1}
-		fragmentSkipTags = map[string]int{"html": 1, "head": 1, "body": 1}
-	})
+	if id.limiter != nil && id.limiter.err != nil {
+		return
+	}
 
 	switch current.Type {
 	case html.ElementNode:
+		if id.tags.skip[current.Data] == 1 {
+			id.diagnose(DiagnosticSkipped, parent, current.Data)
+			return
+		}
 		if !fragment || (fragment && fragmentSkipTags[current.Data] == 0) {
-			id.nodeCount++
-			domNode := NewDOMNode(id.nodeCount, parent, current.Data, id.parseHTMLNodeAttributes(current))
-			// set the children and swap
-			if parent != nil {
-				parent.Children = append(parent.Children, &domNode)
+			if id.limiter != nil && !id.limiter.element(id.limiter.childDepth(parent)) {
+				return
 			}
-			parent = &domNode
-			id.document = append(id.document, &domNode)
-			nodeArr := id.nodes[domNode.Tag]
-			if nodeArr != nil {
-				id.nodes[domNode.Tag] = append(nodeArr, &domNode)
-			} else {
-				id.nodes[domNode.Tag] = []*DOMNode{&domNode}
+			// swap in the new node as the parent of the subtree
+			parent = id.addElementNode(parent, current.Data, id.parseHTMLNodeAttributes(current))
+			if id.options.PreserveCase && current.Data != parent.Tag {
+				// the adjusted name of a foreign element, eg. foreignObject
+				parent.name = current.Data
+			}
+			if id.arena != nil {
+				size := 0
+				for child := current.FirstChild; child != nil; child = child.NextSibling {
+					if child.Type == html.ElementNode {
+						size++
+					}
+				}
+				parent.Children = id.arena.childSlice(size)
 			}
 		}
 	case html.TextNode:
-		text := strings.TrimSpace(current.Data)
-		if strings.Index(text, "<") != -1 && (current.Parent == nil || parseSkipTags[current.Parent.Data] == 0) {
-			id.parseHTMLFragment(parent, current.Parent, text)
+		if current.Parent != nil && id.discardText(current.Parent.Data) {
+			return
+		}
+		text := parseText(parent, current.Data)
+		if id.limiter != nil && !id.limiter.text(len(text)) {
+			return
+		}
+		if id.escapedHTML(text) && (current.Parent == nil || !id.keepsText(current.Parent.Data)) {
+			id.diagnose(DiagnosticFragment, parent, text)
+			err := id.parseHTMLFragment(parent, current.Parent, text)
+			if err != nil {
+				id.diagnose(DiagnosticFragmentError, parent, err.Error())
+				if id.parseErr == nil {
+					id.parseErr = err
+				}
+			}
 		} else {
 			// we need to handle structures like (eg. <div>foo<strong>baz</strong>bar</div>)
-			// Assumption: if the current node already has text, it belongs to the parent
-			currentNode := id.document[len(id.document)-1]
-			if currentNode != nil && len(currentNode.TextFragments) != 0 {
-				currentNode = currentNode.Parent
+			// the text belongs to the enclosing element when there is one
+			currentNode := parent
+			if currentNode == nil {
+				// Assumption: if the current node already has text, it belongs to the parent
+				currentNode = id.document[len(id.document)-1]
+				if currentNode != nil && len(currentNode.TextFragments) != 0 {
+					currentNode = currentNode.Parent
+				}
 			}
 			if currentNode != nil {
-				currentNode.TextFragments = append(currentNode.TextFragments, text)
+				currentNode.appendText(id.entities(text, current.Parent == nil || scriptTags[current.Parent.Data] == 0))
 			}
 		}
 	case html.CommentNode:
-		id.nodeCount++
-		domNode := NewDOMNode(id.nodeCount, parent, "comment", id.parseHTMLNodeAttributes(current))
-		id.document = append(id.document, &domNode)
+		comment := id.addNode(parent, "comment", id.parseHTMLNodeAttributes(current))
+		comment.appendText(id.entities(current.Data, false))
+		if id.options.IndexComments {
+			id.indexNode(comment)
+		}
 	case html.ErrorNode:
-		id.nodeCount++
-		domNode := NewDOMNode(id.nodeCount, parent, "error", id.parseHTMLNodeAttributes(current))
-		id.document = append(id.document, &domNode)
+		errorNode := id.addNode(parent, "error", id.parseHTMLNodeAttributes(current))
+		id.diagnose(DiagnosticErrorNode, errorNode, current.Data)
 	case html.DocumentNode:
-		id.nodeCount++
-		domNode := NewDOMNode(id.nodeCount, parent, "document", id.parseHTMLNodeAttributes(current))
-		id.document = append(id.document, &domNode)
+		id.addNode(parent, "document", id.parseHTMLNodeAttributes(current))
 	case html.DoctypeNode:
-		id.nodeCount++
-		domNode := NewDOMNode(id.nodeCount, parent, "doctype", id.parseHTMLNodeAttributes(current))
-		id.document = append(id.document, &domNode)
+		doctype := id.addNode(parent, "doctype", id.parseHTMLNodeAttributes(current))
+		doctype.appendText(current.Data)
 	}
 
 	// recurse for all child nodes
@@ -316,10 +617,32 @@ func (id *DOM) parseHTMLNode(parent *DOMNode, current *html.Node, fragment bool)
 	}
 }
 
+//
+// DOM: Number the subtree intervals of the nodes, the document holds each
+// subtree contiguously after its node so children fold into their parents
+// from the end.
+//
+func (id *DOM) number() {
+	// a frozen DOM is read-only so the intervals are never written
+	if id.numbered || id.frozen {
+		return
+	}
+	for _, node := range id.document {
+		node.last = node.Index
+	}
+	for i := len(id.document) - 1; i >= 0; i-- {
+		node := id.document[i]
+		if parent := node.Parent; parent != nil && parent.dom == id && parent.last < node.last {
+			parent.last = node.last
+		}
+	}
+	id.numbered = true
+}
+
 //
 // IsDescendantNode : Is node a descendant of parent?
-// The fastest confirmation is bottom up since the relationships are
-// one to many.
+// Nodes of the DOM compare their subtree intervals, others are confirmed
+// bottom up since the relationships are one to many.
 //
 func (id *DOM) IsDescendantNode(parent *DOMNode, node *DOMNode) (result bool) {
 	result = false
@@ -330,6 +653,8 @@ func (id *DOM) IsDescendantNode(parent *DOMNode, node *DOMNode) (result bool) {
 		result = false
 	} else if parent == node {
 		result = true
+	} else if id.intervals(parent) && node.dom == id.owner() {
+		result = parent.Index < node.Index && node.Index <= parent.last
 	} else {
 		// we would have matched above if parent and node were the root node
 		rootNode := id.RootNode()
@@ -346,6 +671,59 @@ func (id *DOM) IsDescendantNode(parent *DOMNode, node *DOMNode) (result bool) {
 	return result
 }
 
+//
+// DOM: The candidate nodes of the tag for a search within parent, within is
+// set when every candidate is known to be the parent or a descendant.
+//
+func (id *DOM) childTagNodes(parent *DOMNode, tag string) (tagNodes []*DOMNode, within bool) {
+	if parent == nil || !id.intervals(parent) {
+		return id.tagNodes(tag), parent == nil
+	}
+
+	// the elements of * are taken from the interval alone
+	tagNodes = id.document
+	if tag != "*" {
+		tagNodes = id.tagNodes(tag)
+	}
+
+	// the candidates are in document order, so the parent and its
+	// descendants are those numbered within its interval
+	first := sort.Search(len(tagNodes), func(i int) bool { return tagNodes[i].Index >= parent.Index })
+	last := sort.Search(len(tagNodes), func(i int) bool { return tagNodes[i].Index > parent.last })
+	if tag == "*" {
+		return elementsOf(tagNodes[first:last]), true
+	}
+
+	return tagNodes[first:last], true
+}
+
+//
+// elementsOf : The nodes other than pseudo nodes, eg. comments.
+//
+func elementsOf(nodes []*DOMNode) []*DOMNode {
+	result := make([]*DOMNode, 0, len(nodes))
+	for _, node := range nodes {
+		if pseudoTags[node.Tag] == 0 {
+			result = append(result, node)
+		}
+	}
+
+	return result
+}
+
+//
+// DOM: Are the subtree intervals of the DOM current for the node?
+//
+func (id *DOM) intervals(node *DOMNode) bool {
+	owner := id.owner()
+	if node == nil || node.dom != owner {
+		return false
+	}
+	owner.number()
+
+	return owner.numbered
+}
+
 //
 // IsChildNode : Is node a child of parent?
 // The fastest confirmation is bottom up since the relationships are
@@ -361,29 +739,66 @@ func (id *DOM) IsChildNode(parent *DOMNode, node *DOMNode) (result bool) {
 }
 
 //
-// Find : Find the Node of type tag with the specified attributes
+// Find : Find the Node of type tag with the specified attributes, the tag
+// "*" matches any element
 //
 func (id *DOM) Find(tag string, attributes DOMNodeAttributes) (result []*DOMNode) {
 	return id.ChildFind(id.RootNode(), tag, attributes)
 }
 
 //
-// ChildFind : Find the child Node of type tag with the specified attributes
+// ChildFind : Find the child Node of type tag with the specified attributes.
+// The class attribute matches when the node has all of the given classes.
+// The tag "*" matches any element.
 //
 func (id *DOM) ChildFind(parent *DOMNode, tag string, attributes DOMNodeAttributes) (result []*DOMNode) {
-	tagNodes := id.nodes[tag]
+	return id.ChildFindLimit(parent, tag, attributes, 0)
+}
+
+//
+// FindLimit : Find at most limit Nodes of type tag with the specified attributes,
+// a limit of 0 or less finds every Node
+//
+func (id *DOM) FindLimit(tag string, attributes DOMNodeAttributes, limit int) (result []*DOMNode) {
+	return id.ChildFindLimit(id.RootNode(), tag, attributes, limit)
+}
+
+//
+// ChildFindLimit : Find at most limit child Nodes of type tag with the specified
+// attributes, the scan stops once the limit is reached
+//
+func (id *DOM) ChildFindLimit(parent *DOMNode, tag string, attributes DOMNodeAttributes, limit int) (result []*DOMNode) {
+	if id.findCache == nil {
+		return id.childFindLimit(parent, tag, attributes, limit)
+	}
+
+	key := newFindKey(parent, tag, attributes, limit)
+	cached, ok := id.findCache[key]
+	if !ok {
+		cached = id.childFindLimit(parent, tag, attributes, limit)
+		// a frozen DOM is read-only so the cache is never written
+		if !id.frozen {
+			id.findCache[key] = cached
+		}
+	}
+
+	// callers own the result
+	return append(result, cached...)
+}
+
+//
+// DOM: Scan the candidates of ChildFindLimit.
+//
+func (id *DOM) childFindLimit(parent *DOMNode, tag string, attributes DOMNodeAttributes, limit int) (result []*DOMNode) {
+	tagNodes, within := id.childTagNodes(parent, tag)
 	for _, node := range tagNodes {
 		// found a matching tag
-		nodeAttrs := node.Attributes
-		found := true
-		for k, v := range attributes {
-			if nodeAttrs[k] != v {
-				found = false
-			}
-		}
-		if found {
-			if id.IsDescendantNode(parent, node) {
+		if node.matchAttributes(attributes) {
+			if within || id.IsDescendantNode(parent, node) {
 				result = append(result, node)
+				if len(result) == limit {
+					break
+				}
 			}
 		}
 	}
@@ -391,6 +806,27 @@ func (id *DOM) ChildFind(parent *DOMNode, tag string, attributes DOMNodeAttribut
 	return result
 }
 
+//
+// FindFirst : Find the first Node of type tag with the specified attributes,
+// nil if there is none
+//
+func (id *DOM) FindFirst(tag string, attributes DOMNodeAttributes) *DOMNode {
+	return id.ChildFindFirst(id.RootNode(), tag, attributes)
+}
+
+//
+// ChildFindFirst : Find the first child Node of type tag with the specified
+// attributes, nil if there is none
+//
+func (id *DOM) ChildFindFirst(parent *DOMNode, tag string, attributes DOMNodeAttributes) *DOMNode {
+	nodes := id.ChildFindLimit(parent, tag, attributes, 1)
+	if len(nodes) == 0 {
+		return nil
+	}
+
+	return nodes[0]
+}
+
 //
 // FindWithKey : Find the Node of type tag with text containing key
 //
@@ -402,7 +838,7 @@ func (id *DOM) FindWithKey(tag string, substring string) (result []*DOMNode) {
 // ChildFindWithKey : Find the child Node of type tag with text containing key
 //
 func (id *DOM) ChildFindWithKey(parent *DOMNode, tag string, substring string) (result []*DOMNode) {
-	tagNodes := id.nodes[tag]
+	tagNodes := id.tagNodes(tag)
 	for _, node := range tagNodes {
 		// found a matching tag
 		if id.IsDescendantNode(parent, node) {
@@ -459,52 +895,72 @@ func (id *DOM) ChildFindJSONForScriptWithKeyDelimiter(parent *DOMNode, substring
 
 	if len(nodes) > 0 {
 		contents := nodes[0].Text()
-		idx := strings.Index(contents, substring)
-		sub := contents[idx:]
-		idx = strings.Index(sub, delimiter[1])
-		if idx >= 0 {
-			sub = sub[:idx+1]
-		}
+		result, err = scriptJSON(contents[strings.Index(contents, substring):], delimiter)
+	}
 
-		// unmarshall is strict and wants complete JSON structures
-		if !strings.HasPrefix(sub, delimiter[0]) {
-			idx = strings.Index(sub, delimiter[0])
-			if idx > 0 {
-				sub = sub[idx:]
-			} else {
-				sub = delimiter[0] + sub + delimiter[1]
-			}
-		}
+	return
+}
 
-		// no newlines
-		sub = strings.Replace(sub, "\n", "", -1)
-		// no tabs
-		sub = strings.Replace(sub, "\t", "", -1)
-
-		bytes := []byte(sub)
-		err = json.Unmarshal(bytes, &result)
-		if err != nil {
-			if strings.HasPrefix(err.Error(), "invalid character ") {
-				// JSON improper escaping detected - need to split the string and tidy it
-				log.Println("Tidy JSON")
-				subtidy := delimiter[0]
-				entries := strings.Split(sub[1:len(sub)-1], ",")
-				for _, entry := range entries {
-					val := strings.Split(entry, ":")
-					subtidy += fmt.Sprintf("\"%s\": \"%s\",", strings.Trim(val[0], " '"), strings.Trim(val[1], " '"))
-				}
-				subtidy = subtidy[:len(subtidy)-1] + delimiter[1]
+//
+// FindAllJSONForScriptWithKey : Find the JSON following each occurrence of
+// substring in every script
+//
+func (id *DOM) FindAllJSONForScriptWithKey(substring string) ([]ScriptJSON, error) {
+	return id.ChildFindAllJSONForScriptWithKeyDelimiter(id.RootNode(), substring, JSONDictionaryDelimiter)
+}
+
+//
+// ChildFindAllJSONForScriptWithKeyDelimiter : Find the delimited JSON
+// following each occurrence of substring in every child script, in document
+// order. Payloads which fail to parse are skipped, the first failure is only
+// returned when none parse.
+//
+func (id *DOM) ChildFindAllJSONForScriptWithKeyDelimiter(parent *DOMNode, substring string, delimiter JSONDelimiter) (result []ScriptJSON, err error) {
+	var firstErr error
+	for _, node := range id.ChildFindWithKey(parent, "script", substring) {
+		contents := node.Text()
+		for idx := strings.Index(contents, substring); idx >= 0; {
+			value, err := scriptJSON(contents[idx:], delimiter)
+			if err == nil {
+				result = append(result, ScriptJSON{Node: node, Value: value})
+			} else if firstErr == nil {
+				firstErr = err
+			}
 
-				bytes := []byte(subtidy)
-				err = json.Unmarshal(bytes, &result)
+			next := strings.Index(contents[idx+len(substring):], substring)
+			if next < 0 || len(substring) == 0 {
+				break
 			}
+			idx += len(substring) + next
 		}
+	}
+	if len(result) == 0 {
+		return nil, firstErr
+	}
 
-		// we may have reset err above, so recheck
-		if err != nil {
-			log.Println(err, "\n", sub)
+	return result, nil
+}
+
+//
+// scriptJSON : Parse the delimited JSON following the key which sub starts with.
+//
+func scriptJSON(sub string, delimiter JSONDelimiter) (JSONMap, error) {
+	// the literal opens with the first delimiter following the key
+	idx := strings.Index(sub, delimiter[0])
+	if idx >= 0 {
+		sub = sub[idx:]
+	} else {
+		// bare entries, eg. a: 1, b: 2
+		if idx = strings.Index(sub, delimiter[1]); idx >= 0 {
+			sub = sub[:idx]
 		}
+		sub = delimiter[0] + sub + delimiter[1]
 	}
 
-	return
+	value, err := ParseJSLiteral(sub)
+	if err != nil {
+		return nil, err
+	}
+
+	return scriptObject(value)
 }
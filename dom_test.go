@@ -8,6 +8,7 @@ import (
 	"io/ioutil"
 	"path"
 	"runtime"
+	"sync"
 	"testing"
 )
 
@@ -127,3 +128,146 @@ func TestReaderText(t *testing.T) {
 	}
 
 }
+
+func TestFindJSONForScriptError(t *testing.T) {
+	d := NewDOM()
+	err := d.SetContents("<html><script>var config = {\"a\": [1, 2</script></html>")
+	if err != nil {
+		t.Errorf("failed to parse contents %s", err)
+	}
+
+	result, err := d.FindJSONForScriptWithKey("config")
+	if err == nil || result != nil {
+		t.Errorf("failed to return JSON error %v", result)
+	}
+}
+
+func TestFreeze(t *testing.T) {
+	contents := loadData(t, "test_b.html")
+
+	d := NewDOM()
+	d.SetContents(contents)
+	d.Freeze()
+	if !d.Frozen() {
+		t.Fatalf("failed to freeze")
+	}
+
+	var wg sync.WaitGroup
+	failures := make(chan string, 8)
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			node := d.Find("input", map[string]string{"name": "user"})
+			if len(node) != 1 {
+				failures <- "failed to find INPUT"
+			}
+		}()
+	}
+	wg.Wait()
+	close(failures)
+	for failure := range failures {
+		t.Error(failure)
+	}
+
+	if d.SetContents("<html></html>") != ErrFrozen {
+		t.Errorf("failed to reject parse of frozen DOM")
+	}
+	root := d.RootNode()
+	if root.RemoveChild(root.Children[0]) != ErrFrozen {
+		t.Errorf("failed to reject mutation of frozen DOM")
+	}
+	input := d.Find("input", map[string]string{"name": "user"})[0]
+	input.SetAttr("name", "other")
+	input.RemoveAttr("name")
+	input.RenameAttr("name", "id")
+	input.AddClass("x")
+	if input.Attr("name") != "user" || input.HasAttr("id") || input.HasClass("x") || input.Dirty() {
+		t.Errorf("failed to ignore attribute changes of frozen DOM")
+	}
+}
+
+func TestFindFirst(t *testing.T) {
+	d := NewDOM()
+	d.SetContents("<html><body><p>a</p><div><p>b</p><p class=\"x\">c</p></div></body></html>")
+
+	if node := d.FindFirst("p", nil); node == nil || node.Text() != "a" {
+		t.Fatalf("failed to find first node")
+	}
+	div := d.FindFirst("div", nil)
+	if node := d.ChildFindFirst(div, "p", DOMNodeAttributes{"class": "x"}); node == nil || node.Text() != "c" {
+		t.Errorf("failed to find first child node")
+	}
+	if d.FindFirst("span", nil) != nil {
+		t.Errorf("failed to miss unknown tag")
+	}
+	if nodes := d.FindLimit("p", nil, 2); len(nodes) != 2 || nodes[1].Text() != "b" {
+		t.Errorf("failed to limit results")
+	}
+	if nodes := d.FindLimit("*", nil, 0); len(nodes) != len(d.Find("*", nil)) || len(nodes) < 6 {
+		t.Errorf("failed to find all without a limit")
+	}
+}
+
+func TestIsDescendantNode(t *testing.T) {
+	d := NewDOM()
+	d.SetContents("<html><body><div id='a'><p>One<!-- c --><b>two</b></p></div><div id='b'><p>Three</p></div></body></html>")
+
+	check := func() {
+		for _, parent := range d.document {
+			for _, node := range d.document {
+				expected := parent == node || isAncestorNode(parent, node)
+				if d.IsDescendantNode(parent, node) != expected {
+					t.Errorf("mismatch for %s %d and %s %d", parent.Tag, parent.Index, node.Tag, node.Index)
+				}
+			}
+		}
+	}
+	check()
+
+	a, b := d.ByID("a"), d.ByID("b")
+	if err := b.AppendChild(a); err != nil {
+		t.Fatal(err)
+	}
+	check()
+	if ps := d.ChildFind(b, "p", nil); len(ps) != 2 || ps[1] != d.Find("b", nil)[0].Parent {
+		t.Errorf("unexpected matches after move %v", ps)
+	}
+	if ps := d.ChildFind(a, "*", nil); len(ps) != 3 || ps[0] != a {
+		t.Errorf("expected the parent and its descendants %v", ps)
+	}
+
+	other := NewDOM()
+	other.SetContents("<html><body><p>Four</p></body></html>")
+	if d.IsDescendantNode(b, other.Find("p", nil)[0]) || len(d.ChildFind(other.RootNode(), "p", nil)) != 0 {
+		t.Error("matched the nodes of another DOM")
+	}
+}
+
+func TestReset(t *testing.T) {
+	d := NewDOMWithArena()
+	d.SetParseOptions(ParseOptions{IndexAttributes: true})
+	d.SetContents("<html><body><div id='a' class='x'><p>One</p></div></body></html>")
+	d.Freeze()
+	old := d.ByID("a")
+	capacity := cap(d.document)
+
+	d.Reset()
+	if d.Frozen() || d.RootNode() != nil || len(d.Find("*", nil)) != 0 || d.ByID("a") != nil || len(d.Contents()) != 0 {
+		t.Fatal("failed to clear the DOM")
+	}
+	if cap(d.document) != capacity || cap(d.nodes["div"]) == 0 || old.dom != nil {
+		t.Error("failed to keep the capacity or detach the nodes")
+	}
+
+	if err := d.SetContents("<html><body><span id='b' class='y'>Two</span></body></html>"); err != nil {
+		t.Fatal(err)
+	}
+	if len(d.Find("div", nil)) != 0 || d.ByID("b") == nil || len(d.FindByAttr("class")) != 1 || !d.options.IndexAttributes {
+		t.Error("unexpected contents after reset")
+	}
+	if old.Tag != "div" || old.Children[0].Text() != "One" {
+		t.Error("modified a node of the previous parse")
+	}
+	checkIndexes(t, &d)
+}
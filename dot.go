@@ -0,0 +1,148 @@
+// Copyright 2016 Marc Lavergne <mlavergn@gmail.com>. All rights reserved.
+// Use of this source code is governed by
+// license that can be found in the LICENSE file.
+
+package godom
+
+import (
+	"io"
+	"strconv"
+	"strings"
+)
+
+// DOTOptions def
+// Controls the graph written by DOT
+type DOTOptions struct {
+	// Root is the node the graph starts from, the root node when nil
+	Root *DOMNode
+	// MaxText is the number of runes of text shown under each element, longer
+	// text is truncated with an ellipsis and 0 shows none
+	MaxText int
+	// MaxDepth collapses the elements at that depth below the root into a
+	// single node counting their descendants, 0 is unlimited
+	MaxDepth int
+	// Collapse are the tags whose subtrees are drawn as a single node, eg. svg
+	Collapse []string
+	// Highlight are the nodes drawn filled, eg. the results of a Find
+	Highlight []*DOMNode
+}
+
+// dotWriter def
+// Writes nodes as Graphviz statements, keeping the first write error
+type dotWriter struct {
+	w         io.Writer
+	options   DOTOptions
+	collapse  map[string]int
+	highlight map[*DOMNode]bool
+	err       error
+}
+
+//
+// DOT : Write the element tree as a Graphviz digraph, eg. for dot -Tsvg. Each
+// element is labeled with its tag, id, and classes.
+//
+func (id *DOM) DOT(w io.Writer, opts DOTOptions) error {
+	writer := &dotWriter{w: w, options: opts, collapse: tagSet(opts.Collapse), highlight: map[*DOMNode]bool{}}
+	for _, node := range opts.Highlight {
+		writer.highlight[node] = true
+	}
+
+	root := opts.Root
+	if root == nil {
+		root = id.RootNode()
+	}
+	writer.write("digraph dom {\n\tnode [shape=box, fontname=\"monospace\"];\n")
+	if root != nil {
+		writer.node(root, 0)
+	}
+	writer.write("}\n")
+
+	return writer.err
+}
+
+//
+// dotWriter: Write the string unless a write already failed.
+//
+func (id *dotWriter) write(s string) {
+	if id.err == nil {
+		_, id.err = io.WriteString(id.w, s)
+	}
+}
+
+//
+// dotWriter: Write the node, its edges, and its subtree unless collapsed.
+//
+func (id *dotWriter) node(node *DOMNode, depth int) {
+	label := nodeSummary(node)
+	collapsed := len(node.Children) > 0 && (id.collapse[node.Tag] == 1 || (id.options.MaxDepth > 0 && depth >= id.options.MaxDepth))
+	if collapsed {
+		label += " (+" + strconv.Itoa(node.SubtreeSize()) + ")"
+	}
+	if text := truncateText(node.Text(), id.options.MaxText); len(text) > 0 {
+		label += "\n\"" + text + "\""
+	}
+
+	name := "n" + strconv.Itoa(node.Index)
+	id.write("\t" + name + " [label=" + dotQuote(label))
+	// graphviz keeps the last style, so the styles are given together
+	switch {
+	case id.highlight[node] && collapsed:
+		id.write(", style=\"filled,dashed\", fillcolor=\"yellow\"")
+	case id.highlight[node]:
+		id.write(", style=filled, fillcolor=\"yellow\"")
+	case collapsed:
+		id.write(", style=dashed")
+	}
+	id.write("];\n")
+
+	if collapsed {
+		return
+	}
+	for _, child := range node.Children {
+		id.write("\t" + name + " -> n" + strconv.Itoa(child.Index) + ";\n")
+		id.node(child, depth+1)
+	}
+}
+
+//
+// dotQuote : The string as a DOT quoted string.
+//
+func dotQuote(s string) string {
+	s = strings.Replace(s, `\`, `\\`, -1)
+	s = strings.Replace(s, `"`, `\"`, -1)
+	s = strings.Replace(s, "\n", `\n`, -1)
+
+	return `"` + s + `"`
+}
+
+//
+// nodeSummary : The tag of the node followed by its #id and .classes.
+//
+func nodeSummary(node *DOMNode) string {
+	sb := strings.Builder{}
+	sb.WriteString(node.Tag)
+	if elementID := strings.TrimSpace(node.Attr("id")); len(elementID) > 0 {
+		sb.WriteString("#" + elementID)
+	}
+	for _, class := range node.Classes() {
+		sb.WriteString("." + class)
+	}
+
+	return sb.String()
+}
+
+//
+// truncateText : The text with whitespace collapsed, cut to max runes with an
+// ellipsis, empty when max is 0 or less.
+//
+func truncateText(text string, max int) string {
+	if max <= 0 {
+		return ""
+	}
+	text = strings.Join(strings.Fields(text), " ")
+	if runes := []rune(text); len(runes) > max {
+		return string(runes[:max]) + "…"
+	}
+
+	return text
+}
@@ -0,0 +1,53 @@
+// Copyright 2016, Marc Lavergne <mlavergn@gmail.com>. All rights reserved.
+// Use of this source code is governed by the MIT
+// license that can be found in the LICENSE file.
+
+package godom
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestDOT(t *testing.T) {
+	d := NewDOM()
+	d.SetContents(`<html><body><div id="a" class="x y"><p>Some "quoted" text here</p></div><svg><g><path></path></g></svg></body></html>`)
+
+	sb := strings.Builder{}
+	p := d.Find("p", nil)[0]
+	if err := d.DOT(&sb, DOTOptions{MaxText: 10, Collapse: []string{"svg"}, Highlight: []*DOMNode{p}}); err != nil {
+		t.Fatal(err)
+	}
+	graph := sb.String()
+	for _, expected := range []string{
+		"digraph dom {\n",
+		`[label="div#a.x.y"];`,
+		`[label="p\n\"Some \"quot…\""` + ", style=filled",
+		`[label="svg (+2)", style=dashed];`,
+		"n2 -> n4;",
+	} {
+		if !strings.Contains(graph, expected) {
+			t.Errorf("expected %s in\n%s", expected, graph)
+		}
+	}
+	if strings.Contains(graph, "path") || !strings.HasSuffix(graph, "}\n") {
+		t.Errorf("expected the svg subtree to collapse\n%s", graph)
+	}
+
+	sb.Reset()
+	if err := d.DOT(&sb, DOTOptions{Root: d.ByID("a"), MaxDepth: 1}); err != nil {
+		t.Fatal(err)
+	}
+	if graph := sb.String(); !strings.Contains(graph, `label="div#a.x.y"`) || strings.Contains(graph, "body") || strings.Count(graph, "->") != 1 {
+		t.Errorf("unexpected subtree graph\n%s", graph)
+	}
+
+	sb.Reset()
+	svg := d.Find("svg", nil)[0]
+	if err := d.DOT(&sb, DOTOptions{Collapse: []string{"svg"}, Highlight: []*DOMNode{svg}}); err != nil {
+		t.Fatal(err)
+	}
+	if graph := sb.String(); !strings.Contains(graph, `[label="svg (+2)", style="filled,dashed", fillcolor="yellow"];`) {
+		t.Errorf("expected a single style for the highlighted collapsed node\n%s", graph)
+	}
+}
@@ -0,0 +1,148 @@
+// Copyright 2016 Marc Lavergne <mlavergn@gmail.com>. All rights reserved.
+// Use of this source code is governed by
+// license that can be found in the LICENSE file.
+
+package godom
+
+import (
+	"bytes"
+	"fmt"
+	"golang.org/x/net/html"
+	"io"
+	"strings"
+)
+
+// entitySentinel stands in for & while parsing with EntitiesRaw so the parser
+// finds no character references, it is a noncharacter never found in text
+const entitySentinel = "\uFDD0"
+
+// EntityMode type
+// How character references in text and attribute values are parsed
+type EntityMode int
+
+// EntityMode values
+const (
+	// EntitiesDecode decodes the references once, as the HTML parser does
+	EntitiesDecode EntityMode = iota
+	// EntitiesDecodeNested also decodes the references left in the decoded
+	// text, such as the &eacute; of a double escaped &amp;eacute;, along with
+	// the legacy references without a semicolon kept in attribute values.
+	// The text of script and style is not decoded
+	EntitiesDecodeNested
+	// EntitiesRaw keeps the references as written, so text holds &amp; rather
+	// than &. Render with EscapeRaw to write the contents back unchanged. Only
+	// the HTML parsers, not SetContentsXML, support it
+	EntitiesRaw
+)
+
+// TextEscape type
+// How Render escapes text and attribute values
+type TextEscape int
+
+// TextEscape values
+const (
+	// EscapeDefault escapes &, <, >, ", and '
+	EscapeDefault TextEscape = iota
+	// EscapeASCII also writes every non-ASCII character as a numeric reference
+	EscapeASCII
+	// EscapeRaw writes text and attribute values unescaped, for DOMs parsed
+	// with EntitiesRaw
+	EscapeRaw
+)
+
+//
+// DOM: The parsed text or attribute value with the entity mode applied,
+// decode is unset for text which is never decoded, such as comments.
+//
+func (id *DOM) entities(text string, decode bool) string {
+	switch id.options.Entities {
+	case EntitiesRaw:
+		return strings.Replace(text, entitySentinel, "&", -1)
+	case EntitiesDecodeNested:
+		for decode && strings.IndexByte(text, '&') >= 0 {
+			decoded := html.UnescapeString(text)
+			if decoded == text {
+				break
+			}
+			text = decoded
+		}
+	}
+
+	return text
+}
+
+//
+// DOM: The contents to be parsed with the entity mode applied.
+//
+func (id *DOM) entityContents(contents string) string {
+	if id.options.Entities != EntitiesRaw {
+		return contents
+	}
+
+	return strings.Replace(contents, "&", entitySentinel, -1)
+}
+
+//
+// DOM: The reader to be tokenized with the entity mode applied.
+//
+func (id *DOM) entityReader(r io.Reader) io.Reader {
+	if id.options.Entities != EntitiesRaw {
+		return r
+	}
+
+	return &entityReader{r: r}
+}
+
+// entityReader def
+// Replaces & with the sentinel in the bytes read
+type entityReader struct {
+	r       io.Reader
+	pending []byte
+	err     error
+}
+
+//
+// entityReader: Read the next bytes, replacing & with the sentinel.
+//
+func (id *entityReader) Read(p []byte) (int, error) {
+	if len(p) == 0 {
+		return 0, nil
+	}
+	for len(id.pending) == 0 {
+		if id.err != nil {
+			return 0, id.err
+		}
+		buf := make([]byte, len(p))
+		n, err := id.r.Read(buf)
+		id.pending = bytes.Replace(buf[:n], []byte("&"), []byte(entitySentinel), -1)
+		id.err = err
+	}
+
+	n := copy(p, id.pending)
+	id.pending = id.pending[n:]
+
+	return n, nil
+}
+
+//
+// escapeText : The text escaped for HTML with the escape mode applied.
+//
+func escapeText(text string, mode TextEscape) string {
+	switch mode {
+	case EscapeRaw:
+		return text
+	case EscapeASCII:
+		text = html.EscapeString(text)
+		sb := strings.Builder{}
+		for _, r := range text {
+			if r < 0x80 {
+				sb.WriteRune(r)
+			} else {
+				fmt.Fprintf(&sb, "&#x%X;", r)
+			}
+		}
+		return sb.String()
+	}
+
+	return html.EscapeString(text)
+}
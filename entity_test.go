@@ -0,0 +1,86 @@
+// Copyright 2016, Marc Lavergne <mlavergn@gmail.com>. All rights reserved.
+// Use of this source code is governed by the MIT
+// license that can be found in the LICENSE file.
+
+package godom
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestEntities(t *testing.T) {
+	contents := `<html><head><script>if (a &amp;&amp; b) {}</script></head><body><p title="Caf&amp;eacute;">Caf&amp;eacute; &copy;</p><div>&lt;ok&gt;</div><a href="/s?a=1&copy=2">x</a></body></html>`
+
+	d := NewDOM()
+	d.SetContents(contents)
+	if p := d.Find("p", nil)[0]; p.Text() != "Caf&eacute; ©" || p.Attr("title") != "Caf&eacute;" || len(d.Find("ok", nil)) != 1 {
+		t.Errorf("unexpected default decoding [%s]", p.Text())
+	}
+
+	for _, stream := range []bool{false, true} {
+		d := NewDOM()
+		d.SetParseOptions(ParseOptions{Entities: EntitiesDecodeNested})
+		if stream {
+			d.SetContentsFromReader(strings.NewReader(contents))
+		} else {
+			d.SetContents(contents)
+		}
+		if p := d.Find("p", nil)[0]; p.Text() != "Café ©" || p.Attr("title") != "Café" {
+			t.Errorf("failed to decode nested entities [%s] [stream %t]", p.Text(), stream)
+		}
+		if a := d.Find("a", nil)[0]; a.Attr("href") != "/s?a=1©=2" {
+			t.Errorf("failed to decode legacy entities [%s] [stream %t]", a.Attr("href"), stream)
+		}
+		if script := d.Find("script", nil)[0]; script.Text() != "if (a &amp;&amp; b) {}" {
+			t.Errorf("unexpected script decoding [%s] [stream %t]", script.Text(), stream)
+		}
+	}
+
+	for _, stream := range []bool{false, true} {
+		d := NewDOM()
+		d.SetParseOptions(ParseOptions{Entities: EntitiesRaw})
+		if stream {
+			d.SetContentsFromReader(strings.NewReader(contents))
+		} else {
+			d.SetContents(contents)
+		}
+		p := d.Find("p", nil)[0]
+		if p.Text() != "Caf&amp;eacute; &copy;" || p.Attr("title") != "Caf&amp;eacute;" || len(d.Find("ok", nil)) != 0 {
+			t.Errorf("failed to keep raw entities [%s] [stream %t]", p.Text(), stream)
+		}
+		sb := strings.Builder{}
+		if err := p.Render(&sb, RenderOptions{Escape: EscapeRaw}); err != nil || sb.String() != `<p title="Caf&amp;eacute;">Caf&amp;eacute; &copy;</p>` {
+			t.Errorf("failed to render raw entities [%s] [stream %t]", sb.String(), stream)
+		}
+	}
+
+	d = NewDOM()
+	d.SetContents(`<html><body><p title='"Zoë"'>Zoë &amp; “Ann”</p></body></html>`)
+	sb := strings.Builder{}
+	d.Find("p", nil)[0].Render(&sb, RenderOptions{Escape: EscapeASCII})
+	if sb.String() != `<p title="&#34;Zo&#xEB;&#34;">Zo&#xEB; &amp; &#x201C;Ann&#x201D;</p>` {
+		t.Errorf("unexpected ASCII escaping [%s]", sb.String())
+	}
+	sb.Reset()
+	d.Find("p", nil)[0].Render(&sb, RenderOptions{Escape: EscapeRaw})
+	if sb.String() != `<p title='"Zoë"'>Zoë & “Ann”</p>` {
+		t.Errorf("unexpected raw quoting [%s]", sb.String())
+	}
+}
+
+func TestEntityReader(t *testing.T) {
+	r := &entityReader{r: strings.NewReader("a&b&&c")}
+	buf := make([]byte, 2)
+	sb := strings.Builder{}
+	for {
+		n, err := r.Read(buf)
+		sb.Write(buf[:n])
+		if err != nil {
+			break
+		}
+	}
+	if sb.String() != "a"+entitySentinel+"b"+entitySentinel+entitySentinel+"c" {
+		t.Errorf("unexpected replacement [%q]", sb.String())
+	}
+}
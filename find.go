@@ -0,0 +1,361 @@
+// Copyright 2016 Marc Lavergne <mlavergn@gmail.com>. All rights reserved.
+// Use of this source code is governed by
+// license that can be found in the LICENSE file.
+
+package godom
+
+import (
+	"regexp"
+	"sort"
+	"strings"
+	"unicode"
+
+	"golang.org/x/text/unicode/norm"
+)
+
+// KeyOptions def
+// Controls how FindWithKeyOptions and FindWithTextOptions compare the node
+// text with the key
+type KeyOptions struct {
+	// IgnoreCase matches regardless of letter case
+	IgnoreCase bool
+	// Normalize compares the NFC normalization of the text and the key, so
+	// precomposed and decomposed accents match
+	Normalize bool
+	// FoldDiacritics drops combining marks before comparing, so "cafe"
+	// matches "café", implies Normalize
+	FoldDiacritics bool
+	// CollapseSpace trims the text and the key and collapses their runs of
+	// whitespace to a single space
+	CollapseSpace bool
+}
+
+//
+// FindMatching : Find the Node of type tag with text matching the pattern
+//
+func (id *DOM) FindMatching(tag string, re *regexp.Regexp) (result []*DOMNode) {
+	return id.ChildFindMatching(id.RootNode(), tag, re)
+}
+
+//
+// ChildFindMatching : Find the child Node of type tag with text matching the pattern
+//
+func (id *DOM) ChildFindMatching(parent *DOMNode, tag string, re *regexp.Regexp) (result []*DOMNode) {
+	tagNodes := id.tagNodes(tag)
+	for _, node := range tagNodes {
+		if id.IsDescendantNode(parent, node) && re.MatchString(node.Text()) {
+			result = append(result, node)
+		}
+	}
+
+	return result
+}
+
+//
+// FindAttrMatching : Find the Node of type tag with the attribute value matching the pattern
+//
+func (id *DOM) FindAttrMatching(tag string, key string, re *regexp.Regexp) (result []*DOMNode) {
+	return id.ChildFindAttrMatching(id.RootNode(), tag, key, re)
+}
+
+//
+// ChildFindAttrMatching : Find the child Node of type tag with the attribute value
+// matching the pattern. Nodes without the attribute never match.
+//
+func (id *DOM) ChildFindAttrMatching(parent *DOMNode, tag string, key string, re *regexp.Regexp) (result []*DOMNode) {
+	key = attrKey(key)
+	tagNodes := id.tagNodes(tag)
+	for _, node := range tagNodes {
+		_, val, ok := node.lookupAttr(key)
+		if ok && id.IsDescendantNode(parent, node) && re.MatchString(val) {
+			result = append(result, node)
+		}
+	}
+
+	return result
+}
+
+//
+// FindWithKeyOptions : Find the Node of type tag with text containing key
+// compared according to the options
+//
+func (id *DOM) FindWithKeyOptions(tag string, substring string, opts KeyOptions) (result []*DOMNode) {
+	return id.ChildFindWithKeyOptions(id.RootNode(), tag, substring, opts)
+}
+
+//
+// ChildFindWithKeyOptions : Find the child Node of type tag with text containing
+// key compared according to the options
+//
+func (id *DOM) ChildFindWithKeyOptions(parent *DOMNode, tag string, substring string, opts KeyOptions) (result []*DOMNode) {
+	substring = opts.fold(substring)
+	tagNodes := id.tagNodes(tag)
+	for _, node := range tagNodes {
+		if id.IsDescendantNode(parent, node) && strings.Contains(opts.fold(node.Text()), substring) {
+			result = append(result, node)
+		}
+	}
+
+	return result
+}
+
+//
+// FindWithText : Find the Node of type tag whose text is exactly text, so
+// "Total" doesn't match "Subtotal" as FindWithKey does
+//
+func (id *DOM) FindWithText(tag string, text string) (result []*DOMNode) {
+	return id.ChildFindWithTextOptions(id.RootNode(), tag, text, KeyOptions{})
+}
+
+//
+// FindWithTextOptions : Find the Node of type tag whose text equals text
+// compared according to the options
+//
+func (id *DOM) FindWithTextOptions(tag string, text string, opts KeyOptions) (result []*DOMNode) {
+	return id.ChildFindWithTextOptions(id.RootNode(), tag, text, opts)
+}
+
+//
+// ChildFindWithTextOptions : Find the child Node of type tag whose text equals
+// text compared according to the options
+//
+func (id *DOM) ChildFindWithTextOptions(parent *DOMNode, tag string, text string, opts KeyOptions) (result []*DOMNode) {
+	text = opts.fold(text)
+	tagNodes := id.tagNodes(tag)
+	for _, node := range tagNodes {
+		if id.IsDescendantNode(parent, node) && opts.fold(node.Text()) == text {
+			result = append(result, node)
+		}
+	}
+
+	return result
+}
+
+//
+// KeyOptions: The text in the form compared by the options.
+//
+func (id KeyOptions) fold(text string) string {
+	if id.CollapseSpace {
+		text = strings.Join(strings.Fields(text), " ")
+	}
+	if id.FoldDiacritics {
+		text = strings.Map(func(r rune) rune {
+			if unicode.Is(unicode.Mn, r) {
+				return -1
+			}
+			return r
+		}, norm.NFD.String(text))
+	}
+	if id.Normalize || id.FoldDiacritics {
+		text = norm.NFC.String(text)
+	}
+	if id.IgnoreCase {
+		text = strings.ToLower(text)
+	}
+
+	return text
+}
+
+//
+// FindFunc : Find the Nodes for which the predicate returns true, in document order
+//
+func (id *DOM) FindFunc(fn func(*DOMNode) bool) (result []*DOMNode) {
+	return id.ChildFindFunc(id.RootNode(), fn)
+}
+
+//
+// ChildFindFunc : Find the parent and child Nodes for which the predicate returns
+// true, in document order. A nil parent searches every element of the document,
+// eg. the top level elements of a fragment
+//
+func (id *DOM) ChildFindFunc(parent *DOMNode, fn func(*DOMNode) bool) (result []*DOMNode) {
+	if parent == nil {
+		for _, node := range id.document {
+			if pseudoTags[node.Tag] == 0 && fn(node) {
+				result = append(result, node)
+			}
+		}
+		return result
+	}
+
+	parent.Walk(func(node *DOMNode, depth int) WalkAction {
+		if fn(node) {
+			result = append(result, node)
+		}
+		return WalkContinue
+	})
+
+	return result
+}
+
+//
+// FindAny : Find the Nodes of any of the tags with the specified attributes,
+// in document order
+//
+func (id *DOM) FindAny(tags []string, attributes DOMNodeAttributes) (result []*DOMNode) {
+	return id.ChildFindAny(id.RootNode(), tags, attributes)
+}
+
+//
+// ChildFindAny : Find the child Nodes of any of the tags with the specified
+// attributes, in document order
+//
+func (id *DOM) ChildFindAny(parent *DOMNode, tags []string, attributes DOMNodeAttributes) (result []*DOMNode) {
+	seen := map[string]int{}
+	for _, tag := range tags {
+		if tag == "*" {
+			return id.ChildFind(parent, tag, attributes)
+		}
+		if seen[tag] == 1 {
+			continue
+		}
+		seen[tag] = 1
+		result = append(result, id.ChildFind(parent, tag, attributes)...)
+	}
+
+	// each tag is in document order, but not the tags relative to one another
+	sortNodes(result)
+
+	return result
+}
+
+// AttrOp type
+// The comparison of an AttrMatcher
+type AttrOp int
+
+const (
+	// AttrExists the attribute is present, as [attr]
+	AttrExists AttrOp = iota
+	// AttrEquals the value equals, as [attr=val]
+	AttrEquals
+	// AttrPrefix the value starts with, as [attr^=val]
+	AttrPrefix
+	// AttrSuffix the value ends with, as [attr$=val]
+	AttrSuffix
+	// AttrContains the value contains, as [attr*=val]
+	AttrContains
+	// AttrToken a whitespace separated token of the value equals, as [attr~=val]
+	AttrToken
+)
+
+// attrOps the selector operator of each AttrOp
+var attrOps = map[AttrOp]string{
+	AttrExists: "", AttrEquals: "=", AttrPrefix: "^=", AttrSuffix: "$=", AttrContains: "*=", AttrToken: "~=",
+}
+
+// AttrMatcher def
+// A condition on an attribute, the empty value never matches the prefix,
+// suffix, and contains operators as in CSS
+type AttrMatcher struct {
+	Key   string
+	Op    AttrOp
+	Value string
+}
+
+//
+// Match : Does the node satisfy the condition?
+//
+func (id AttrMatcher) Match(node *DOMNode) bool {
+	op, ok := attrOps[id.Op]
+	if !ok {
+		return false
+	}
+
+	return selectorAttr{key: attrKey(id.Key), op: op, val: id.Value}.match(node)
+}
+
+//
+// FindWhere : Find the Node of type tag satisfying all of the matchers, the tag
+// "*" matches any element
+//
+func (id *DOM) FindWhere(tag string, matchers ...AttrMatcher) (result []*DOMNode) {
+	return id.ChildFindWhere(id.RootNode(), tag, matchers...)
+}
+
+//
+// ChildFindWhere : Find the child Node of type tag satisfying all of the matchers
+//
+func (id *DOM) ChildFindWhere(parent *DOMNode, tag string, matchers ...AttrMatcher) (result []*DOMNode) {
+	for _, node := range id.ChildFind(parent, tag, nil) {
+		matched := true
+		for _, matcher := range matchers {
+			if !matcher.Match(node) {
+				matched = false
+				break
+			}
+		}
+		if matched {
+			result = append(result, node)
+		}
+	}
+
+	return result
+}
+
+// Exclusion def
+// The nodes left out of the results of FindExcluding and Exclude
+type Exclusion struct {
+	// Tags excludes the nodes of these tags
+	Tags []string
+	// Attributes excludes the nodes matching all of these attributes, as Find
+	Attributes DOMNodeAttributes
+	// Ancestors excludes the nodes within an element of these tags, eg. nav
+	// or footer
+	Ancestors []string
+}
+
+//
+// FindExcluding : Find the Node of type tag with the specified attributes,
+// leaving out the excluded nodes
+//
+func (id *DOM) FindExcluding(tag string, attributes DOMNodeAttributes, exclusion Exclusion) (result []*DOMNode) {
+	return id.ChildFindExcluding(id.RootNode(), tag, attributes, exclusion)
+}
+
+//
+// ChildFindExcluding : Find the child Node of type tag with the specified
+// attributes, leaving out the excluded nodes
+//
+func (id *DOM) ChildFindExcluding(parent *DOMNode, tag string, attributes DOMNodeAttributes, exclusion Exclusion) (result []*DOMNode) {
+	return id.Exclude(id.ChildFind(parent, tag, attributes), exclusion)
+}
+
+//
+// Exclude : Filter the excluded nodes out of the query results. Each excluded
+// subtree spans a range of document indexes, so the ancestor test is a binary
+// search rather than a walk up the tree.
+//
+func (id *DOM) Exclude(nodes []*DOMNode, exclusion Exclusion) (result []*DOMNode) {
+	tags := tagSet(exclusion.Tags)
+
+	// the index ranges of the excluded subtrees, ordered and disjoint
+	spans := [][2]int{}
+	for _, node := range id.ChildFindAny(id.RootNode(), exclusion.Ancestors, nil) {
+		if len(spans) > 0 && node.Index <= spans[len(spans)-1][1] {
+			// nested within the previous span
+			continue
+		}
+		last := node
+		for len(last.Children) > 0 {
+			last = last.Children[len(last.Children)-1]
+		}
+		spans = append(spans, [2]int{node.Index, last.Index})
+	}
+
+	for _, node := range nodes {
+		if tags[node.Tag] == 1 || (len(exclusion.Attributes) > 0 && node.matchAttributes(exclusion.Attributes)) {
+			continue
+		}
+		// the first span ending at or after the node, the node itself is not
+		// within the span it starts
+		i := sort.Search(len(spans), func(i int) bool {
+			return spans[i][1] >= node.Index
+		})
+		if i < len(spans) && spans[i][0] < node.Index {
+			continue
+		}
+		result = append(result, node)
+	}
+
+	return result
+}
@@ -0,0 +1,211 @@
+// Copyright 2016, Marc Lavergne <mlavergn@gmail.com>. All rights reserved.
+// Use of this source code is governed by the MIT
+// license that can be found in the LICENSE file.
+
+package godom
+
+import (
+	"regexp"
+	"testing"
+)
+
+func TestFindMatching(t *testing.T) {
+	d := NewDOM()
+	d.SetContents("<html><body><div><span>Order #A-1234</span><span>$19.99</span><a href=\"/p/sku-42\">x</a><a href=\"/about\">y</a></div></body></html>")
+
+	nodes := d.FindMatching("span", regexp.MustCompile(`\$\d+\.\d{2}`))
+	if len(nodes) != 1 || nodes[0].Text() != "$19.99" {
+		d.Dump()
+		t.Fatalf("failed to find text matching pattern")
+	}
+
+	nodes = d.FindAttrMatching("a", "HREF", regexp.MustCompile(`sku-\d+$`))
+	if len(nodes) != 1 || nodes[0].Text() != "x" {
+		t.Errorf("failed to find attribute matching pattern")
+	}
+	if len(d.FindAttrMatching("a", "title", regexp.MustCompile(`.*`))) != 0 {
+		t.Errorf("failed to skip nodes without the attribute")
+	}
+
+	// * finds elements only, not the comments or doctype
+	d = NewDOM()
+	d.SetContents("<!DOCTYPE html><html><body><!-- html --><p>html</p></body></html>")
+	body := d.Find("body", nil)[0]
+	if nodes := d.FindWithKey("*", "html"); len(nodes) != 1 || nodes[0].Tag != "p" {
+		t.Errorf("unexpected * nodes %v", nodes)
+	}
+	if nodes := d.ChildFindMatching(body, "*", regexp.MustCompile("html")); len(nodes) != 1 || nodes[0].Tag != "p" {
+		t.Errorf("unexpected * matches %v", nodes)
+	}
+	if len(d.ChildFind(body, "*", nil)) != 2 || len(d.ChildFind(nil, "*", nil)) != 4 {
+		t.Errorf("unexpected * children %d", len(d.ChildFind(body, "*", nil)))
+	}
+}
+
+func TestFindWithKeyOptions(t *testing.T) {
+	d := NewDOM()
+	d.SetContents("<html><body><p>Le CAFÉ du coin</p><p>Le cafe\u0301 noir</p><p>Thé</p></body></html>")
+
+	if len(d.FindWithKey("p", "café")) != 0 {
+		t.Fatalf("failed to match literally without options")
+	}
+	if nodes := d.FindWithKeyOptions("p", "café", KeyOptions{IgnoreCase: true}); len(nodes) != 1 {
+		t.Errorf("failed to match ignoring case [%d]", len(nodes))
+	}
+	if nodes := d.FindWithKeyOptions("p", "café", KeyOptions{IgnoreCase: true, Normalize: true}); len(nodes) != 2 {
+		t.Errorf("failed to match normalized [%d]", len(nodes))
+	}
+	if nodes := d.FindWithKeyOptions("p", "cafe", KeyOptions{IgnoreCase: true, FoldDiacritics: true}); len(nodes) != 2 {
+		t.Errorf("failed to match folded diacritics [%d]", len(nodes))
+	}
+	if nodes := d.FindWithKeyOptions("p", "the", KeyOptions{}); len(nodes) != 0 {
+		t.Errorf("failed to keep diacritics by default")
+	}
+}
+
+func TestFindFunc(t *testing.T) {
+	d := NewDOM()
+	d.SetContents("<html><body><ul><li>a</li><li>b</li></ul><ul><li>c</li></ul><p title=\"x\" lang=\"en\">long enough</p></body></html>")
+
+	nodes := d.FindFunc(func(node *DOMNode) bool {
+		return node.Tag == "ul" && len(node.Children) > 1
+	})
+	if len(nodes) != 1 || nodes[0].Children[0].Text() != "a" {
+		d.Dump()
+		t.Fatalf("failed to find by child count")
+	}
+
+	nodes = d.FindFunc(func(node *DOMNode) bool {
+		return node.HasAttr("title") && node.HasAttr("lang") && len(node.Text()) > 5
+	})
+	if len(nodes) != 1 || nodes[0].Tag != "p" {
+		t.Errorf("failed to find by attributes and text length")
+	}
+
+	list := d.Find("ul", nil)[1]
+	nodes = d.ChildFindFunc(list, func(node *DOMNode) bool {
+		return node.Tag == "li"
+	})
+	if len(nodes) != 1 || nodes[0].Text() != "c" {
+		t.Errorf("failed to scope to parent")
+	}
+
+	fragment, err := NewFragment("<p>one</p><div><p>two</p></div>")
+	if err != nil {
+		t.Fatal(err)
+	}
+	nodes = fragment.FindFunc(func(node *DOMNode) bool {
+		return node.Tag == "p"
+	})
+	if len(nodes) != 2 || len(nodes) != len(fragment.Find("p", nil)) {
+		t.Errorf("expected the fragment paragraphs, found %d", len(nodes))
+	}
+}
+
+func TestFindAny(t *testing.T) {
+	d := NewDOM()
+	d.SetContents("<html><body><h2 class=\"t\">b</h2><h1 class=\"t\">a</h1><div class=\"t\"><h3>c</h3></div></body></html>")
+
+	nodes := d.FindAny([]string{"h1", "h2", "h3", "h1"}, nil)
+	if len(nodes) != 3 || nodes[0].Text() != "b" || nodes[1].Text() != "a" || nodes[2].Text() != "c" {
+		d.Dump()
+		t.Fatalf("failed to find multiple tags in document order")
+	}
+
+	nodes = d.Find("*", DOMNodeAttributes{"class": "t"})
+	if len(nodes) != 3 || nodes[0].Tag != "h2" || nodes[2].Tag != "div" {
+		t.Errorf("failed to find any tag by attributes")
+	}
+	if len(d.ChildFind(d.Find("div", nil)[0], "*", nil)) != 2 {
+		t.Errorf("failed to scope any tag to parent")
+	}
+	if len(d.FindAny([]string{"p", "*"}, DOMNodeAttributes{"class": "t"})) != 3 {
+		t.Errorf("failed to treat wildcard in tag list")
+	}
+}
+
+func TestFindWhere(t *testing.T) {
+	d := NewDOM()
+	d.SetContents("<html><body><a href=\"https://x.com/a.pdf\" rel=\"nofollow noopener\">1</a><a href=\"/b.html\">2</a><a>3</a></body></html>")
+
+	checks := []struct {
+		matcher AttrMatcher
+		texts   string
+	}{
+		{AttrMatcher{Key: "href"}, "12"},
+		{AttrMatcher{Key: "href", Op: AttrEquals, Value: "/b.html"}, "2"},
+		{AttrMatcher{Key: "href", Op: AttrPrefix, Value: "https:"}, "1"},
+		{AttrMatcher{Key: "HREF", Op: AttrSuffix, Value: ".pdf"}, "1"},
+		{AttrMatcher{Key: "href", Op: AttrContains, Value: "b."}, "2"},
+		{AttrMatcher{Key: "rel", Op: AttrToken, Value: "noopener"}, "1"},
+		{AttrMatcher{Key: "rel", Op: AttrToken, Value: "noop"}, ""},
+		{AttrMatcher{Key: "href", Op: AttrPrefix}, ""},
+	}
+	for _, check := range checks {
+		texts := ""
+		for _, node := range d.FindWhere("a", check.matcher) {
+			texts += node.Text()
+		}
+		if texts != check.texts {
+			t.Errorf("failed to match %v [%s]", check.matcher, texts)
+		}
+	}
+
+	nodes := d.FindWhere("*", AttrMatcher{Key: "href"}, AttrMatcher{Key: "rel", Op: AttrExists})
+	if len(nodes) != 1 || nodes[0].Text() != "1" {
+		t.Errorf("failed to combine matchers")
+	}
+}
+
+func TestFindExcluding(t *testing.T) {
+	d := NewDOM()
+	d.SetContents("<html><body><nav><ul><li><a>n1</a></li></ul></nav><div><a>c1</a><a class=\"ad\">c2</a><nav><a>n2</a></nav><a>c3</a></div><footer><a>f1</a></footer></body></html>")
+
+	texts := func(nodes []*DOMNode) (result string) {
+		for _, node := range nodes {
+			result += node.Text() + " "
+		}
+		return result
+	}
+
+	nodes := d.FindExcluding("a", nil, Exclusion{Ancestors: []string{"nav", "footer"}})
+	if texts(nodes) != "c1 c2 c3 " {
+		d.Dump()
+		t.Fatalf("failed to exclude by ancestor [%s]", texts(nodes))
+	}
+
+	nodes = d.FindExcluding("a", nil, Exclusion{Ancestors: []string{"footer"}, Attributes: DOMNodeAttributes{"class": "ad"}})
+	if texts(nodes) != "n1 c1 n2 c3 " {
+		t.Errorf("failed to exclude by attribute [%s]", texts(nodes))
+	}
+
+	nodes = d.Exclude(d.FindAny([]string{"nav", "footer", "ul"}, nil), Exclusion{Tags: []string{"UL"}, Ancestors: []string{"div"}})
+	if len(nodes) != 2 || nodes[0].Tag != "nav" || nodes[1].Tag != "footer" {
+		t.Errorf("failed to exclude by tag")
+	}
+}
+
+func TestFindWithText(t *testing.T) {
+	d := NewDOM()
+	d.SetContents("<html><body><table><tr><td>Subtotal</td><td>10</td></tr><tr><td>Total</td><td>12</td></tr><tr><td>  TOTAL\n due </td></tr></table><!--Total--></body></html>")
+
+	if found := d.FindWithText("td", "Total"); len(found) != 1 || found[0].NextElement().Text() != "12" {
+		t.Errorf("unexpected exact matches %v", found)
+	}
+	if found := d.FindWithKeyOptions("td", "total", KeyOptions{IgnoreCase: true}); len(found) != 3 {
+		t.Errorf("expected the substring to match 3 times, found %d", len(found))
+	}
+	if found := d.FindWithTextOptions("td", "total", KeyOptions{IgnoreCase: true}); len(found) != 1 {
+		t.Errorf("expected the text to match once, found %d", len(found))
+	}
+	if found := d.FindWithText("*", "Total"); len(found) != 1 {
+		t.Errorf("expected elements only, found %d", len(found))
+	}
+	found := d.FindWithTextOptions("td", "total due", KeyOptions{IgnoreCase: true, CollapseSpace: true})
+	if len(found) != 1 || found[0] != d.Find("td", nil)[4] {
+		t.Errorf("unexpected normalized matches %v", found)
+	}
+	if found := d.ChildFindWithTextOptions(d.Find("tr", nil)[0], "td", "Total", KeyOptions{}); len(found) != 0 {
+		t.Errorf("matched outside the parent %v", found)
+	}
+}
@@ -0,0 +1,388 @@
+// Copyright 2016 Marc Lavergne <mlavergn@gmail.com>. All rights reserved.
+// Use of this source code is governed by
+// license that can be found in the LICENSE file.
+
+package godom
+
+import (
+	"bytes"
+	"errors"
+	"mime/multipart"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+)
+
+// ErrFormMethod the form method can't be submitted as an HTTP request
+var ErrFormMethod = errors.New("godom: unsupported form method")
+
+// formFieldTags elements extracted as form fields
+var formFieldTags = map[string]int{"input": 1, "select": 1, "textarea": 1, "button": 1}
+
+// Form def
+// A <form> with its method and encoding defaulted per the HTML spec
+type Form struct {
+	Node    *DOMNode
+	ID      string
+	Name    string
+	Action  string
+	Method  string
+	Enctype string
+	Fields  []*FormField
+	// Submitter is the button used to submit the form, nil for the default button
+	Submitter *FormField
+}
+
+// FormField def
+// An input, select, textarea, or button of a form
+type FormField struct {
+	Node *DOMNode
+	Tag  string
+	// Type is the lowercased input or button type, or the tag for select and textarea
+	Type     string
+	Name     string
+	Value    string
+	Checked  bool
+	Disabled bool
+	Multiple bool
+	Options  []*FormOption
+}
+
+// FormOption def
+// An option of a select field
+type FormOption struct {
+	Value    string
+	Label    string
+	Selected bool
+	Disabled bool
+}
+
+//
+// Forms : Extract every form in the document with its fields.
+//
+func (id *DOM) Forms() (result []*Form) {
+	for _, node := range id.Find("form", nil) {
+		result = append(result, id.newForm(node))
+	}
+
+	return result
+}
+
+//
+// DOM: Build the Form for the node, including fields associated with the
+// form elsewhere in the document via the form attribute.
+//
+func (id *DOM) newForm(node *DOMNode) *Form {
+	result := &Form{
+		Node:    node,
+		ID:      node.Attr("id"),
+		Name:    node.Attr("name"),
+		Action:  strings.TrimSpace(node.Attr("action")),
+		Method:  strings.ToUpper(strings.TrimSpace(node.Attr("method"))),
+		Enctype: strings.ToLower(strings.TrimSpace(node.Attr("enctype"))),
+	}
+	if result.Method != "POST" && result.Method != "DIALOG" {
+		result.Method = "GET"
+	}
+	if result.Enctype != "multipart/form-data" && result.Enctype != "text/plain" {
+		result.Enctype = "application/x-www-form-urlencoded"
+	}
+
+	fields := []*DOMNode{}
+	for tag := range formFieldTags {
+		for _, field := range id.nodes[tag] {
+			owner := field.Attr("form")
+			if (len(owner) > 0 && owner == result.ID) || (len(owner) == 0 && field.Closest("form", nil) == node) {
+				fields = append(fields, field)
+			}
+		}
+	}
+	sortNodes(fields)
+
+	for _, field := range fields {
+		result.Fields = append(result.Fields, newFormField(field))
+	}
+
+	return result
+}
+
+//
+// newFormField : Build the FormField for the node.
+//
+func newFormField(node *DOMNode) *FormField {
+	result := &FormField{
+		Node:     node,
+		Tag:      node.Tag,
+		Type:     node.Tag,
+		Name:     node.Attr("name"),
+		Value:    node.Attr("value"),
+		Checked:  node.HasAttr("checked"),
+		Disabled: node.HasAttr("disabled"),
+	}
+
+	// a disabled fieldset disables its fields
+	for ancestor := range node.Ancestors() {
+		if ancestor.Tag == "fieldset" && ancestor.HasAttr("disabled") {
+			result.Disabled = true
+			break
+		}
+	}
+
+	switch node.Tag {
+	case "input":
+		result.Type = strings.ToLower(strings.TrimSpace(node.Attr("type")))
+		if len(result.Type) == 0 {
+			result.Type = "text"
+		}
+		if (result.Type == "checkbox" || result.Type == "radio") && !node.HasAttr("value") {
+			result.Value = "on"
+		}
+	case "button":
+		result.Type = strings.ToLower(strings.TrimSpace(node.Attr("type")))
+		if result.Type != "reset" && result.Type != "button" {
+			result.Type = "submit"
+		}
+	case "textarea":
+		result.Value = node.Text()
+	case "select":
+		result.Multiple = node.HasAttr("multiple")
+		for option := range node.Descendants() {
+			if option.Tag != "option" {
+				continue
+			}
+			label := strings.TrimSpace(option.ReaderText())
+			value := label
+			if option.HasAttr("value") {
+				value = option.Attr("value")
+			}
+			result.Options = append(result.Options, &FormOption{
+				Value:    value,
+				Label:    label,
+				Selected: option.HasAttr("selected"),
+				Disabled: option.HasAttr("disabled") || option.Parent.Tag == "optgroup" && option.Parent.HasAttr("disabled"),
+			})
+		}
+		result.Value = ""
+		if selected := result.SelectedValues(); len(selected) > 0 {
+			result.Value = selected[0]
+		}
+	}
+
+	return result
+}
+
+//
+// SelectedValues : The values of the selected options. A single select
+// without an explicit selection selects its first enabled option.
+//
+func (id *FormField) SelectedValues() (result []string) {
+	for _, option := range id.Options {
+		if option.Selected {
+			result = append(result, option.Value)
+		}
+	}
+
+	if len(result) == 0 && !id.Multiple {
+		for _, option := range id.Options {
+			if !option.Disabled {
+				return []string{option.Value}
+			}
+		}
+	}
+	if !id.Multiple && len(result) > 1 {
+		// the last selected option wins in a single select
+		result = result[len(result)-1:]
+	}
+
+	return result
+}
+
+//
+// Field : The first field of the form with the name.
+//
+func (id *Form) Field(name string) *FormField {
+	for _, field := range id.Fields {
+		if field.Name == name {
+			return field
+		}
+	}
+
+	return nil
+}
+
+//
+// DefaultButton : The first enabled submit button of the form, used for
+// implicit submission.
+//
+func (id *Form) DefaultButton() *FormField {
+	for _, field := range id.Fields {
+		if field.isSubmitButton() && !field.Disabled {
+			return field
+		}
+	}
+
+	return nil
+}
+
+//
+// Values : Build the form data set from the field values, as a browser would
+// on submission by the Submitter, then apply the overrides. Disabled and
+// unnamed fields, unchecked checkboxes and radios, and buttons other than the
+// submitter are omitted. An override replaces every value of its name.
+//
+func (id *Form) Values(overrides map[string]string) url.Values {
+	result := url.Values{}
+
+	submitter := id.Submitter
+	if submitter == nil {
+		submitter = id.DefaultButton()
+	}
+
+	for _, field := range id.Fields {
+		if field.Disabled || len(field.Name) == 0 {
+			continue
+		}
+
+		switch {
+		case field.isSubmitButton():
+			if field != submitter {
+				continue
+			}
+			if field.Type == "image" {
+				result.Add(field.Name+".x", "0")
+				result.Add(field.Name+".y", "0")
+			} else {
+				result.Add(field.Name, field.Value)
+			}
+		case field.Type == "reset" || field.Type == "button":
+			continue
+		case field.Type == "checkbox" || field.Type == "radio":
+			if field.Checked {
+				result.Add(field.Name, field.Value)
+			}
+		case field.Type == "file":
+			// file contents are not part of the model
+			result.Add(field.Name, "")
+		case field.Tag == "select":
+			for _, value := range field.SelectedValues() {
+				result.Add(field.Name, value)
+			}
+		default:
+			result.Add(field.Name, field.Value)
+		}
+	}
+
+	for name, value := range overrides {
+		result.Set(name, value)
+	}
+
+	return result
+}
+
+//
+// NewRequest : Build the request submitting the form values with the action
+// resolved against base.
+//
+func (id *Form) NewRequest(base *url.URL) (*http.Request, error) {
+	return id.NewRequestWithValues(base, id.Values(nil))
+}
+
+//
+// NewRequestWithValues : Build the request submitting values with the form
+// method and encoding, and the action resolved against base. The multipart
+// and text/plain bodies list the values in the document order of the fields.
+//
+func (id *Form) NewRequestWithValues(base *url.URL, values url.Values) (*http.Request, error) {
+	action, err := url.Parse(id.Action)
+	if err != nil {
+		return nil, err
+	}
+	if base != nil {
+		action = base.ResolveReference(action)
+	}
+
+	switch id.Method {
+	case "GET":
+		action.RawQuery = values.Encode()
+		return http.NewRequest(http.MethodGet, action.String(), nil)
+	case "POST":
+	default:
+		return nil, ErrFormMethod
+	}
+
+	body := &bytes.Buffer{}
+	contentType := id.Enctype
+	switch id.Enctype {
+	case "multipart/form-data":
+		writer := multipart.NewWriter(body)
+		for _, name := range id.orderedKeys(values) {
+			for _, value := range values[name] {
+				err = writer.WriteField(name, value)
+				if err != nil {
+					return nil, err
+				}
+			}
+		}
+		err = writer.Close()
+		if err != nil {
+			return nil, err
+		}
+		contentType = writer.FormDataContentType()
+	case "text/plain":
+		for _, name := range id.orderedKeys(values) {
+			for _, value := range values[name] {
+				body.WriteString(name + "=" + value + "\r\n")
+			}
+		}
+	default:
+		body.WriteString(values.Encode())
+	}
+
+	request, err := http.NewRequest(http.MethodPost, action.String(), body)
+	if err != nil {
+		return nil, err
+	}
+	request.Header.Set("Content-Type", contentType)
+
+	return request, nil
+}
+
+//
+// FormField: Is the field a button that submits the form?
+//
+func (id *FormField) isSubmitButton() bool {
+	return id.Type == "submit" || id.Type == "image"
+}
+
+//
+// Form: The keys of the values in the document order of their fields, as a
+// browser submits them, followed by the keys without a field in order.
+//
+func (id *Form) orderedKeys(values url.Values) []string {
+	keys := make([]string, 0, len(values))
+	seen := map[string]int{}
+	add := func(key string) {
+		if _, ok := values[key]; ok && seen[key] == 0 {
+			seen[key] = 1
+			keys = append(keys, key)
+		}
+	}
+	for _, field := range id.Fields {
+		if field.Type == "image" {
+			add(field.Name + ".x")
+			add(field.Name + ".y")
+		}
+		add(field.Name)
+	}
+
+	rest := []string{}
+	for key := range values {
+		if seen[key] == 0 {
+			rest = append(rest, key)
+		}
+	}
+	sort.Strings(rest)
+
+	return append(keys, rest...)
+}
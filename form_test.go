@@ -0,0 +1,153 @@
+// Copyright 2016, Marc Lavergne <mlavergn@gmail.com>. All rights reserved.
+// Use of this source code is governed by the MIT
+// license that can be found in the LICENSE file.
+
+package godom
+
+import (
+	"fmt"
+	"io"
+	"net/url"
+	"testing"
+)
+
+func TestForms(t *testing.T) {
+	contents := loadData(t, "test_b.html")
+
+	d := NewDOM()
+	d.SetContents(contents)
+
+	forms := d.Forms()
+	var form *Form
+	for _, f := range forms {
+		if f.ID == "example_connect" {
+			form = f
+		}
+	}
+	if form == nil {
+		d.Dump()
+		t.Fatalf("failed to find FORM")
+	}
+	if len(form.Action) == 0 {
+		t.Errorf("failed to find ACTION")
+	}
+	field := form.Field("user")
+	if field == nil || field.Value != "AAAAAAAA-AAAA-AAAA-AAAA-AAAAAAAAAAAA@private" || field.Type != "hidden" {
+		t.Errorf("failed to parse arguments %+v", field)
+	}
+}
+
+func TestFormFields(t *testing.T) {
+	d := NewDOM()
+	d.SetContents(`<html><body>
+<form id="f" action="/login" method="post">
+  <input name="user" value="bob">
+  <input type="checkbox" name="remember" checked>
+  <fieldset disabled><input name="legacy" value="x"></fieldset>
+  <select name="lang"><option value="en">English</option><option selected>French</option></select>
+  <select name="tags" multiple><option>a</option><optgroup disabled><option selected>b</option></optgroup></select>
+  <textarea name="note">Hello</textarea>
+  <button name="go" value="1">Go</button>
+</form>
+<input form="f" name="outside" value="y">
+<form><input name="other"></form>
+</body></html>`)
+
+	forms := d.Forms()
+	if len(forms) != 2 {
+		d.Dump()
+		t.Fatalf("failed to find forms %d", len(forms))
+	}
+
+	form := forms[0]
+	if form.Method != "POST" || form.Enctype != "application/x-www-form-urlencoded" || forms[1].Method != "GET" {
+		t.Errorf("failed to default method and encoding %+v", form)
+	}
+
+	names := []string{}
+	for _, field := range form.Fields {
+		names = append(names, field.Name)
+	}
+	if fmt.Sprint(names) != "[user remember legacy lang tags note go outside]" {
+		t.Errorf("failed to collect fields %v", names)
+	}
+
+	if f := form.Field("remember"); f.Type != "checkbox" || !f.Checked || f.Value != "on" {
+		t.Errorf("failed to parse checkbox %+v", f)
+	}
+	if !form.Field("legacy").Disabled || form.Field("user").Disabled {
+		t.Errorf("failed to inherit fieldset disabled")
+	}
+	if f := form.Field("lang"); f.Value != "French" || len(f.Options) != 2 || f.Options[0].Value != "en" {
+		t.Errorf("failed to parse select %+v", f)
+	}
+	if f := form.Field("tags"); !f.Multiple || !f.Options[1].Disabled || fmt.Sprint(f.SelectedValues()) != "[b]" {
+		t.Errorf("failed to parse multiple select %+v", f)
+	}
+	if form.Field("note").Value != "Hello" || form.Field("go").Type != "submit" {
+		t.Errorf("failed to parse textarea and button")
+	}
+}
+
+func TestFormValues(t *testing.T) {
+	d := NewDOM()
+	d.SetContents(`<html><body>
+<form action="login?x=1" method="post">
+  <input name="user" value="">
+  <input type="password" name="password">
+  <input type="checkbox" name="remember" value="yes">
+  <input name="token" value="abc" disabled>
+  <input type="hidden" name="cmd" value="authenticate">
+  <select name="lang"><option>en</option><option>fr</option></select>
+  <input type="submit" name="action" value="login">
+  <button name="action" value="register">Register</button>
+</form>
+<form action="/search"><input name="q" value="go dom"></form>
+</body></html>`)
+
+	forms := d.Forms()
+	if len(forms) != 2 {
+		d.Dump()
+		t.Fatalf("failed to find forms %d", len(forms))
+	}
+	form := forms[0]
+
+	values := form.Values(map[string]string{"user": "bob", "password": "secret"})
+	if values.Encode() != "action=login&cmd=authenticate&lang=en&password=secret&user=bob" {
+		t.Errorf("failed to build values %s", values.Encode())
+	}
+
+	form.Submitter = form.Fields[len(form.Fields)-1]
+	form.Field("remember").Checked = true
+	values = form.Values(nil)
+	if values.Get("action") != "register" || values.Get("remember") != "yes" || values.Has("token") {
+		t.Errorf("failed to build values for submitter %s", values.Encode())
+	}
+
+	base, _ := url.Parse("https://example.com/account/")
+	request, err := form.NewRequest(base)
+	if err != nil {
+		t.Fatalf("failed to build request %s", err)
+	}
+	body, _ := io.ReadAll(request.Body)
+	if request.Method != "POST" || request.URL.String() != "https://example.com/account/login?x=1" ||
+		request.Header.Get("Content-Type") != "application/x-www-form-urlencoded" || string(body) != values.Encode() {
+		t.Errorf("failed to build POST request %s %s %s", request.Method, request.URL, body)
+	}
+
+	request, err = forms[1].NewRequest(base)
+	if err != nil || request.Method != "GET" || request.URL.String() != "https://example.com/search?q=go+dom" {
+		t.Errorf("failed to build GET request %v %s", err, request.URL)
+	}
+
+	// the other encodings follow the document order of the fields
+	form.Enctype = "text/plain"
+	request, err = form.NewRequestWithValues(base, url.Values{"z": {"1"}, "user": {"bob"}, "action": {"register"}, "cmd": {"go"}})
+	if err != nil {
+		t.Fatalf("failed to build request %s", err)
+	}
+	body, _ = io.ReadAll(request.Body)
+	if string(body) != "user=bob\r\ncmd=go\r\naction=register\r\nz=1\r\n" {
+		t.Errorf("failed to order text/plain body %q", body)
+	}
+}
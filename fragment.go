@@ -0,0 +1,73 @@
+// Copyright 2016 Marc Lavergne <mlavergn@gmail.com>. All rights reserved.
+// Use of this source code is governed by
+// license that can be found in the LICENSE file.
+
+package godom
+
+import (
+	"golang.org/x/net/html"
+	"golang.org/x/net/html/atom"
+	"strings"
+)
+
+//
+// ParseFragment : Parse the HTML snippet as the content of the context node,
+// which decides how it is parsed, eg. a tr is only kept within a table. A nil
+// context parses the snippet as the content of a body. The top level elements
+// are returned detached and numbered in document order from 1, ready to be
+// inserted with AppendChild or InsertBefore. Text and comments outside of the
+// top level elements are dropped. The parse options of the DOM apply.
+//
+func (id *DOM) ParseFragment(context *DOMNode, contents string) ([]*DOMNode, error) {
+	tag := "body"
+	if context != nil {
+		tag = strings.ToLower(context.Tag)
+	}
+	contextNode := &html.Node{Type: html.ElementNode, Data: tag, DataAtom: atom.Lookup([]byte(tag))}
+
+	scratch := NewDOM()
+	scratch.options = id.options
+	scratch.tags = id.tags
+	holder := &DOMNode{Children: []*DOMNode{}}
+	err := scratch.parseHTMLFragment(holder, contextNode, contents)
+	if err == nil {
+		err = scratch.parseErr
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	for _, node := range holder.Children {
+		node.Parent = nil
+		for i, subNode := range node.elementNodes() {
+			subNode.Index = i + 1
+			subNode.dom = nil
+		}
+	}
+
+	return holder.Children, nil
+}
+
+//
+// NewFragment : A DOM holding the HTML snippet without the html, head, and
+// body elements implied by a full parse, its top level elements have no
+// parent. RootNode is nil, so Find searches every node.
+//
+func NewFragment(contents string) (*DOM, error) {
+	result := NewDOM()
+	nodes, err := result.ParseFragment(nil, contents)
+	if err != nil {
+		return nil, err
+	}
+
+	result.contents = contents
+	for _, node := range nodes {
+		result.document = append(result.document, node.elementNodes()...)
+	}
+	for _, node := range result.document {
+		node.dom = &result
+	}
+	result.reindex()
+
+	return &result, nil
+}
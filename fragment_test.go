@@ -0,0 +1,55 @@
+// Copyright 2016, Marc Lavergne <mlavergn@gmail.com>. All rights reserved.
+// Use of this source code is governed by the MIT
+// license that can be found in the LICENSE file.
+
+package godom
+
+import (
+	"testing"
+)
+
+func TestParseFragment(t *testing.T) {
+	d := NewDOM()
+	d.SetContents(`<html><body><table><tbody id="rows"></tbody></table></body></html>`)
+	tbody := d.Find("tbody", nil)[0]
+
+	nodes, err := d.ParseFragment(tbody, `<tr><td>1</td></tr><tr><td>2</td></tr>`)
+	if err != nil || len(nodes) != 2 {
+		t.Fatalf("failed to parse fragment %v %d", err, len(nodes))
+	}
+	if nodes[0].Tag != "tr" || nodes[0].Parent != nil || nodes[0].Index != 1 || nodes[0].Children[0].Index != 2 {
+		t.Errorf("unexpected fragment node %s", nodes[0])
+	}
+	for _, node := range nodes {
+		if err := tbody.AppendChild(node); err != nil {
+			t.Fatalf("failed to insert fragment %v", err)
+		}
+	}
+	checkIndexes(t, &d)
+	if len(d.Find("td", nil)) != 2 || tbody.InnerHTML() != "<tr><td>1</td></tr><tr><td>2</td></tr>" {
+		t.Errorf("unexpected rows [%s]", tbody.InnerHTML())
+	}
+
+	// without a table context the rows are dropped by the parser
+	nodes, err = d.ParseFragment(nil, `<tr><td>1</td></tr><p>text</p>`)
+	if err != nil || len(nodes) != 1 || nodes[0].Tag != "p" {
+		t.Errorf("unexpected body fragment %d", len(nodes))
+	}
+}
+
+func TestNewFragment(t *testing.T) {
+	d, err := NewFragment(`<p class="a">One</p><p>Two <b>three</b></p>`)
+	if err != nil {
+		t.Fatalf("failed to parse fragment %v", err)
+	}
+	checkIndexes(t, d)
+
+	p := d.Find("p", nil)
+	if len(p) != 2 || p[0].Parent != nil || d.RootNode() != nil || len(d.Find("html", nil)) != 0 {
+		d.Dump()
+		t.Fatalf("unexpected fragment")
+	}
+	if len(d.Find("b", nil)) != 1 || d.Find("p", DOMNodeAttributes{"class": "a"})[0] != p[0] {
+		t.Errorf("failed to index fragment")
+	}
+}
@@ -0,0 +1,115 @@
+// Copyright 2016 Marc Lavergne <mlavergn@gmail.com>. All rights reserved.
+// Use of this source code is governed by
+// license that can be found in the LICENSE file.
+
+package godom
+
+import (
+	"net/url"
+	"strings"
+)
+
+// FrameOptions def
+// Controls the frames listed by Frames
+type FrameOptions struct {
+	// ParseSrcdoc parses the srcdoc of each iframe into Document, and the
+	// frames of that document into Frames, with the options of the DOM
+	ParseSrcdoc bool
+}
+
+// Frame def
+// An iframe, frame, embed, or object with its URL resolved against the base
+type Frame struct {
+	Node *DOMNode
+	Tag  string
+	// Src is the src, or the data of an object
+	Src string
+	// URL is the resolved src, nil when the src is missing or can't be parsed
+	URL   *url.URL
+	Title string
+	// Sandbox holds the lowercased sandbox tokens, nil when not sandboxed
+	Sandbox []string
+	Srcdoc  string
+	// Document is the parsed srcdoc, nil unless requested
+	Document *DOM
+	// Err is the error of the srcdoc parse, eg. ErrMaxNodes, the Document
+	// then holds what the parse kept
+	Err error
+	// Frames are the frames of the parsed srcdoc
+	Frames []*Frame
+}
+
+//
+// Frames : The iframes, frames, embeds, and objects of the document in
+// document order.
+//
+func (id *DOM) Frames(opts FrameOptions) (result []*Frame) {
+	base := id.BaseURL()
+	for _, node := range id.FindAny([]string{"iframe", "frame", "embed", "object"}, nil) {
+		frame := &Frame{
+			Node:  node,
+			Tag:   node.Tag,
+			Src:   strings.TrimSpace(node.Attr("src")),
+			Title: strings.TrimSpace(node.Attr("title")),
+		}
+		if node.Tag == "object" {
+			frame.Src = strings.TrimSpace(node.Attr("data"))
+		}
+		if len(frame.Src) > 0 {
+			frame.URL = resolveURL(base, frame.Src)
+		}
+		if node.HasAttr("sandbox") {
+			frame.Sandbox = strings.Fields(strings.ToLower(node.Attr("sandbox")))
+		}
+
+		if _, srcdoc, ok := node.lookupAttr("srcdoc"); ok && node.Tag == "iframe" {
+			frame.Srcdoc = srcdoc
+			if opts.ParseSrcdoc {
+				frame.Document, frame.Err = id.parseSrcdoc(srcdoc, base)
+				frame.Frames = frame.Document.Frames(opts)
+			}
+		}
+		result = append(result, frame)
+	}
+
+	return result
+}
+
+//
+// Sandboxed : Does the frame carry a sandbox attribute?
+//
+func (id *Frame) Sandboxed() bool {
+	return id.Sandbox != nil
+}
+
+//
+// Allows : Does the sandbox allow the flag, eg. allow-scripts? Frames without
+// a sandbox allow everything.
+//
+func (id *Frame) Allows(flag string) bool {
+	if id.Sandbox == nil {
+		return true
+	}
+	flag = strings.ToLower(flag)
+	for _, token := range id.Sandbox {
+		if token == flag {
+			return true
+		}
+	}
+
+	return false
+}
+
+//
+// DOM: Parse the srcdoc with the options of the DOM, its relative URLs
+// resolve against the base of the DOM.
+//
+func (id *DOM) parseSrcdoc(srcdoc string, base *url.URL) (*DOM, error) {
+	result := NewDOM()
+	result.SetParseOptions(id.options)
+	result.url = base
+	result.logger = id.logger
+	err := result.SetContents(srcdoc)
+
+	return &result, err
+}
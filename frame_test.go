@@ -0,0 +1,54 @@
+// Copyright 2016, Marc Lavergne <mlavergn@gmail.com>. All rights reserved.
+// Use of this source code is governed by the MIT
+// license that can be found in the LICENSE file.
+
+package godom
+
+import (
+	"net/url"
+	"testing"
+)
+
+func TestFrames(t *testing.T) {
+	d := NewDOM()
+	d.SetContents(`<html><body>
+<iframe src="/embed/1" title="Player" sandbox="allow-scripts Allow-Same-Origin"></iframe>
+<embed src="movie.swf">
+<object data="doc.pdf"></object>
+<iframe sandbox srcdoc="&lt;p&gt;Hi&lt;/p&gt;&lt;iframe src=&quot;inner.html&quot;&gt;&lt;/iframe&gt;"></iframe>
+</body></html>`)
+	d.url, _ = url.Parse("https://example.com/a/")
+
+	frames := d.Frames(FrameOptions{})
+	if len(frames) != 4 {
+		d.Dump()
+		t.Fatalf("unexpected frames %d", len(frames))
+	}
+	if frames[0].URL.String() != "https://example.com/embed/1" || frames[0].Title != "Player" || !frames[0].Allows("allow-same-origin") || frames[0].Allows("allow-forms") {
+		t.Errorf("unexpected frame %+v", frames[0])
+	}
+	if frames[1].Tag != "embed" || frames[1].Sandboxed() || !frames[1].Allows("allow-forms") || frames[2].URL.String() != "https://example.com/a/doc.pdf" {
+		t.Errorf("unexpected embed or object")
+	}
+	srcdoc := frames[3]
+	if !srcdoc.Sandboxed() || srcdoc.Allows("allow-scripts") || srcdoc.Document != nil || srcdoc.Srcdoc != "<p>Hi</p><iframe src=\"inner.html\"></iframe>" {
+		t.Errorf("unexpected srcdoc frame %+v", srcdoc)
+	}
+
+	frames = d.Frames(FrameOptions{ParseSrcdoc: true})
+	srcdoc = frames[3]
+	if srcdoc.Document == nil || srcdoc.Document.Find("p", nil)[0].Text() != "Hi" {
+		t.Fatalf("failed to parse srcdoc")
+	}
+	if len(srcdoc.Frames) != 1 || srcdoc.Frames[0].URL.String() != "https://example.com/a/inner.html" {
+		t.Errorf("failed to list nested frames")
+	}
+	if srcdoc.Err != nil {
+		t.Errorf("unexpected srcdoc error %v", srcdoc.Err)
+	}
+
+	d.SetParseOptions(ParseOptions{MaxNodes: 3})
+	if frames = d.Frames(FrameOptions{ParseSrcdoc: true}); frames[3].Err != ErrMaxNodes {
+		t.Errorf("expected the srcdoc limit error, got %v", frames[3].Err)
+	}
+}
@@ -0,0 +1,109 @@
+// Copyright 2016 Marc Lavergne <mlavergn@gmail.com>. All rights reserved.
+// Use of this source code is governed by
+// license that can be found in the LICENSE file.
+
+package godom
+
+import (
+	"sort"
+	"strings"
+	"unicode"
+)
+
+//
+// FindFuzzy : Find the Node of type tag whose text is similar to text, with a
+// TextSimilarity of at least threshold, in document order
+//
+func (id *DOM) FindFuzzy(tag string, text string, threshold float64) (result []*DOMNode) {
+	return id.ChildFindFuzzy(id.RootNode(), tag, text, threshold)
+}
+
+//
+// ChildFindFuzzy : Find the child Node of type tag whose text is similar to
+// text, with a TextSimilarity of at least threshold, in document order
+//
+func (id *DOM) ChildFindFuzzy(parent *DOMNode, tag string, text string, threshold float64) (result []*DOMNode) {
+	key := fuzzyTokens(text)
+	tagNodes := id.tagNodes(tag)
+	for _, node := range tagNodes {
+		if id.IsDescendantNode(parent, node) && fuzzyRatio(key, fuzzyTokens(node.Text())) >= threshold {
+			result = append(result, node)
+		}
+	}
+
+	return result
+}
+
+//
+// TextSimilarity : The similarity of the texts from 0 to 1, ignoring case,
+// punctuation, and spacing. The better of the normalized Levenshtein ratio of
+// the words in order and of the words sorted, so reordered words match.
+//
+func TextSimilarity(a string, b string) float64 {
+	return fuzzyRatio(fuzzyTokens(a), fuzzyTokens(b))
+}
+
+//
+// fuzzyTokens : The lower case words of the text, without punctuation.
+//
+func fuzzyTokens(text string) []string {
+	return strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+}
+
+//
+// fuzzyRatio : The similarity of the words in order or sorted.
+//
+func fuzzyRatio(a []string, b []string) float64 {
+	ratio := levenshteinRatio(strings.Join(a, " "), strings.Join(b, " "))
+	if ratio == 1 {
+		return ratio
+	}
+
+	a = append([]string(nil), a...)
+	b = append([]string(nil), b...)
+	sort.Strings(a)
+	sort.Strings(b)
+	if sorted := levenshteinRatio(strings.Join(a, " "), strings.Join(b, " ")); sorted > ratio {
+		ratio = sorted
+	}
+
+	return ratio
+}
+
+//
+// levenshteinRatio : 1 less the edit distance of the runes over the longer
+// length, 1 for two empty strings.
+//
+func levenshteinRatio(a string, b string) float64 {
+	ra, rb := []rune(a), []rune(b)
+	longest := len(ra)
+	if len(rb) > longest {
+		longest = len(rb)
+	}
+	if longest == 0 {
+		return 1
+	}
+
+	// a single row of the distance matrix
+	row := make([]int, len(rb)+1)
+	for j := range row {
+		row[j] = j
+	}
+	for i := 1; i <= len(ra); i++ {
+		diagonal := row[0]
+		row[0] = i
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			above := row[j]
+			row[j] = min(row[j]+1, row[j-1]+1, diagonal+cost)
+			diagonal = above
+		}
+	}
+
+	return 1 - float64(row[len(rb)])/float64(longest)
+}
@@ -0,0 +1,31 @@
+// Copyright 2016, Marc Lavergne <mlavergn@gmail.com>. All rights reserved.
+// Use of this source code is governed by the MIT
+// license that can be found in the LICENSE file.
+
+package godom
+
+import (
+	"testing"
+)
+
+func TestFindFuzzy(t *testing.T) {
+	d := NewDOM()
+	d.SetContents("<html><body><button>Add to Cart!</button><button>Add to wishlist</button><a>Cart: add to</a><button>Checkout</button></body></html>")
+
+	if found := d.FindFuzzy("button", "Add to cart", 0.9); len(found) != 1 || found[0].Text() != "Add to Cart!" {
+		t.Errorf("unexpected matches %v", found)
+	}
+	if found := d.FindFuzzy("*", "add to cart", 0.9); len(found) != 2 || found[1].Tag != "a" {
+		t.Errorf("expected the reordered words to match %v", found)
+	}
+	if found := d.FindFuzzy("button", "Add to cart", 0.5); len(found) != 2 {
+		t.Errorf("expected 2 loose matches found %d", len(found))
+	}
+
+	if ratio := TextSimilarity("kitten", "sitting"); ratio < 0.57 || ratio > 0.58 {
+		t.Errorf("unexpected ratio %f", ratio)
+	}
+	if TextSimilarity("", "") != 1 || TextSimilarity("abc", "") != 0 || TextSimilarity("Café!", "café") != 1 {
+		t.Error("unexpected edge ratios")
+	}
+}
@@ -0,0 +1,211 @@
+// Copyright 2016 Marc Lavergne <mlavergn@gmail.com>. All rights reserved.
+// Use of this source code is governed by
+// license that can be found in the LICENSE file.
+
+package godom
+
+import (
+	"bytes"
+	"compress/flate"
+	"compress/gzip"
+	"compress/zlib"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// DefaultUserAgent sent by Load unless overridden
+const DefaultUserAgent = "Mozilla/5.0 (compatible; godom)"
+
+// StatusError def
+// A non-2xx response to Load
+type StatusError struct {
+	StatusCode int
+	Status     string
+}
+
+//
+// StatusError: Error representation.
+//
+func (id *StatusError) Error() string {
+	return fmt.Sprintf("godom: unexpected status %s", id.Status)
+}
+
+// LoadOption type
+// Configures a Load request
+type LoadOption func(config *loadConfig)
+
+// loadConfig the settings of a Load request
+type loadConfig struct {
+	client        *http.Client
+	timeout       time.Duration
+	userAgent     string
+	header        http.Header
+	checkRedirect func(req *http.Request, via []*http.Request) error
+}
+
+//
+// LoadWithClient : Send the request with client instead of http.DefaultClient.
+//
+func LoadWithClient(client *http.Client) LoadOption {
+	return func(config *loadConfig) {
+		config.client = client
+	}
+}
+
+//
+// LoadWithTimeout : Bound the request, including reading the body, by timeout.
+//
+func LoadWithTimeout(timeout time.Duration) LoadOption {
+	return func(config *loadConfig) {
+		config.timeout = timeout
+	}
+}
+
+//
+// LoadWithUserAgent : Send userAgent as the User-Agent header.
+//
+func LoadWithUserAgent(userAgent string) LoadOption {
+	return func(config *loadConfig) {
+		config.userAgent = userAgent
+	}
+}
+
+//
+// LoadWithHeader : Send an additional request header.
+//
+func LoadWithHeader(key string, value string) LoadOption {
+	return func(config *loadConfig) {
+		config.header.Add(key, value)
+	}
+}
+
+//
+// LoadWithRedirectPolicy : Use checkRedirect as the http.Client CheckRedirect
+// policy, eg. returning http.ErrUseLastResponse to not follow redirects.
+//
+func LoadWithRedirectPolicy(checkRedirect func(req *http.Request, via []*http.Request) error) LoadOption {
+	return func(config *loadConfig) {
+		config.checkRedirect = checkRedirect
+	}
+}
+
+//
+// Load : Fetch rawURL and parse the response with NewDOMFromResponse. A
+// non-2xx response is reported as a *StatusError.
+//
+func Load(ctx context.Context, rawURL string, opts ...LoadOption) (*DOM, error) {
+	config := &loadConfig{
+		client:    http.DefaultClient,
+		userAgent: DefaultUserAgent,
+		header:    http.Header{},
+	}
+	for _, opt := range opts {
+		opt(config)
+	}
+
+	if config.timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, config.timeout)
+		defer cancel()
+	}
+
+	client := config.client
+	if config.checkRedirect != nil {
+		// don't modify the caller's client
+		copied := *client
+		copied.CheckRedirect = config.checkRedirect
+		client = &copied
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, rawURL, nil)
+	if err != nil {
+		return nil, err
+	}
+	for key, values := range config.header {
+		req.Header[key] = values
+	}
+	req.Header.Set("User-Agent", config.userAgent)
+	if len(req.Header.Get("Accept-Encoding")) == 0 {
+		req.Header.Set("Accept-Encoding", "gzip, deflate")
+	}
+
+	logger := DefaultLogger()
+	logger.Debug("godom: load", "url", rawURL)
+	resp, err := client.Do(req)
+	if err != nil {
+		logger.Error("godom: load failed", "url", rawURL, "error", err)
+		return nil, err
+	}
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		resp.Body.Close()
+		logger.Warn("godom: load failed", "url", rawURL, "status", resp.Status)
+		return nil, &StatusError{StatusCode: resp.StatusCode, Status: resp.Status}
+	}
+
+	return NewDOMFromResponse(resp)
+}
+
+//
+// NewDOMFromResponse : Read and close the response body, decompressing gzip
+// and deflate content encodings, decode it using the Content-Type header and
+// any <meta charset>, and parse it. The final request URL is recorded as the
+// DOM URL for relative link resolution.
+//
+func NewDOMFromResponse(resp *http.Response) (*DOM, error) {
+	defer resp.Body.Close()
+
+	body, err := readResponseBody(resp)
+	if err != nil {
+		return nil, err
+	}
+
+	dom := NewDOM()
+	if resp.Request != nil && resp.Request.URL != nil {
+		dom.url = resp.Request.URL
+	}
+	err = dom.SetContentsWithContentType(body, resp.Header.Get("Content-Type"))
+
+	return &dom, err
+}
+
+//
+// URL : The URL the document was fetched from, nil when unknown.
+//
+func (id *DOM) URL() *url.URL {
+	return id.url
+}
+
+//
+// readResponseBody : Read the body, undoing the content encoding.
+//
+func readResponseBody(resp *http.Response) ([]byte, error) {
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	switch strings.ToLower(strings.TrimSpace(resp.Header.Get("Content-Encoding"))) {
+	case "gzip", "x-gzip":
+		reader, err := gzip.NewReader(bytes.NewReader(body))
+		if err != nil {
+			return nil, err
+		}
+		defer reader.Close()
+		return io.ReadAll(reader)
+	case "deflate":
+		// deflate is specified as zlib wrapped, but raw streams are common
+		reader, err := zlib.NewReader(bytes.NewReader(body))
+		if err != nil {
+			reader = flate.NewReader(bytes.NewReader(body))
+		}
+		defer reader.Close()
+		return io.ReadAll(reader)
+	}
+
+	return body, nil
+}
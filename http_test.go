@@ -0,0 +1,92 @@
+// Copyright 2016, Marc Lavergne <mlavergn@gmail.com>. All rights reserved.
+// Use of this source code is governed by the MIT
+// license that can be found in the LICENSE file.
+
+package godom
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+)
+
+func TestNewDOMFromResponse(t *testing.T) {
+	compressed := bytes.Buffer{}
+	writer := gzip.NewWriter(&compressed)
+	writer.Write([]byte("<html><body><a href=\"next.html\">caf\xe9</a></body></html>"))
+	writer.Close()
+
+	requestURL, _ := url.Parse("https://example.com/dir/page.html")
+	resp := &http.Response{
+		StatusCode: 200,
+		Header: http.Header{
+			"Content-Type":     {"text/html; charset=iso-8859-1"},
+			"Content-Encoding": {"gzip"},
+		},
+		Body:    io.NopCloser(&compressed),
+		Request: &http.Request{URL: requestURL},
+	}
+
+	d, err := NewDOMFromResponse(resp)
+	if err != nil {
+		t.Fatalf("failed to parse response %s", err)
+	}
+	if d.URL() != requestURL {
+		t.Errorf("failed to record URL %s", d.URL())
+	}
+
+	links := d.Links(nil)
+	if len(links) != 1 || links[0].Text != "café" || links[0].URL.String() != "https://example.com/dir/next.html" {
+		d.Dump()
+		t.Errorf("failed to decode and resolve links")
+	}
+}
+
+func TestLoad(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/old":
+			http.Redirect(w, r, "/new", http.StatusFound)
+		case "/new":
+			w.Header().Set("Content-Type", "text/html")
+			fmt.Fprintf(w, "<html><body><p id=\"ua\">%s</p><a href=\"rel\">x</a></body></html>", r.Header.Get("User-Agent"))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	d, err := Load(context.Background(), server.URL+"/old", LoadWithUserAgent("test-agent"), LoadWithTimeout(5*time.Second))
+	if err != nil {
+		t.Fatalf("failed to load %s", err)
+	}
+	if d.URL().Path != "/new" {
+		t.Errorf("failed to record final URL %s", d.URL())
+	}
+	if p := d.Find("p", map[string]string{"id": "ua"}); len(p) != 1 || p[0].Text() != "test-agent" {
+		d.Dump()
+		t.Errorf("failed to send User-Agent")
+	}
+	if links := d.Links(nil); len(links) != 1 || links[0].URL.String() != server.URL+"/rel" {
+		t.Errorf("failed to resolve against final URL")
+	}
+
+	_, err = Load(context.Background(), server.URL+"/old", LoadWithRedirectPolicy(func(req *http.Request, via []*http.Request) error {
+		return http.ErrUseLastResponse
+	}))
+	if status, ok := err.(*StatusError); !ok || status.StatusCode != http.StatusFound {
+		t.Errorf("failed to apply redirect policy %v", err)
+	}
+
+	_, err = Load(context.Background(), server.URL+"/missing")
+	if status, ok := err.(*StatusError); !ok || status.StatusCode != http.StatusNotFound {
+		t.Errorf("failed to report status %v", err)
+	}
+}
@@ -0,0 +1,122 @@
+// Copyright 2016 Marc Lavergne <mlavergn@gmail.com>. All rights reserved.
+// Use of this source code is governed by
+// license that can be found in the LICENSE file.
+
+package godom
+
+import (
+	"strings"
+)
+
+//
+// ByID : The first element in document order whose id attribute is
+// elementID, or nil.
+//
+func (id *DOM) ByID(elementID string) *DOMNode {
+	return id.ids[elementID]
+}
+
+//
+// DOM: Add the node to the id index unless an earlier node holds its id.
+//
+func (id *DOM) indexID(domNode *DOMNode) {
+	_, elementID, ok := domNode.lookupAttr("id")
+	if !ok || len(elementID) == 0 || pseudoTags[domNode.Tag] == 1 {
+		return
+	}
+	if current, ok := id.ids[elementID]; !ok || current.Index > domNode.Index {
+		id.ids[elementID] = domNode
+	}
+}
+
+//
+// DOM: Update the id index after the id of the node changed from old.
+//
+func (id *DOM) idChanged(domNode *DOMNode, old string) {
+	if len(old) > 0 && id.ids[old] == domNode {
+		// the old id passes to the next element holding it
+		delete(id.ids, old)
+		for _, node := range id.document {
+			if node != domNode && pseudoTags[node.Tag] == 0 && node.Attr("id") == old {
+				id.ids[old] = node
+				break
+			}
+		}
+	}
+	id.indexID(domNode)
+}
+
+//
+// FindByAttr : Find the Nodes carrying the attribute name, eg. itemprop.
+// Backed by an index when the IndexAttributes parse option is set.
+//
+func (id *DOM) FindByAttr(name string) (result []*DOMNode) {
+	return id.ChildFindByAttr(id.RootNode(), name)
+}
+
+//
+// ChildFindByAttr : Find the child Nodes carrying the attribute name.
+//
+func (id *DOM) ChildFindByAttr(parent *DOMNode, name string) (result []*DOMNode) {
+	name = attrKey(name)
+
+	candidates := id.document
+	if id.options.IndexAttributes {
+		candidates = id.attrs[name]
+	}
+	for _, node := range candidates {
+		if _, _, ok := node.lookupAttr(name); ok && pseudoTags[node.Tag] == 0 && id.IsDescendantNode(parent, node) {
+			result = append(result, node)
+		}
+	}
+
+	return result
+}
+
+//
+// DOM: Add the element to the attribute index when enabled.
+//
+func (id *DOM) indexAttributes(domNode *DOMNode) {
+	if !id.options.IndexAttributes || pseudoTags[domNode.Tag] == 1 {
+		return
+	}
+	if id.attrs == nil {
+		id.attrs = map[string][]*DOMNode{}
+	}
+	for key := range domNode.Attributes {
+		key = strings.ToLower(key)
+		id.attrs[key] = append(id.attrs[key], domNode)
+	}
+}
+
+//
+// DOMNode: Update the indexes of the owning DOM after the attribute key
+// changed from old.
+//
+func (id *DOMNode) attrChanged(key string, old string) {
+	if id.dom == nil {
+		return
+	}
+	id.dom.invalidateFinds()
+
+	if strings.EqualFold(key, "id") {
+		id.dom.idChanged(id, old)
+	}
+
+	if id.dom.options.IndexAttributes && pseudoTags[id.Tag] == 0 {
+		// move the node in or out of the entries of the key by its position
+		key = strings.ToLower(key)
+		if id.dom.attrs == nil {
+			id.dom.attrs = map[string][]*DOMNode{}
+		}
+		nodes := removeIndexed(id.dom.attrs[key], id)
+		if _, _, ok := id.lookupAttr(key); ok {
+			nodes = insertIndexed(nodes, id)
+		}
+		if len(nodes) == 0 {
+			delete(id.dom.attrs, key)
+		} else {
+			id.dom.attrs[key] = nodes
+		}
+	}
+}
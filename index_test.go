@@ -0,0 +1,83 @@
+// Copyright 2016, Marc Lavergne <mlavergn@gmail.com>. All rights reserved.
+// Use of this source code is governed by the MIT
+// license that can be found in the LICENSE file.
+
+package godom
+
+import (
+	"testing"
+)
+
+func TestByID(t *testing.T) {
+	d := NewDOM()
+	d.SetContents("<html><body><div id=\"a\">first</div><p id=\"a\">second</p><span id=\"b\">b</span></body></html>")
+
+	node := d.ByID("a")
+	if node == nil || node.Tag != "div" {
+		d.Dump()
+		t.Fatalf("failed to find first node by id")
+	}
+	if d.ByID("missing") != nil || d.ByID("") != nil {
+		t.Errorf("failed to miss unknown id")
+	}
+
+	node.SetAttr("id", "c")
+	if d.ByID("a") == nil || d.ByID("a").Tag != "p" || d.ByID("c") != node {
+		t.Errorf("failed to update index on attribute change")
+	}
+
+	span := d.ByID("b")
+	err := span.Parent.RemoveChild(span)
+	if err != nil || d.ByID("b") != nil {
+		t.Errorf("failed to update index on removal")
+	}
+
+	// the id key keeps its case when preserved
+	d = NewDOM(WithPreserveCase())
+	d.SetContents("<html><body><div ID=\"a\">first</div><p id=\"a\">second</p></body></html>")
+	div := d.ByID("a")
+	if div == nil || div.Tag != "div" {
+		t.Fatalf("failed to find the preserved case id")
+	}
+	div.SetAttr("ID", "c")
+	if d.ByID("a") == nil || d.ByID("a").Tag != "p" || d.ByID("c") != div {
+		t.Errorf("failed to update index on preserved case id change")
+	}
+	div.RemoveAttr("id")
+	if d.ByID("c") != nil {
+		t.Errorf("failed to update index on preserved case id removal")
+	}
+}
+
+func TestFindByAttr(t *testing.T) {
+	contents := "<html><body><div itemprop=\"a\"><span data-sku=\"1\">x</span><span>y</span><p data-sku=\"2\"></p></div></body></html>"
+	for _, indexed := range []bool{false, true} {
+		d := NewDOM()
+		d.SetParseOptions(ParseOptions{IndexAttributes: indexed})
+		d.SetContents(contents)
+
+		nodes := d.FindByAttr("data-sku")
+		if len(nodes) != 2 || nodes[0].Tag != "span" || nodes[1].Tag != "p" {
+			d.Dump()
+			t.Fatalf("failed to find by attribute [indexed %t]", indexed)
+		}
+
+		nodes[0].RemoveAttr("data-sku")
+		d.Find("span", nil)[1].SetAttr("data-sku", "3")
+		nodes = d.FindByAttr("data-sku")
+		if len(nodes) != 2 || nodes[0].Text() != "y" {
+			t.Errorf("failed to track attribute changes [indexed %t]", indexed)
+		}
+		if len(d.ChildFindByAttr(d.Find("span", nil)[0], "itemprop")) != 0 {
+			t.Errorf("failed to scope to parent [indexed %t]", indexed)
+		}
+
+		// set in reverse document order, the entries keep document order
+		d.Find("div", nil)[0].SetAttr("data-sku", "4")
+		d.Find("span", nil)[1].RenameAttr("data-sku", "data-id")
+		nodes = d.FindByAttr("data-sku")
+		if len(nodes) != 2 || nodes[0].Tag != "div" || nodes[1].Tag != "p" || len(d.FindByAttr("data-id")) != 1 {
+			t.Errorf("failed to order attribute changes [indexed %t] %v", indexed, nodes)
+		}
+	}
+}
@@ -0,0 +1,529 @@
+// Copyright 2016 Marc Lavergne <mlavergn@gmail.com>. All rights reserved.
+// Use of this source code is governed by
+// license that can be found in the LICENSE file.
+
+package godom
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf16"
+	"unicode/utf8"
+)
+
+// ErrScriptObject error returned when the script value found is not an object
+var ErrScriptObject = errors.New("godom: script value is not an object")
+
+//
+// ParseJSLiteral : Parse the JavaScript object, array, or scalar literal at the
+// start of s as encoding/json would decode the equivalent JSON into an
+// interface{}: objects as map[string]interface{}, arrays as []interface{},
+// and numbers as float64. Unquoted and numeric keys, single quoted and
+// template strings without substitutions, hex numbers, trailing commas, and
+// comments are accepted. undefined, NaN, and Infinity decode as nil. The text
+// following the literal is ignored.
+//
+func ParseJSLiteral(s string) (interface{}, error) {
+	value, _, err := parseJSLiteral(s)
+	return value, err
+}
+
+//
+// parseJSLiteral : Parse the literal at the start of s, returning the value
+// and the offset following it.
+//
+func parseJSLiteral(s string) (interface{}, int, error) {
+	parser := &jsParser{s: s}
+	value, err := parser.value(0)
+	if err != nil {
+		return nil, parser.pos, err
+	}
+
+	return value, parser.pos, nil
+}
+
+// jsMaxDepth nesting beyond which a literal is rejected
+const jsMaxDepth = 1000
+
+// jsParser def
+// A recursive descent parser over JavaScript literal source
+type jsParser struct {
+	s   string
+	pos int
+	// numbers decodes numbers as json.Number, keeping their precision
+	numbers bool
+}
+
+//
+// jsLiteralJSON : The JSON encoding of the literal at the start of s, numbers
+// are written as they appear in s where JSON allows it.
+//
+func jsLiteralJSON(s string) ([]byte, error) {
+	parser := &jsParser{s: s, numbers: true}
+	value, err := parser.value(0)
+	if err != nil {
+		return nil, err
+	}
+
+	return json.Marshal(value)
+}
+
+//
+// jsParser: An error at the current position.
+//
+func (id *jsParser) errorf(format string, args ...interface{}) error {
+	return fmt.Errorf("godom: invalid JavaScript literal at offset %d: %s", id.pos, fmt.Sprintf(format, args...))
+}
+
+//
+// jsParser: Skip whitespace and comments.
+//
+func (id *jsParser) space() {
+	for id.pos < len(id.s) {
+		r, size := utf8.DecodeRuneInString(id.s[id.pos:])
+		switch {
+		case unicode.IsSpace(r) || r == '\uFEFF':
+			id.pos += size
+		case strings.HasPrefix(id.s[id.pos:], "//"):
+			end := strings.IndexAny(id.s[id.pos:], "\n\r")
+			if end < 0 {
+				id.pos = len(id.s)
+			} else {
+				id.pos += end
+			}
+		case strings.HasPrefix(id.s[id.pos:], "/*"):
+			end := strings.Index(id.s[id.pos+2:], "*/")
+			if end < 0 {
+				id.pos = len(id.s)
+			} else {
+				id.pos += end + 4
+			}
+		default:
+			return
+		}
+	}
+}
+
+//
+// jsParser: Parse the value at the current position.
+//
+func (id *jsParser) value(depth int) (interface{}, error) {
+	if depth > jsMaxDepth {
+		return nil, id.errorf("nesting too deep")
+	}
+
+	id.space()
+	if id.pos >= len(id.s) {
+		return nil, id.errorf("unexpected end of input")
+	}
+
+	switch c := id.s[id.pos]; {
+	case c == '{':
+		return id.object(depth)
+	case c == '[':
+		return id.array(depth)
+	case c == '"' || c == '\'' || c == '`':
+		return id.string()
+	case c == '-' || c == '+' || c == '.' || (c >= '0' && c <= '9'):
+		return id.number()
+	}
+
+	start := id.pos
+	switch ident := id.ident(); ident {
+	case "true":
+		return true, nil
+	case "false":
+		return false, nil
+	case "null", "undefined", "NaN", "Infinity":
+		return nil, nil
+	default:
+		id.pos = start
+		return nil, id.errorf("unsupported value")
+	}
+}
+
+//
+// jsParser: Parse the object at the current position.
+//
+func (id *jsParser) object(depth int) (interface{}, error) {
+	result := map[string]interface{}{}
+	id.pos++
+	for {
+		id.space()
+		if id.pos >= len(id.s) {
+			return nil, id.errorf("unterminated object")
+		}
+		if id.s[id.pos] == '}' {
+			id.pos++
+			return result, nil
+		}
+
+		key, err := id.key()
+		if err != nil {
+			return nil, err
+		}
+		id.space()
+		if id.pos >= len(id.s) || id.s[id.pos] != ':' {
+			return nil, id.errorf("expected : after key %q", key)
+		}
+		id.pos++
+		value, err := id.value(depth + 1)
+		if err != nil {
+			return nil, err
+		}
+		result[key] = value
+
+		id.space()
+		if id.pos < len(id.s) && id.s[id.pos] == ',' {
+			id.pos++
+		} else if id.pos < len(id.s) && id.s[id.pos] != '}' {
+			return nil, id.errorf("expected , or }")
+		}
+	}
+}
+
+//
+// jsParser: Parse the key of an object member.
+//
+func (id *jsParser) key() (string, error) {
+	switch c := id.s[id.pos]; {
+	case c == '"' || c == '\'' || c == '`':
+		return id.string()
+	case c >= '0' && c <= '9' || c == '.':
+		start := id.pos
+		value, err := id.number()
+		if err != nil {
+			return "", err
+		}
+		// numeric keys are named by their value, eg. 1.0 is "1"
+		switch n := value.(type) {
+		case float64:
+			return strconv.FormatFloat(n, 'f', -1, 64), nil
+		case json.Number:
+			f, _ := n.Float64()
+			return strconv.FormatFloat(f, 'f', -1, 64), nil
+		}
+		return id.s[start:id.pos], nil
+	}
+
+	if ident := id.ident(); len(ident) > 0 {
+		return ident, nil
+	}
+
+	return "", id.errorf("expected key")
+}
+
+//
+// jsParser: Parse the array at the current position, elisions are nil.
+//
+func (id *jsParser) array(depth int) (interface{}, error) {
+	result := []interface{}{}
+	id.pos++
+	for {
+		id.space()
+		if id.pos >= len(id.s) {
+			return nil, id.errorf("unterminated array")
+		}
+		switch id.s[id.pos] {
+		case ']':
+			id.pos++
+			return result, nil
+		case ',':
+			// an elision such as [1,,2]
+			id.pos++
+			result = append(result, nil)
+			continue
+		}
+
+		value, err := id.value(depth + 1)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, value)
+
+		id.space()
+		if id.pos < len(id.s) && id.s[id.pos] == ',' {
+			id.pos++
+		} else if id.pos < len(id.s) && id.s[id.pos] != ']' {
+			return nil, id.errorf("expected , or ]")
+		}
+	}
+}
+
+//
+// jsParser: Parse the identifier at the current position, empty if there is none.
+//
+func (id *jsParser) ident() string {
+	start := id.pos
+	for id.pos < len(id.s) {
+		r, size := utf8.DecodeRuneInString(id.s[id.pos:])
+		if r != '_' && r != '$' && !unicode.IsLetter(r) && !(id.pos > start && unicode.IsDigit(r)) {
+			break
+		}
+		id.pos += size
+	}
+
+	return id.s[start:id.pos]
+}
+
+//
+// jsParser: Parse the number at the current position.
+//
+func (id *jsParser) number() (interface{}, error) {
+	start := id.pos
+	sign := 1.0
+	if c := id.s[id.pos]; c == '-' || c == '+' {
+		if c == '-' {
+			sign = -1
+		}
+		id.pos++
+		id.space()
+	}
+	if strings.HasPrefix(id.s[id.pos:], "Infinity") {
+		id.pos += len("Infinity")
+		return nil, nil
+	}
+
+	digits := id.pos
+	hex := id.pos+1 < len(id.s) && id.s[id.pos] == '0' && id.s[id.pos+1]|0x20 == 'x'
+	for id.pos < len(id.s) {
+		c := id.s[id.pos]
+		exponentSign := (c == '+' || c == '-') && !hex && id.pos > digits && id.s[id.pos-1]|0x20 == 'e'
+		if strings.IndexByte("0123456789abcdefABCDEFxXoO._", c) < 0 && !exponentSign {
+			break
+		}
+		id.pos++
+	}
+	text := strings.Replace(id.s[digits:id.pos], "_", "", -1)
+
+	var n float64
+	lower := strings.ToLower(text)
+	switch {
+	case len(lower) > 2 && lower[0] == '0' && strings.IndexByte("xob", lower[1]) >= 0:
+		i, err := strconv.ParseInt(lower, 0, 64)
+		if err != nil {
+			id.pos = start
+			return nil, id.errorf("invalid number %q", text)
+		}
+		n = float64(i)
+	default:
+		f, err := strconv.ParseFloat(text, 64)
+		if err != nil {
+			id.pos = start
+			return nil, id.errorf("invalid number %q", text)
+		}
+		n = f
+	}
+
+	if id.numbers {
+		// keep the digits unless JSON can't represent them, eg. 0x1F or .5
+		if json.Valid([]byte(text)) {
+			if sign < 0 {
+				text = "-" + text
+			}
+			return json.Number(text), nil
+		}
+		return json.Number(strconv.FormatFloat(sign*n, 'f', -1, 64)), nil
+	}
+
+	return sign * n, nil
+}
+
+//
+// jsParser: Parse the string at the current position, decoding its escapes.
+//
+func (id *jsParser) string() (string, error) {
+	quote := id.s[id.pos]
+	id.pos++
+	sb := strings.Builder{}
+	for id.pos < len(id.s) {
+		c := id.s[id.pos]
+		switch {
+		case c == quote:
+			id.pos++
+			return sb.String(), nil
+		case c == '\\':
+			if err := id.escape(&sb); err != nil {
+				return "", err
+			}
+		case quote == '`' && strings.HasPrefix(id.s[id.pos:], "${"):
+			return "", id.errorf("template substitution")
+		case (c == '\n' || c == '\r') && quote != '`':
+			return "", id.errorf("unterminated string")
+		default:
+			sb.WriteByte(c)
+			id.pos++
+		}
+	}
+
+	return "", id.errorf("unterminated string")
+}
+
+//
+// jsParser: Decode the escape sequence at the current position.
+//
+func (id *jsParser) escape(sb *strings.Builder) error {
+	id.pos++
+	if id.pos >= len(id.s) {
+		return id.errorf("unterminated string")
+	}
+	c := id.s[id.pos]
+	id.pos++
+
+	switch c {
+	case 'n':
+		sb.WriteByte('\n')
+	case 't':
+		sb.WriteByte('\t')
+	case 'r':
+		sb.WriteByte('\r')
+	case 'b':
+		sb.WriteByte('\b')
+	case 'f':
+		sb.WriteByte('\f')
+	case 'v':
+		sb.WriteByte('\v')
+	case '0':
+		sb.WriteByte(0)
+	case '\r':
+		// a line continuation
+		if id.pos < len(id.s) && id.s[id.pos] == '\n' {
+			id.pos++
+		}
+	case '\n':
+	case 'x':
+		r, err := id.hex(2)
+		if err != nil {
+			return err
+		}
+		sb.WriteRune(r)
+	case 'u':
+		r, err := id.unicodeEscape()
+		if err != nil {
+			return err
+		}
+		// combine a surrogate pair
+		if utf16.IsSurrogate(r) && strings.HasPrefix(id.s[id.pos:], "\\u") {
+			start := id.pos
+			id.pos += 2
+			low, err := id.unicodeEscape()
+			if err == nil && utf16.DecodeRune(r, low) != unicode.ReplacementChar {
+				r = utf16.DecodeRune(r, low)
+			} else {
+				id.pos = start
+			}
+		}
+		sb.WriteRune(r)
+	default:
+		// any other escaped character is itself
+		id.pos--
+		r, size := utf8.DecodeRuneInString(id.s[id.pos:])
+		id.pos += size
+		sb.WriteRune(r)
+	}
+
+	return nil
+}
+
+//
+// jsParser: Decode the code point of a \u escape, as \uXXXX or \u{X...}.
+//
+func (id *jsParser) unicodeEscape() (rune, error) {
+	if id.pos >= len(id.s) || id.s[id.pos] != '{' {
+		return id.hex(4)
+	}
+
+	end := strings.IndexByte(id.s[id.pos:], '}')
+	if end < 2 {
+		return 0, id.errorf("invalid unicode escape")
+	}
+	n, err := strconv.ParseUint(id.s[id.pos+1:id.pos+end], 16, 32)
+	if err != nil || n > unicode.MaxRune {
+		return 0, id.errorf("invalid unicode escape")
+	}
+	id.pos += end + 1
+
+	return rune(n), nil
+}
+
+//
+// jsParser: Decode count hex digits as a code point.
+//
+func (id *jsParser) hex(count int) (rune, error) {
+	if id.pos+count > len(id.s) {
+		return 0, id.errorf("invalid escape")
+	}
+	n, err := strconv.ParseUint(id.s[id.pos:id.pos+count], 16, 32)
+	if err != nil {
+		return 0, id.errorf("invalid escape")
+	}
+	id.pos += count
+
+	return rune(n), nil
+}
+
+//
+// ExtractBalancedJSON : The JSON or JavaScript object or array opening with the
+// first { or [ at or after start, up to its matching closing delimiter.
+// Delimiters within strings and comments are ignored, so a } inside a string
+// does not end the object. The contents are not validated, parse them with
+// json.Unmarshal or ParseJSLiteral.
+//
+func ExtractBalancedJSON(s string, start int) (string, error) {
+	if start < 0 || start > len(s) {
+		return "", fmt.Errorf("godom: offset %d out of range", start)
+	}
+	open := strings.IndexAny(s[start:], "{[")
+	if open < 0 {
+		return "", fmt.Errorf("godom: no object or array at offset %d", start)
+	}
+	open += start
+
+	stack := []byte{}
+	for i := open; i < len(s); i++ {
+		switch c := s[i]; c {
+		case '{':
+			stack = append(stack, '}')
+		case '[':
+			stack = append(stack, ']')
+		case '}', ']':
+			if stack[len(stack)-1] != c {
+				return "", fmt.Errorf("godom: mismatched %c at offset %d", c, i)
+			}
+			stack = stack[:len(stack)-1]
+			if len(stack) == 0 {
+				return s[open : i+1], nil
+			}
+		case '"', '\'', '`':
+			// skip to the closing quote, passing over escapes
+			for i++; i < len(s) && s[i] != c; i++ {
+				if s[i] == '\\' {
+					i++
+				}
+			}
+		case '/':
+			switch {
+			case strings.HasPrefix(s[i:], "//"):
+				end := strings.IndexAny(s[i:], "\n\r")
+				if end < 0 {
+					i = len(s)
+				} else {
+					i += end
+				}
+			case strings.HasPrefix(s[i:], "/*"):
+				end := strings.Index(s[i+2:], "*/")
+				if end < 0 {
+					i = len(s)
+				} else {
+					i += end + 3
+				}
+			}
+		}
+	}
+
+	return "", fmt.Errorf("godom: unbalanced %c at offset %d", s[open], open)
+}
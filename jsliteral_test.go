@@ -0,0 +1,97 @@
+// Copyright 2016, Marc Lavergne <mlavergn@gmail.com>. All rights reserved.
+// Use of this source code is governed by the MIT
+// license that can be found in the LICENSE file.
+
+package godom
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParseJSLiteral(t *testing.T) {
+	value, err := ParseJSLiteral(`{
+		// the page configuration
+		name: 'Ann, "the" first',
+		'url': "http://example.com/a?b=1,c:2",
+		nested: {list: [1, 2.5, -3e2, 0x1F, .5,], ok: true, none: null, gone: undefined,},
+		$id: "é\x41\u{1F600}😀\'",
+		2: ` + "`tpl`" + `,
+		/* trailing */
+	}; var other = 1;`)
+	if err != nil {
+		t.Fatalf("failed to parse literal %v", err)
+	}
+
+	expected := map[string]interface{}{
+		"name": `Ann, "the" first`,
+		"url":  "http://example.com/a?b=1,c:2",
+		"nested": map[string]interface{}{
+			"list": []interface{}{1.0, 2.5, -300.0, 31.0, 0.5},
+			"ok":   true,
+			"none": nil,
+			"gone": nil,
+		},
+		"$id": "éA😀😀'",
+		"2":   "tpl",
+	}
+	if !reflect.DeepEqual(value, expected) {
+		t.Errorf("unexpected literal %#v", value)
+	}
+
+	if value, err := ParseJSLiteral(`[1,,'a']`); err != nil || !reflect.DeepEqual(value, []interface{}{1.0, nil, "a"}) {
+		t.Errorf("unexpected array %#v %v", value, err)
+	}
+
+	for _, invalid := range []string{`{a: 1`, `{a: new Date()}`, `{a 1}`, `["a]`, "`${x}`", `{a: 1 b: 2}`} {
+		if _, err := ParseJSLiteral(invalid); err == nil {
+			t.Errorf("expected error for %s", invalid)
+		}
+	}
+}
+
+func TestFindJSONForScript(t *testing.T) {
+	d := NewDOM()
+	d.SetContents(`<html><head><script>
+	var config = {api: 'https://example.com/v1', retry: {count: 3, delay: [1, 2]}, label: "a, b: c",};
+	var list = [1, 2];
+	</script></head><body></body></html>`)
+
+	result, err := d.FindJSONForScriptWithKey("config")
+	if err != nil {
+		t.Fatalf("failed to parse script JSON %v", err)
+	}
+	retry, _ := result["retry"].(map[string]interface{})
+	if result["api"] != "https://example.com/v1" || result["label"] != "a, b: c" || retry["count"] != 3.0 {
+		t.Errorf("unexpected script JSON %v", result)
+	}
+
+	if _, err := d.FindJSONForScriptWithKeyDelimiter("list", JSONArrayDelimiter); err != ErrScriptObject {
+		t.Errorf("expected object error %v", err)
+	}
+}
+
+func TestExtractBalancedJSON(t *testing.T) {
+	s := `var a = {"text": "a } b ] c", 'q': 'it\'s {', list: [1, [2, {}]], /* } */ url: "http://x/}"} // }
+	var b = 1;`
+	json, err := ExtractBalancedJSON(s, 0)
+	if err != nil || json != s[8:strings.Index(s, " // }")] {
+		t.Errorf("unexpected extraction [%s] %v", json, err)
+	}
+	if value, err := ParseJSLiteral(json); err != nil || value.(map[string]interface{})["url"] != "http://x/}" {
+		t.Errorf("failed to parse extraction %v", err)
+	}
+
+	if json, err := ExtractBalancedJSON(`x = [1, "]", 2]; y = {}`, 3); err != nil || json != `[1, "]", 2]` {
+		t.Errorf("unexpected array extraction [%s] %v", json, err)
+	}
+	for _, invalid := range []string{`{"a": [1}`, `{"a": "}`, `no delimiters`} {
+		if _, err := ExtractBalancedJSON(invalid, 0); err == nil {
+			t.Errorf("expected error for %s", invalid)
+		}
+	}
+	if _, err := ExtractBalancedJSON("{}", 3); err == nil {
+		t.Errorf("expected range error")
+	}
+}
@@ -0,0 +1,264 @@
+// Copyright 2016 Marc Lavergne <mlavergn@gmail.com>. All rights reserved.
+// Use of this source code is governed by
+// license that can be found in the LICENSE file.
+
+package godom
+
+import (
+	"context"
+	"errors"
+	"golang.org/x/net/html"
+	"io"
+	"strings"
+)
+
+// ErrMaxNodes the contents hold more elements than ParseOptions.MaxNodes
+var ErrMaxNodes = errors.New("godom: contents exceed the maximum node count")
+
+// ErrMaxDepth the contents nest elements deeper than ParseOptions.MaxDepth
+var ErrMaxDepth = errors.New("godom: contents exceed the maximum depth")
+
+// ErrMaxTextBytes the contents hold more text than ParseOptions.MaxTextBytes
+var ErrMaxTextBytes = errors.New("godom: contents exceed the maximum text size")
+
+// limitCheckInterval tokens or nodes between checks of the context
+const limitCheckInterval = 64
+
+// limitScanSlack the factor by which the token counts may exceed the limits
+// before the tree is built, as the tree adds implied elements and drops
+// misplaced ones
+const limitScanSlack = 2
+
+//
+// SetContentsContext : Parse the raw html contents as SetContents does,
+// stopping once ctx is done or a limit of the ParseOptions is exceeded. The
+// contents are first tokenized, rejecting those over twice a limit so an
+// oversized page is never built into a tree. The limits then apply to the
+// DOM, which includes the implied html, head, and body elements but neither
+// skipped elements nor the whitespace trimmed from text. The context is
+// checked throughout. On failure the nodes of the parse are discarded and the
+// error of ctx or one of ErrMaxNodes, ErrMaxDepth, or ErrMaxTextBytes is
+// returned.
+//
+func (id *DOM) SetContentsContext(ctx context.Context, htmlString string) error {
+	if id.frozen {
+		return ErrFrozen
+	}
+
+	limiter := &parseLimiter{ctx: ctx, options: id.options}
+	bounded := limiter.bounded()
+	if bounded {
+		if err := id.scanLimits(ctx, htmlString); err != nil {
+			id.Logger().Warn("godom: parse aborted", "error", err)
+			return err
+		}
+	}
+
+	id.contents = htmlString
+	id.parseErr = nil
+
+	var r io.Reader = strings.NewReader(id.entityContents(htmlString))
+	if ctx.Done() != nil {
+		r = &contextReader{ctx: ctx, r: r}
+	}
+	doc, err := html.Parse(r)
+	if err != nil {
+		id.Logger().Warn("godom: parse aborted", "error", err)
+		return err
+	}
+
+	start, diagnostics := len(id.document), len(id.diagnostics)
+	if bounded {
+		id.limiter = limiter
+		defer func() { id.limiter = nil }()
+	}
+	id.parseHTMLNode(nil, doc, false)
+	if limiter.err != nil {
+		id.truncate(start, diagnostics)
+		id.Logger().Warn("godom: parse aborted", "error", limiter.err)
+		return limiter.err
+	}
+	if id.options.SourcePositions || id.options.PreserveCase {
+		id.parseSource(id.document[start:])
+	}
+	if id.parseErr != nil {
+		id.Logger().Warn("godom: fragment parse failed", "error", id.parseErr)
+	}
+
+	return id.parseErr
+}
+
+// parseLimiter def
+// Enforces the context and limits of a parse
+type parseLimiter struct {
+	ctx       context.Context
+	options   ParseOptions
+	nodes     int
+	textBytes int
+	ticks     int
+	// err is the first context or limit error, which stops the parse
+	err error
+}
+
+//
+// parseLimiter: Is there a context to check or a limit to enforce?
+//
+func (id *parseLimiter) bounded() bool {
+	return id.ctx.Done() != nil || id.options.MaxNodes > 0 || id.options.MaxDepth > 0 || id.options.MaxTextBytes > 0
+}
+
+//
+// parseLimiter: Count an element at depth, the top level being 1. False once
+// the parse must stop.
+//
+func (id *parseLimiter) element(depth int) bool {
+	id.nodes++
+	switch {
+	case id.err != nil:
+	case id.options.MaxNodes > 0 && id.nodes > id.options.MaxNodes:
+		id.err = ErrMaxNodes
+	case id.options.MaxDepth > 0 && depth > id.options.MaxDepth:
+		id.err = ErrMaxDepth
+	default:
+		id.tick()
+	}
+
+	return id.err == nil
+}
+
+//
+// parseLimiter: Count the bytes of a text. False once the parse must stop.
+//
+func (id *parseLimiter) text(size int) bool {
+	id.textBytes += size
+	switch {
+	case id.err != nil:
+	case id.options.MaxTextBytes > 0 && id.textBytes > id.options.MaxTextBytes:
+		id.err = ErrMaxTextBytes
+	default:
+		id.tick()
+	}
+
+	return id.err == nil
+}
+
+//
+// parseLimiter: Check the context every limitCheckInterval calls.
+//
+func (id *parseLimiter) tick() {
+	id.ticks++
+	if id.ticks%limitCheckInterval == 0 {
+		id.err = id.ctx.Err()
+	}
+}
+
+//
+// DOM: Tokenize the contents, checking the context and rejecting contents
+// over limitScanSlack times a limit. Elements are nested as parseTokens does,
+// with an element of optional end tag closed by a start tag of the same name.
+//
+func (id *DOM) scanLimits(ctx context.Context, contents string) error {
+	options := id.options
+	options.MaxNodes *= limitScanSlack
+	options.MaxDepth *= limitScanSlack
+	options.MaxTextBytes *= limitScanSlack
+	limiter := &parseLimiter{ctx: ctx, options: options}
+
+	z := html.NewTokenizer(strings.NewReader(contents))
+	// open element stack and the depth of the skipped element being passed over
+	stack := []string{}
+	skipDepth := 0
+	for limiter.err == nil {
+		tokenType := z.Next()
+		if tokenType == html.ErrorToken {
+			if z.Err() != io.EOF {
+				return z.Err()
+			}
+			// a cancelled context is reported even for short contents
+			return ctx.Err()
+		}
+
+		name, _ := z.TagName()
+		tag := string(name)
+		switch tokenType {
+		case html.StartTagToken, html.SelfClosingTagToken:
+			if len(stack) > 0 && stack[len(stack)-1] == tag && optionalEndTags[tag] == 1 {
+				stack = stack[:len(stack)-1]
+			}
+			if skipDepth == 0 && id.tags.skip[tag] == 0 {
+				limiter.element(len(stack) + 1)
+			}
+			if tokenType == html.StartTagToken && voidTags[tag] == 0 {
+				stack = append(stack, tag)
+				if skipDepth == 0 && id.tags.skip[tag] == 1 {
+					skipDepth = len(stack)
+				}
+			}
+		case html.EndTagToken:
+			// unwind to the matching open element, stray end tags are ignored
+			for i := len(stack) - 1; i >= 0; i-- {
+				if stack[i] == tag {
+					stack = stack[:i]
+					break
+				}
+			}
+			if len(stack) < skipDepth {
+				skipDepth = 0
+			}
+		case html.TextToken:
+			if skipDepth == 0 && (len(stack) == 0 || !id.discardText(stack[len(stack)-1])) {
+				limiter.text(len(strings.TrimSpace(string(z.Text()))))
+			}
+		default:
+			limiter.tick()
+		}
+	}
+
+	return limiter.err
+}
+
+//
+// parseLimiter: The depth of a child of node, counting no further than the
+// maximum depth.
+//
+func (id *parseLimiter) childDepth(node *DOMNode) int {
+	depth := 1
+	if id.options.MaxDepth <= 0 {
+		return depth
+	}
+	for ; node != nil && depth <= id.options.MaxDepth; node = node.Parent {
+		depth++
+	}
+
+	return depth
+}
+
+// contextReader def
+// Fails reads once the context is done
+type contextReader struct {
+	ctx context.Context
+	r   io.Reader
+}
+
+//
+// contextReader: Read unless the context is done.
+//
+func (id *contextReader) Read(p []byte) (int, error) {
+	if err := id.ctx.Err(); err != nil {
+		return 0, err
+	}
+
+	return id.r.Read(p)
+}
+
+//
+// DOM: Discard the document entries and diagnostics of an aborted parse.
+//
+func (id *DOM) truncate(start int, diagnostics int) {
+	id.diagnostics = id.diagnostics[:diagnostics]
+	for _, node := range id.document[start:] {
+		node.dom = nil
+	}
+	id.document = id.document[:start]
+	id.reindex()
+}
@@ -0,0 +1,85 @@
+// Copyright 2016, Marc Lavergne <mlavergn@gmail.com>. All rights reserved.
+// Use of this source code is governed by the MIT
+// license that can be found in the LICENSE file.
+
+package godom
+
+import (
+	"context"
+	"strings"
+	"testing"
+)
+
+func TestSetContentsContext(t *testing.T) {
+	contents := "<html><body>" + strings.Repeat("<div>", 50) + "text" + strings.Repeat("</div>", 50) + "</body></html>"
+
+	d := NewDOM()
+	if err := d.SetContentsContext(context.Background(), contents); err != nil || len(d.Find("div", nil)) != 50 {
+		t.Fatalf("failed to parse with context %v", err)
+	}
+
+	tests := []struct {
+		options  ParseOptions
+		expected error
+	}{
+		{ParseOptions{MaxNodes: 20}, ErrMaxNodes},
+		{ParseOptions{MaxDepth: 10}, ErrMaxDepth},
+		{ParseOptions{MaxTextBytes: 3}, ErrMaxTextBytes},
+		{ParseOptions{MaxNodes: 60, MaxDepth: 60, MaxTextBytes: 4}, nil},
+		// skipped elements and trimmed whitespace are not counted
+		{ParseOptions{MaxNodes: 3, MaxTextBytes: 4, SkipTags: []string{"div"}}, nil},
+	}
+	for _, test := range tests {
+		for _, stream := range []bool{false, true} {
+			d := NewDOM()
+			d.SetParseOptions(test.options)
+			var err error
+			if stream {
+				err = d.SetContentsFromReader(strings.NewReader(contents))
+			} else {
+				err = d.SetContents(contents)
+			}
+			if err != test.expected {
+				t.Errorf("unexpected error %v for %+v [stream %t]", err, test.options, stream)
+			}
+			if err != nil && (len(d.document) != 0 || len(d.Find("div", nil)) != 0) {
+				t.Errorf("failed to discard the aborted parse [stream %t]", stream)
+			}
+		}
+	}
+
+	d = NewDOM(WithLimits(0, 0, 4))
+	if err := d.SetContents("<html><body><p>   text   </p>\n\n</body></html>"); err != nil {
+		t.Errorf("expected the trimmed text within the limit, got %v", err)
+	}
+
+	// the tokens are checked before the tree is built
+	d = NewDOM(WithLimits(0, 10, 0))
+	deep := strings.Repeat("<div>", 10000)
+	if err := d.scanLimits(context.Background(), deep); err != ErrMaxDepth {
+		t.Errorf("expected the token depth rejected, got %v", err)
+	}
+	list := "<ul>" + strings.Repeat("<li>item", 100) + "</ul><p>a<p>b"
+	if err := d.scanLimits(context.Background(), list); err != nil {
+		t.Errorf("expected implied end tags within the limit, got %v", err)
+	}
+	if err := d.SetContents(list); err != nil || len(d.Find("li", nil)) != 100 {
+		t.Errorf("failed to parse implied end tags %v", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	d = NewDOM()
+	if err := d.SetContentsContext(ctx, contents); err != context.Canceled || len(d.document) != 0 {
+		t.Errorf("expected cancellation %v", err)
+	}
+
+	// an earlier document is kept when a later parse fails
+	d = NewDOM()
+	d.SetContents("<html><body><p>kept</p></body></html>")
+	count := len(d.document)
+	d.SetParseOptions(ParseOptions{MaxNodes: 5})
+	if err := d.SetContents(contents); err != ErrMaxNodes || len(d.document) != count || len(d.Find("p", nil)) != 1 {
+		t.Errorf("failed to keep the earlier document %v", err)
+	}
+}
@@ -0,0 +1,295 @@
+// Copyright 2016 Marc Lavergne <mlavergn@gmail.com>. All rights reserved.
+// Use of this source code is governed by
+// license that can be found in the LICENSE file.
+
+package godom
+
+import (
+	"net/url"
+	"strings"
+)
+
+// Link def
+// An anchor with its href resolved to an absolute URL when possible
+type Link struct {
+	Node *DOMNode
+	Href string
+	// URL is the resolved href, nil when the href can't be parsed
+	URL *url.URL
+	// Rel is the lowercased rel tokens
+	Rel  []string
+	Text string
+}
+
+//
+// Links : Extract the a and area links of the document, resolving each href
+// against the document <base href>, itself resolved against base. When base
+// is nil the DOM URL is used, failing that relative hrefs remain relative.
+//
+func (id *DOM) Links(base *url.URL) (result []*Link) {
+	base = id.documentBase(base)
+
+	nodes := append(id.Find("a", nil), id.Find("area", nil)...)
+	sortNodes(nodes)
+	for _, node := range nodes {
+		if !node.HasAttr("href") {
+			continue
+		}
+		href := strings.TrimSpace(node.Attr("href"))
+		result = append(result, &Link{
+			Node: node,
+			Href: href,
+			URL:  resolveURL(base, href),
+			Rel:  strings.Fields(strings.ToLower(node.Attr("rel"))),
+			Text: strings.TrimSpace(node.ReaderText()),
+		})
+	}
+
+	return result
+}
+
+//
+// HasRel : Does the link carry the rel token?
+//
+func (id *Link) HasRel(rel string) bool {
+	rel = strings.ToLower(rel)
+	for _, token := range id.Rel {
+		if token == rel {
+			return true
+		}
+	}
+
+	return false
+}
+
+//
+// DOM: The base URL of the document, the first <base href> resolved against
+// base or the DOM URL, unless one was set by SetBaseURL.
+//
+func (id *DOM) documentBase(base *url.URL) *url.URL {
+	if base == nil && id.baseURL != nil {
+		return id.baseURL
+	}
+	if base == nil {
+		base = id.url
+	}
+	for _, node := range id.Find("base", nil) {
+		if node.HasAttr("href") {
+			if resolved := resolveURL(base, strings.TrimSpace(node.Attr("href"))); resolved != nil {
+				return resolved
+			}
+			break
+		}
+	}
+
+	return base
+}
+
+//
+// resolveURL : Resolve ref against base, nil when ref can't be parsed.
+//
+func resolveURL(base *url.URL, ref string) *url.URL {
+	refURL, err := url.Parse(ref)
+	if err != nil {
+		return nil
+	}
+	if base == nil {
+		return refURL
+	}
+
+	return base.ResolveReference(refURL)
+}
+
+// linkURLAttrs attributes holding a URL rewritten by AbsoluteURLs
+var linkURLAttrs = []string{"href", "src", "action", "formaction", "poster", "cite", "background", "longdesc", "data"}
+
+//
+// SetBaseURL : Set the URL relative URLs resolve against, overriding the
+// <base href> and the URL the document was fetched from.
+//
+func (id *DOM) SetBaseURL(u *url.URL) {
+	id.baseURL = u
+}
+
+//
+// BaseURL : The URL relative URLs resolve against, the URL set by SetBaseURL
+// or the <base href> resolved against the DOM URL. Nil when unknown.
+//
+func (id *DOM) BaseURL() *url.URL {
+	return id.documentBase(nil)
+}
+
+//
+// AbsoluteURLs : Rewrite the href, src, srcset, action, and other URL
+// attributes of every element to absolute URLs against the BaseURL, eg.
+// before archiving the page. Fragment-only URLs, URLs with a scheme such as
+// mailto: or data:, and URLs that fail to parse are left unchanged. The
+// rewritten nodes are marked as modified.
+//
+func (id *DOM) AbsoluteURLs() error {
+	if id.frozen {
+		return ErrFrozen
+	}
+	base := id.BaseURL()
+	if base == nil {
+		return nil
+	}
+
+	for _, node := range id.Find("*", nil) {
+		for _, key := range linkURLAttrs {
+			if _, val, ok := node.lookupAttr(key); ok {
+				if resolved := absoluteURL(base, val); resolved != val {
+					node.SetAttr(key, resolved)
+				}
+			}
+		}
+		if _, srcset, ok := node.lookupAttr("srcset"); ok {
+			if resolved := absoluteSrcset(base, srcset); resolved != srcset {
+				node.SetAttr("srcset", resolved)
+			}
+		}
+	}
+
+	return nil
+}
+
+//
+// absoluteSrcset : The srcset with the URL of each valid candidate resolved
+// against base, the descriptors and the invalid candidates are kept as is.
+//
+func absoluteSrcset(base *url.URL, srcset string) string {
+	parts := []string{}
+	splitSrcset(srcset, func(rawURL string, descriptors []string) {
+		candidate := SrcsetCandidate{URL: rawURL}
+		if candidate.parseDescriptors(descriptors) {
+			rawURL = absoluteURL(base, rawURL)
+		}
+		parts = append(parts, strings.Join(append([]string{rawURL}, descriptors...), " "))
+	})
+
+	return strings.Join(parts, ", ")
+}
+
+//
+// absoluteURL : The reference resolved against base, unchanged when it is
+// empty, a fragment, already has a scheme, or fails to parse.
+//
+func absoluteURL(base *url.URL, ref string) string {
+	trimmed := strings.TrimSpace(ref)
+	if len(trimmed) == 0 || strings.HasPrefix(trimmed, "#") {
+		return ref
+	}
+
+	refURL, err := url.Parse(trimmed)
+	if err != nil || len(refURL.Scheme) > 0 {
+		return ref
+	}
+
+	return base.ResolveReference(refURL).String()
+}
+
+// iconRels the rel tokens of icon links
+var iconRels = map[string]int{"icon": 1, "apple-touch-icon": 1, "apple-touch-icon-precomposed": 1, "mask-icon": 1, "fluid-icon": 1}
+
+// Icon def
+// An icon link, Sizes holds the sizes tokens, eg. 32x32 or any, and Color the
+// color of a mask-icon
+type Icon struct {
+	// Node is nil for the /favicon.ico fallback
+	Node *DOMNode
+	// Rel is the lowercased rel attribute, eg. shortcut icon
+	Rel  string
+	Href string
+	// URL is the resolved href, nil when the href can't be parsed
+	URL   *url.URL
+	Sizes []string
+	Type  string
+	Color string
+}
+
+//
+// Icons : The icon, shortcut icon, apple-touch-icon, and mask-icon links of
+// the document in document order, resolved against the base. Without any, the
+// conventional /favicon.ico of the site is returned.
+//
+func (id *DOM) Icons() (result []*Icon) {
+	base := id.BaseURL()
+	for _, node := range id.Find("link", nil) {
+		href := strings.TrimSpace(node.Attr("href"))
+		rel := strings.Fields(strings.ToLower(node.Attr("rel")))
+		if len(href) == 0 || !containsRel(rel, iconRels) {
+			continue
+		}
+		result = append(result, &Icon{
+			Node:  node,
+			Rel:   strings.Join(rel, " "),
+			Href:  href,
+			URL:   resolveURL(base, href),
+			Sizes: strings.Fields(strings.ToLower(node.Attr("sizes"))),
+			Type:  strings.TrimSpace(node.Attr("type")),
+			Color: strings.TrimSpace(node.Attr("color")),
+		})
+	}
+
+	if len(result) == 0 {
+		result = append(result, &Icon{Rel: "icon", Href: "/favicon.ico", URL: resolveURL(base, "/favicon.ico")})
+	}
+
+	return result
+}
+
+//
+// containsRel : Is one of the rel tokens in the set?
+//
+func containsRel(rel []string, set map[string]int) bool {
+	for _, token := range rel {
+		if set[token] == 1 {
+			return true
+		}
+	}
+
+	return false
+}
+
+// feedRels the rel tokens of feed links
+var feedRels = map[string]int{"alternate": 1}
+
+// feedTypes the media types of feed links
+var feedTypes = map[string]int{"application/rss+xml": 1, "application/atom+xml": 1, "application/feed+json": 1}
+
+// Feed def
+// A feed advertised by a <link rel="alternate">
+type Feed struct {
+	Node *DOMNode
+	Href string
+	// URL is the resolved href, nil when the href can't be parsed
+	URL *url.URL
+	// Type is the lowercased media type without parameters
+	Type  string
+	Title string
+}
+
+//
+// Feeds : The RSS, Atom, and JSON Feed links of the document in document
+// order, resolved against the base.
+//
+func (id *DOM) Feeds() (result []*Feed) {
+	base := id.BaseURL()
+	for _, node := range id.Find("link", nil) {
+		href := strings.TrimSpace(node.Attr("href"))
+		mediaType, _, _ := strings.Cut(strings.ToLower(node.Attr("type")), ";")
+		mediaType = strings.TrimSpace(mediaType)
+		if len(href) == 0 || feedTypes[mediaType] == 0 || !containsRel(strings.Fields(strings.ToLower(node.Attr("rel"))), feedRels) {
+			continue
+		}
+		result = append(result, &Feed{
+			Node:  node,
+			Href:  href,
+			URL:   resolveURL(base, href),
+			Type:  mediaType,
+			Title: strings.TrimSpace(node.Attr("title")),
+		})
+	}
+
+	return result
+}
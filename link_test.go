@@ -0,0 +1,147 @@
+// Copyright 2016, Marc Lavergne <mlavergn@gmail.com>. All rights reserved.
+// Use of this source code is governed by the MIT
+// license that can be found in the LICENSE file.
+
+package godom
+
+import (
+	"net/url"
+	"testing"
+)
+
+func TestLinks(t *testing.T) {
+	d := NewDOM()
+	d.SetContents(`<html><head><base href="/docs/"></head><body>
+<a href="intro.html" rel="Next nofollow">Intro <b>page</b></a>
+<a name="anchor">no href</a>
+<map><area href="../map.html"></map>
+<a href="https://other.com/x">Other</a>
+<a href="http://[bad">Bad</a>
+</body></html>`)
+
+	base, _ := url.Parse("https://example.com/index.html")
+	links := d.Links(base)
+	if len(links) != 4 {
+		d.Dump()
+		t.Fatalf("failed to find links %d", len(links))
+	}
+
+	if links[0].URL.String() != "https://example.com/docs/intro.html" || links[0].Text != "Intro page" {
+		t.Errorf("failed to resolve link %s [%s]", links[0].URL, links[0].Text)
+	}
+	if !links[0].HasRel("next") || !links[0].HasRel("NOFOLLOW") || links[0].HasRel("prev") {
+		t.Errorf("failed to parse rel %v", links[0].Rel)
+	}
+	if links[1].URL.String() != "https://example.com/map.html" {
+		t.Errorf("failed to resolve area %s", links[1].URL)
+	}
+	if links[2].URL.String() != "https://other.com/x" || links[3].URL != nil {
+		t.Errorf("failed to resolve absolute links")
+	}
+
+	links = d.Links(nil)
+	if links[0].URL.String() != "/docs/intro.html" {
+		t.Errorf("failed to resolve without base %s", links[0].URL)
+	}
+}
+
+func TestAbsoluteURLs(t *testing.T) {
+	d := NewDOM()
+	d.SetContents(`<html><head><base href="/docs/"><link rel="stylesheet" href="s.css"></head><body>
+<a href="a.html">a</a><a href="#top">top</a><a href="mailto:x@y.z">mail</a>
+<img src="i.png" srcset="i-1.png 1x, /i-2.png 2x, i-w.png 640w 480h, bad.png 2q">
+<form action="?q=1"></form>
+</body></html>`)
+
+	if d.BaseURL() == nil || d.BaseURL().String() != "/docs/" {
+		t.Errorf("unexpected base without a DOM URL %s", d.BaseURL())
+	}
+	d.url, _ = url.Parse("https://example.com/index.html")
+	if d.BaseURL().String() != "https://example.com/docs/" {
+		t.Fatalf("unexpected base %s", d.BaseURL())
+	}
+
+	if err := d.AbsoluteURLs(); err != nil {
+		t.Fatalf("failed to rewrite URLs [%v]", err)
+	}
+	a := d.Find("a", nil)
+	if a[0].Attr("href") != "https://example.com/docs/a.html" || a[1].Attr("href") != "#top" || a[2].Attr("href") != "mailto:x@y.z" {
+		t.Errorf("unexpected anchors %s %s %s", a[0].Attr("href"), a[1].Attr("href"), a[2].Attr("href"))
+	}
+	if a[1].Dirty() || a[2].Dirty() {
+		t.Errorf("failed to leave unchanged anchors unmodified")
+	}
+	img := d.Find("img", nil)[0]
+	if img.Attr("src") != "https://example.com/docs/i.png" || !img.Dirty() ||
+		img.Attr("srcset") != "https://example.com/docs/i-1.png 1x, https://example.com/i-2.png 2x, https://example.com/docs/i-w.png 640w 480h, bad.png 2q" {
+		t.Errorf("unexpected image %s [%s]", img.Attr("src"), img.Attr("srcset"))
+	}
+	if d.Find("form", nil)[0].Attr("action") != "https://example.com/docs/?q=1" || d.Find("link", nil)[0].Attr("href") != "https://example.com/docs/s.css" {
+		t.Errorf("failed to rewrite form and link")
+	}
+
+	override, _ := url.Parse("https://mirror.org/")
+	d.SetBaseURL(override)
+	if d.BaseURL() != override || d.Links(nil)[0].URL.String() != "https://example.com/docs/a.html" {
+		t.Errorf("failed to override base")
+	}
+}
+
+func TestIcons(t *testing.T) {
+	d := NewDOM()
+	d.SetContents(`<html><head>
+<link rel="Shortcut Icon" href="/f.ico">
+<link rel="stylesheet" href="s.css">
+<link rel="apple-touch-icon" sizes="180x180" href="touch.png">
+<link rel="mask-icon" href="mask.svg" color="#5bbad5" type="image/svg+xml">
+<link rel="icon" sizes="16x16 32x32" href="">
+</head></html>`)
+	d.url, _ = url.Parse("https://example.com/a/b.html")
+
+	icons := d.Icons()
+	if len(icons) != 3 {
+		t.Fatalf("unexpected icons %d", len(icons))
+	}
+	if icons[0].Rel != "shortcut icon" || icons[0].URL.String() != "https://example.com/f.ico" {
+		t.Errorf("unexpected icon %+v", icons[0])
+	}
+	if len(icons[1].Sizes) != 1 || icons[1].Sizes[0] != "180x180" || icons[1].URL.String() != "https://example.com/a/touch.png" {
+		t.Errorf("unexpected touch icon %+v", icons[1])
+	}
+	if icons[2].Color != "#5bbad5" || icons[2].Type != "image/svg+xml" {
+		t.Errorf("unexpected mask icon %+v", icons[2])
+	}
+
+	d = NewDOM()
+	d.SetContents("<html></html>")
+	d.url, _ = url.Parse("https://example.com/a/b.html")
+	if icons = d.Icons(); len(icons) != 1 || icons[0].Node != nil || icons[0].URL.String() != "https://example.com/favicon.ico" {
+		t.Errorf("failed to fall back to favicon.ico")
+	}
+}
+
+func TestFeeds(t *testing.T) {
+	d := NewDOM()
+	d.SetContents(`<html><head>
+<link rel="alternate" type="application/rss+xml" title="RSS" href="/rss.xml">
+<link rel="alternate" type="text/html" hreflang="fr" href="/fr/">
+<link rel="Alternate" type="Application/Atom+XML; charset=utf-8" href="atom.xml">
+<link rel="alternate" type="application/feed+json" href="https://cdn.example.com/feed.json">
+<link rel="stylesheet" type="application/rss+xml" href="/not.xml">
+</head></html>`)
+	d.url, _ = url.Parse("https://example.com/blog/")
+
+	feeds := d.Feeds()
+	if len(feeds) != 3 {
+		t.Fatalf("unexpected feeds %d", len(feeds))
+	}
+	if feeds[0].Title != "RSS" || feeds[0].URL.String() != "https://example.com/rss.xml" {
+		t.Errorf("unexpected feed %+v", feeds[0])
+	}
+	if feeds[1].Type != "application/atom+xml" || feeds[1].URL.String() != "https://example.com/blog/atom.xml" {
+		t.Errorf("unexpected feed %+v", feeds[1])
+	}
+	if feeds[2].Type != "application/feed+json" {
+		t.Errorf("unexpected feed %+v", feeds[2])
+	}
+}
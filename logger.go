@@ -0,0 +1,98 @@
+// Copyright 2016 Marc Lavergne <mlavergn@gmail.com>. All rights reserved.
+// Use of this source code is governed by
+// license that can be found in the LICENSE file.
+
+package godom
+
+import (
+	"sync/atomic"
+)
+
+// Logger def
+// Receives the messages of the package with alternating key and value
+// arguments. The method set matches *slog.Logger, other logging packages
+// need a small adapter
+type Logger interface {
+	Debug(msg string, args ...interface{})
+	Warn(msg string, args ...interface{})
+	Error(msg string, args ...interface{})
+}
+
+// nopLogger def
+// Discards all messages, the default Logger
+type nopLogger struct{}
+
+//
+// Debug : Discard the debug message
+//
+func (nopLogger) Debug(msg string, args ...interface{}) {}
+
+//
+// Warn : Discard the warning message
+//
+func (nopLogger) Warn(msg string, args ...interface{}) {}
+
+//
+// Error : Discard the error message
+//
+func (nopLogger) Error(msg string, args ...interface{}) {}
+
+//
+// silent : Does the logger discard all messages?
+//
+func silent(logger Logger) bool {
+	_, ok := logger.(nopLogger)
+	return ok
+}
+
+// loggerBox def
+// Holds the package Logger, atomic.Value requires a consistent concrete type
+type loggerBox struct {
+	logger Logger
+}
+
+// defaultLogger the Logger of the DOMs without one of their own
+var defaultLogger atomic.Value
+
+//
+// SetDefaultLogger : Send the messages of every DOM without a Logger of its
+// own, and of Load, to logger. A nil logger discards them, which is the
+// default. Safe for concurrent use.
+//
+func SetDefaultLogger(logger Logger) {
+	if logger == nil {
+		logger = nopLogger{}
+	}
+	defaultLogger.Store(loggerBox{logger})
+}
+
+//
+// DefaultLogger : The package Logger set by SetDefaultLogger.
+//
+func DefaultLogger() Logger {
+	if box, ok := defaultLogger.Load().(loggerBox); ok {
+		return box.logger
+	}
+
+	return nopLogger{}
+}
+
+//
+// SetLogger : Send the messages of the DOM to logger rather than to the
+// package Logger, nil reverts to the package Logger. Clones and scoped views
+// share the Logger of the DOM.
+//
+func (id *DOM) SetLogger(logger Logger) {
+	id.logger = logger
+}
+
+//
+// Logger : The Logger receiving the messages of the DOM.
+//
+func (id *DOM) Logger() Logger {
+	if id.logger != nil {
+		return id.logger
+	}
+
+	return DefaultLogger()
+}
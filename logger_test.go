@@ -0,0 +1,78 @@
+// Copyright 2016, Marc Lavergne <mlavergn@gmail.com>. All rights reserved.
+// Use of this source code is governed by the MIT
+// license that can be found in the LICENSE file.
+
+package godom
+
+import (
+	"bytes"
+	"log/slog"
+	"strings"
+	"testing"
+)
+
+// recordLogger def
+// Records the messages it receives by level
+type recordLogger struct {
+	messages []string
+}
+
+func (id *recordLogger) Debug(msg string, args ...interface{}) {
+	id.messages = append(id.messages, "debug "+msg)
+}
+
+func (id *recordLogger) Warn(msg string, args ...interface{}) {
+	id.messages = append(id.messages, "warn "+msg)
+}
+
+func (id *recordLogger) Error(msg string, args ...interface{}) {
+	id.messages = append(id.messages, "error "+msg)
+}
+
+func TestLogger(t *testing.T) {
+	if _, ok := DefaultLogger().(nopLogger); !ok {
+		t.Fatalf("expected the no-op default logger, got %T", DefaultLogger())
+	}
+
+	logger := &recordLogger{}
+	d := NewDOM()
+	d.SetLogger(logger)
+	d.SetContentsFromReader(strings.NewReader(`<div><span>a</b></span></div>`))
+	d.Dump()
+	if len(logger.messages) != 2 || logger.messages[0] != "debug godom: parse diagnostic" || logger.messages[1] != "debug godom: dump" {
+		t.Errorf("unexpected messages %v", logger.messages)
+	}
+	if clone := d.Clone(); clone.Logger() != Logger(logger) {
+		t.Errorf("expected the clone to share the logger")
+	}
+
+	logger.messages = nil
+	d = NewDOM()
+	d.SetLogger(logger)
+	d.SetParseOptions(ParseOptions{MaxNodes: 2})
+	if err := d.SetContents(`<html><body><p>a</p><p>b</p></body></html>`); err != ErrMaxNodes {
+		t.Fatalf("expected ErrMaxNodes, got %v", err)
+	}
+	if len(logger.messages) != 1 || logger.messages[0] != "warn godom: parse aborted" {
+		t.Errorf("unexpected messages %v", logger.messages)
+	}
+}
+
+func TestDefaultLogger(t *testing.T) {
+	defer SetDefaultLogger(nil)
+
+	buf := bytes.Buffer{}
+	SetDefaultLogger(slog.New(slog.NewTextHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug})))
+	d := NewDOM()
+	d.SetContentsFromReader(strings.NewReader(`<div><span>a</b></span></div>`))
+	if !strings.Contains(buf.String(), "godom: parse diagnostic") || !strings.Contains(buf.String(), "stray end tag") {
+		t.Errorf("unexpected log output %s", buf.String())
+	}
+
+	SetDefaultLogger(nil)
+	buf.Reset()
+	d.Dump()
+	if buf.Len() > 0 {
+		t.Errorf("expected no output, got %s", buf.String())
+	}
+}
@@ -0,0 +1,249 @@
+// Copyright 2016 Marc Lavergne <mlavergn@gmail.com>. All rights reserved.
+// Use of this source code is governed by
+// license that can be found in the LICENSE file.
+
+package godom
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// markdownBlockTags elements rendered as paragraphs
+var markdownBlockTags = map[string]int{
+	"p": 1, "div": 1, "section": 1, "article": 1, "main": 1, "header": 1, "footer": 1, "aside": 1, "nav": 1,
+	"figure": 1, "figcaption": 1, "address": 1, "details": 1, "summary": 1, "form": 1, "fieldset": 1,
+	"dl": 1, "dt": 1, "dd": 1, "body": 1, "html": 1,
+}
+
+// markdownSkipTags elements with no Markdown representation
+var markdownSkipTags = map[string]int{
+	"head": 1, "script": 1, "style": 1, "template": 1, "noscript": 1, "iframe": 1,
+	"comment": 1, "doctype": 1, "error": 1,
+}
+
+// markdownBlankLines runs of blank lines collapsed into one
+var markdownBlankLines = regexp.MustCompile(`[ \t]*\n([ \t]*\n)+`)
+
+//
+// Markdown : Convert the node and its subtree into Markdown. Headings,
+// paragraphs, lists, blockquotes, links, images, emphasis, code, and tables
+// are converted; other elements contribute their text.
+//
+func (id *DOMNode) Markdown() string {
+	return markdownCollapse(id.markdown())
+}
+
+//
+// DOMNode: The Markdown of the node, block boundaries are marked by blank
+// lines which are collapsed by the caller.
+//
+func (id *DOMNode) markdown() string {
+	if markdownSkipTags[id.Tag] == 1 {
+		return ""
+	}
+
+	switch id.Tag {
+	case "document":
+		return id.markdownInner()
+	case "h1", "h2", "h3", "h4", "h5", "h6":
+		level := int(id.Tag[1] - '0')
+		text := strings.Join(strings.Fields(id.markdownInner()), " ")
+		return "\n\n" + strings.Repeat("#", level) + " " + text + "\n\n"
+	case "pre":
+		return "\n\n```\n" + strings.Trim(id.markdownCode(), "\n") + "\n```\n\n"
+	case "code", "kbd", "samp", "tt":
+		return "`" + id.markdownCode() + "`"
+	case "strong", "b":
+		return markdownWrap("**", id.markdownInner())
+	case "em", "i":
+		return markdownWrap("_", id.markdownInner())
+	case "del", "s", "strike":
+		return markdownWrap("~~", id.markdownInner())
+	case "a":
+		text := id.markdownInner()
+		href := id.Attr("href")
+		if len(href) == 0 {
+			return text
+		}
+		return "[" + text + "](" + href + ")"
+	case "img":
+		return "![" + id.Attr("alt") + "](" + id.Attr("src") + ")"
+	case "br":
+		return "  \n"
+	case "hr":
+		return "\n\n---\n\n"
+	case "ul", "ol":
+		return "\n\n" + id.markdownList() + "\n\n"
+	case "blockquote":
+		lines := strings.Split(markdownCollapse(id.markdownInner()), "\n")
+		for i, line := range lines {
+			lines[i] = strings.TrimRight("> "+line, " ")
+		}
+		return "\n\n" + strings.Join(lines, "\n") + "\n\n"
+	case "table":
+		return "\n\n" + id.markdownTable() + "\n\n"
+	}
+
+	if markdownBlockTags[id.Tag] == 1 {
+		return "\n\n" + id.markdownInner() + "\n\n"
+	}
+
+	return id.markdownInner()
+}
+
+//
+// DOMNode: The Markdown of the text fragments and children joined by a
+// space, as the parse trims the whitespace between them.
+//
+func (id *DOMNode) markdownInner() string {
+	sb := strings.Builder{}
+	write := func(text string) {
+		if len(text) == 0 {
+			return
+		}
+		if sb.Len() > 0 && !markdownNoSpace(sb.String(), text) {
+			sb.WriteString(" ")
+		}
+		sb.WriteString(text)
+	}
+
+	id.eachContent(write, func(child *DOMNode) {
+		write(child.markdown())
+	})
+
+	return sb.String()
+}
+
+//
+// DOMNode: The text of the subtree without any Markdown conversion.
+//
+func (id *DOMNode) markdownCode() string {
+	sb := strings.Builder{}
+	id.eachContent(func(text string) {
+		sb.WriteString(text)
+	}, func(child *DOMNode) {
+		if child.Tag == "br" {
+			sb.WriteString("\n")
+		} else {
+			sb.WriteString(child.markdownCode())
+		}
+	})
+
+	return sb.String()
+}
+
+//
+// DOMNode: The list items, nested lists are indented under their item.
+//
+func (id *DOMNode) markdownList() string {
+	number, err := strconv.Atoi(id.Attr("start"))
+	if err != nil {
+		number = 1
+	}
+
+	items := []string{}
+	for _, child := range id.Children {
+		if child.Tag != "li" {
+			continue
+		}
+
+		marker := "- "
+		if id.Tag == "ol" {
+			marker = strconv.Itoa(number) + ". "
+			number++
+		}
+		indent := strings.Repeat(" ", len(marker))
+
+		// items are kept tight, blank lines would make the list loose
+		content := strings.ReplaceAll(markdownCollapse(child.markdownInner()), "\n\n", "\n")
+		lines := strings.Split(content, "\n")
+		for i := 1; i < len(lines); i++ {
+			lines[i] = indent + lines[i]
+		}
+		items = append(items, marker+strings.Join(lines, "\n"))
+	}
+
+	return strings.Join(items, "\n")
+}
+
+//
+// DOMNode: The table as a pipe table, the first row stands in for a
+// missing header as Markdown requires one.
+//
+func (id *DOMNode) markdownTable() string {
+	table := id.AsTable()
+	header := table.Header
+	rows := table.Rows
+	if len(header) == 0 && len(rows) > 0 {
+		header, rows = rows[0], rows[1:]
+	}
+
+	columns := len(header)
+	for _, row := range rows {
+		if len(row) > columns {
+			columns = len(row)
+		}
+	}
+	if columns == 0 {
+		return ""
+	}
+
+	separator := make([]string, columns)
+	for i := range separator {
+		separator[i] = "---"
+	}
+
+	lines := []string{markdownTableRow(header, columns), markdownTableRow(separator, columns)}
+	for _, row := range rows {
+		lines = append(lines, markdownTableRow(row, columns))
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+//
+// markdownTableRow : Format the cells as a pipe table row padded to columns.
+//
+func markdownTableRow(cells []string, columns int) string {
+	row := make([]string, columns)
+	for i := range row {
+		if i < len(cells) {
+			cell := strings.Join(strings.Fields(cells[i]), " ")
+			row[i] = strings.ReplaceAll(cell, "|", `\|`)
+		}
+	}
+
+	return "| " + strings.Join(row, " | ") + " |"
+}
+
+//
+// markdownWrap : Wrap the text in the emphasis delimiter, empty text is dropped.
+//
+func markdownWrap(delimiter string, text string) string {
+	text = strings.TrimSpace(text)
+	if len(text) == 0 {
+		return ""
+	}
+
+	return delimiter + text + delimiter
+}
+
+//
+// markdownNoSpace : Should text follow prev without a separating space?
+//
+func markdownNoSpace(prev string, text string) bool {
+	if strings.HasSuffix(prev, "\n") || strings.HasPrefix(text, "\n") || strings.HasPrefix(text, "  \n") {
+		return true
+	}
+
+	return joinsWithoutSpace(text)
+}
+
+//
+// markdownCollapse : Collapse blank line runs and trim the surrounding space.
+//
+func markdownCollapse(text string) string {
+	return strings.TrimSpace(markdownBlankLines.ReplaceAllString(text, "\n\n"))
+}
@@ -0,0 +1,32 @@
+// Copyright 2016, Marc Lavergne <mlavergn@gmail.com>. All rights reserved.
+// Use of this source code is governed by the MIT
+// license that can be found in the LICENSE file.
+
+package godom
+
+import (
+	"testing"
+)
+
+func TestMarkdown(t *testing.T) {
+	d := NewDOM()
+	d.SetContents("<html><body><div id=\"a\"><h1>Title</h1><p>Hello <strong>bold</strong> and <em>soft</em>, see <a href=\"/x\">this link</a>.</p>" +
+		"<ul><li>one</li><li>two<ol><li>nested</li></ol></li></ul>" +
+		"<pre>a := 1\nb := 2</pre><p>Use <code>go test</code></p>" +
+		"<table><tr><th>Name</th><th>Age</th></tr><tr><td>Bob</td><td>42</td></tr></table></div></body></html>")
+	p := d.Find("div", map[string]string{"id": "a"})
+	if len(p) != 1 {
+		d.Dump()
+		t.Fatalf("failed to find node")
+	}
+
+	expected := "# Title\n\n" +
+		"Hello **bold** and _soft_, see [this link](/x).\n\n" +
+		"- one\n- two\n  1. nested\n\n" +
+		"```\na := 1\nb := 2\n```\n\n" +
+		"Use `go test`\n\n" +
+		"| Name | Age |\n| --- | --- |\n| Bob | 42 |"
+	if p[0].Markdown() != expected {
+		t.Errorf("failed to convert node [%s]", p[0].Markdown())
+	}
+}
@@ -0,0 +1,56 @@
+// Copyright 2016 Marc Lavergne <mlavergn@gmail.com>. All rights reserved.
+// Use of this source code is governed by
+// license that can be found in the LICENSE file.
+
+package godom
+
+import (
+	"encoding/json"
+)
+
+// jsonNode def
+// The JSON form of a node, children are nested rather than linked to their
+// parent so the tree has no cycle
+type jsonNode struct {
+	Tag        string            `json:"tag"`
+	Attributes DOMNodeAttributes `json:"attrs,omitempty"`
+	Text       string            `json:"text,omitempty"`
+	Children   []*jsonNode       `json:"children,omitempty"`
+}
+
+//
+// MarshalJSON : Encode the node and its element descendants as nested
+// objects with tag, attrs, text, and children keys.
+//
+func (id *DOMNode) MarshalJSON() ([]byte, error) {
+	return json.Marshal(id.jsonNode())
+}
+
+//
+// MarshalJSON : Encode the tree from the root node as DOMNode does, null
+// for an empty DOM. Marshal a *DOM, the method is not called for a DOM value.
+//
+func (id *DOM) MarshalJSON() ([]byte, error) {
+	root := id.RootNode()
+	if root == nil {
+		return []byte("null"), nil
+	}
+
+	return root.MarshalJSON()
+}
+
+//
+// DOMNode: The JSON form of the subtree.
+//
+func (id *DOMNode) jsonNode() *jsonNode {
+	result := &jsonNode{
+		Tag:        id.Tag,
+		Attributes: id.Attributes,
+		Text:       id.Text(),
+	}
+	for _, child := range id.Children {
+		result.Children = append(result.Children, child.jsonNode())
+	}
+
+	return result
+}
@@ -0,0 +1,39 @@
+// Copyright 2016, Marc Lavergne <mlavergn@gmail.com>. All rights reserved.
+// Use of this source code is governed by the MIT
+// license that can be found in the LICENSE file.
+
+package godom
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestMarshalJSON(t *testing.T) {
+	d := NewDOM()
+	d.SetContents("<html><body><ul class=\"a\"><li>one</li><li>two</li></ul></body></html>")
+
+	bytes, err := json.Marshal(d.Find("ul", nil)[0])
+	if err != nil {
+		t.Fatalf("failed to marshal node [%v]", err)
+	}
+	expected := `{"tag":"ul","attrs":{"class":"a"},"children":[{"tag":"li","text":"one"},{"tag":"li","text":"two"}]}`
+	if string(bytes) != expected {
+		t.Errorf("unexpected node JSON [%s]", bytes)
+	}
+
+	bytes, err = json.Marshal(&d)
+	if err != nil {
+		t.Fatalf("failed to marshal DOM [%v]", err)
+	}
+	var tree map[string]interface{}
+	if err = json.Unmarshal(bytes, &tree); err != nil || tree["tag"] != "html" {
+		t.Errorf("unexpected DOM JSON [%s]", bytes)
+	}
+
+	empty := NewDOM()
+	bytes, _ = json.Marshal(&empty)
+	if string(bytes) != "null" {
+		t.Errorf("unexpected empty DOM JSON [%s]", bytes)
+	}
+}
@@ -0,0 +1,186 @@
+// Copyright 2016 Marc Lavergne <mlavergn@gmail.com>. All rights reserved.
+// Use of this source code is governed by
+// license that can be found in the LICENSE file.
+
+package godom
+
+import (
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// Image def
+// An <img> with its URLs resolved against the base, Width and Height are 0
+// when their attribute is missing or not a number
+type Image struct {
+	Node *DOMNode
+	Src  string
+	// URL is the resolved src, nil when the src is missing or can't be parsed
+	URL *url.URL
+	// Srcset holds the srcset candidates with their URLs resolved
+	Srcset  []SrcsetCandidate
+	Alt     string
+	Width   int
+	Height  int
+	Loading string
+	// Caption is the text of the figcaption of the enclosing figure
+	Caption string
+}
+
+//
+// Images : The images of the document in document order.
+//
+func (id *DOM) Images() (result []*Image) {
+	base := id.BaseURL()
+	for _, node := range id.Find("img", nil) {
+		image := &Image{
+			Node:    node,
+			Src:     strings.TrimSpace(node.Attr("src")),
+			Srcset:  ParseSrcset(node.Attr("srcset")),
+			Alt:     strings.TrimSpace(node.Attr("alt")),
+			Width:   mediaDimension(node.Attr("width")),
+			Height:  mediaDimension(node.Attr("height")),
+			Loading: strings.ToLower(strings.TrimSpace(node.Attr("loading"))),
+		}
+		if len(image.Src) > 0 {
+			image.URL = resolveURL(base, image.Src)
+		}
+		if base != nil {
+			for i := range image.Srcset {
+				image.Srcset[i].URL = absoluteURL(base, image.Srcset[i].URL)
+			}
+		}
+		if figure := node.Closest("figure", nil); figure != nil {
+			for _, child := range figure.Children {
+				if child.Tag == "figcaption" {
+					image.Caption = strings.TrimSpace(child.TextContent(TextOptions{}))
+					break
+				}
+			}
+		}
+		result = append(result, image)
+	}
+
+	return result
+}
+
+//
+// mediaDimension : The pixels of a width or height attribute, 0 when invalid.
+//
+func mediaDimension(val string) int {
+	val = strings.TrimSuffix(strings.TrimSpace(val), "px")
+	size, err := strconv.Atoi(val)
+	if err != nil || size < 0 {
+		return 0
+	}
+
+	return size
+}
+
+// Media def
+// A <video> or <audio> with its URLs resolved against the base
+type Media struct {
+	Node *DOMNode
+	// Tag is video or audio
+	Tag string
+	Src string
+	// URL is the resolved src, nil when the src is missing or can't be parsed
+	URL    *url.URL
+	Poster string
+	// PosterURL is the resolved poster, nil when missing or unparsable
+	PosterURL *url.URL
+	Sources   []*MediaSource
+	Tracks    []*MediaTrack
+}
+
+// MediaSource def
+// A <source> of a media element, Type is its MIME type, eg. video/mp4
+type MediaSource struct {
+	Node  *DOMNode
+	Src   string
+	URL   *url.URL
+	Type  string
+	Media string
+}
+
+// MediaTrack def
+// A <track> of a media element, Kind is lowercased and defaults to subtitles
+type MediaTrack struct {
+	Node    *DOMNode
+	Src     string
+	URL     *url.URL
+	Kind    string
+	Label   string
+	SrcLang string
+	Default bool
+}
+
+//
+// Media : The video and audio elements of the document in document order,
+// with their sources and text tracks such as captions.
+//
+func (id *DOM) Media() (result []*Media) {
+	base := id.BaseURL()
+	optionalURL := func(ref string) *url.URL {
+		if len(ref) == 0 {
+			return nil
+		}
+		return resolveURL(base, ref)
+	}
+
+	for _, node := range id.FindAny([]string{"video", "audio"}, nil) {
+		media := &Media{
+			Node:   node,
+			Tag:    node.Tag,
+			Src:    strings.TrimSpace(node.Attr("src")),
+			Poster: strings.TrimSpace(node.Attr("poster")),
+		}
+		media.URL = optionalURL(media.Src)
+		media.PosterURL = optionalURL(media.Poster)
+
+		for _, child := range node.Children {
+			src := strings.TrimSpace(child.Attr("src"))
+			switch child.Tag {
+			case "source":
+				media.Sources = append(media.Sources, &MediaSource{
+					Node:  child,
+					Src:   src,
+					URL:   optionalURL(src),
+					Type:  strings.TrimSpace(child.Attr("type")),
+					Media: strings.TrimSpace(child.Attr("media")),
+				})
+			case "track":
+				kind := strings.ToLower(strings.TrimSpace(child.Attr("kind")))
+				if len(kind) == 0 {
+					kind = "subtitles"
+				}
+				media.Tracks = append(media.Tracks, &MediaTrack{
+					Node:    child,
+					Src:     src,
+					URL:     optionalURL(src),
+					Kind:    kind,
+					Label:   strings.TrimSpace(child.Attr("label")),
+					SrcLang: strings.TrimSpace(child.Attr("srclang")),
+					Default: child.HasAttr("default"),
+				})
+			}
+		}
+		result = append(result, media)
+	}
+
+	return result
+}
+
+//
+// Captions : The caption and subtitle tracks of the media.
+//
+func (id *Media) Captions() (result []*MediaTrack) {
+	for _, track := range id.Tracks {
+		if track.Kind == "captions" || track.Kind == "subtitles" {
+			result = append(result, track)
+		}
+	}
+
+	return result
+}
@@ -0,0 +1,72 @@
+// Copyright 2016, Marc Lavergne <mlavergn@gmail.com>. All rights reserved.
+// Use of this source code is governed by the MIT
+// license that can be found in the LICENSE file.
+
+package godom
+
+import (
+	"net/url"
+	"testing"
+)
+
+func TestImages(t *testing.T) {
+	d := NewDOM()
+	d.SetContents(`<html><body>
+<img src="a.png" alt=" A " width="100" height="50px" loading="Lazy">
+<figure><div><img srcset="b-1.png 1x, /b-2.png 2x"></div><figcaption>The <em>B</em> image</figcaption></figure>
+<img width="auto">
+</body></html>`)
+	d.url, _ = url.Parse("https://example.com/p/")
+
+	images := d.Images()
+	if len(images) != 3 {
+		d.Dump()
+		t.Fatalf("unexpected images %d", len(images))
+	}
+	a := images[0]
+	if a.URL.String() != "https://example.com/p/a.png" || a.Alt != "A" || a.Width != 100 || a.Height != 50 || a.Loading != "lazy" || len(a.Caption) != 0 {
+		t.Errorf("unexpected image %+v", a)
+	}
+	b := images[1]
+	if b.URL != nil || len(b.Srcset) != 2 || b.Srcset[1].URL != "https://example.com/b-2.png" || b.Srcset[1].Density != 2 || b.Caption != "The B image" {
+		t.Errorf("unexpected image %+v", b)
+	}
+	if images[2].Width != 0 || images[2].URL != nil {
+		t.Errorf("unexpected image %+v", images[2])
+	}
+}
+
+func TestMedia(t *testing.T) {
+	d := NewDOM()
+	d.SetContents(`<html><body>
+<video poster="p.jpg" controls>
+  <source src="v.webm" type="video/webm">
+  <source src="/v.mp4" type="video/mp4" media="(min-width: 800px)">
+  <track src="en.vtt" kind="Captions" srclang="en" label="English" default>
+  <track src="fr.vtt" srclang="fr">
+  <track src="ch.vtt" kind="chapters">
+</video>
+<audio src="a.mp3"></audio>
+</body></html>`)
+	d.url, _ = url.Parse("https://example.com/m/")
+
+	media := d.Media()
+	if len(media) != 2 {
+		d.Dump()
+		t.Fatalf("unexpected media %d", len(media))
+	}
+	video := media[0]
+	if video.Tag != "video" || video.URL != nil || video.PosterURL.String() != "https://example.com/m/p.jpg" {
+		t.Errorf("unexpected video %+v", video)
+	}
+	if len(video.Sources) != 2 || video.Sources[1].URL.String() != "https://example.com/v.mp4" || video.Sources[1].Type != "video/mp4" || video.Sources[1].Media != "(min-width: 800px)" {
+		t.Errorf("unexpected sources %+v", video.Sources)
+	}
+	captions := video.Captions()
+	if len(video.Tracks) != 3 || len(captions) != 2 || captions[0].Kind != "captions" || !captions[0].Default || captions[1].Kind != "subtitles" || captions[1].SrcLang != "fr" {
+		t.Errorf("unexpected tracks %+v", video.Tracks)
+	}
+	if media[1].Tag != "audio" || media[1].URL.String() != "https://example.com/m/a.mp3" {
+		t.Errorf("unexpected audio %+v", media[1])
+	}
+}
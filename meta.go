@@ -0,0 +1,162 @@
+// Copyright 2016 Marc Lavergne <mlavergn@gmail.com>. All rights reserved.
+// Use of this source code is governed by
+// license that can be found in the LICENSE file.
+
+package godom
+
+import (
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// SocialMeta def
+// OpenGraph and Twitter Card metadata, Properties holds every og:, twitter:,
+// article:, etc. value keyed by its lowercased property name in document order
+type SocialMeta struct {
+	Title       string
+	Description string
+	Image       string
+	URL         string
+	Type        string
+	SiteName    string
+	Locale      string
+
+	TwitterCard        string
+	TwitterSite        string
+	TwitterCreator     string
+	TwitterTitle       string
+	TwitterDescription string
+	TwitterImage       string
+
+	Properties map[string][]string
+}
+
+//
+// SocialMeta : The OpenGraph and Twitter Card metadata of the document, read
+// from <meta property> and <meta name> tags. The first value of a property wins.
+//
+func (id *DOM) SocialMeta() (result SocialMeta) {
+	result.Properties = map[string][]string{}
+
+	for _, node := range id.Find("meta", nil) {
+		key := node.Attr("property")
+		if len(key) == 0 {
+			key = node.Attr("name")
+		}
+		key = strings.ToLower(strings.TrimSpace(key))
+		// only namespaced keys such as og:title are social properties
+		if !strings.Contains(key, ":") || !node.HasAttr("content") {
+			continue
+		}
+		result.Properties[key] = append(result.Properties[key], strings.TrimSpace(node.Attr("content")))
+	}
+
+	first := func(keys ...string) string {
+		for _, key := range keys {
+			if values := result.Properties[key]; len(values) > 0 {
+				return values[0]
+			}
+		}
+		return ""
+	}
+
+	result.Title = first("og:title")
+	result.Description = first("og:description")
+	result.Image = first("og:image", "og:image:url", "og:image:secure_url")
+	result.URL = first("og:url")
+	result.Type = first("og:type")
+	result.SiteName = first("og:site_name")
+	result.Locale = first("og:locale")
+
+	result.TwitterCard = first("twitter:card")
+	result.TwitterSite = first("twitter:site")
+	result.TwitterCreator = first("twitter:creator")
+	result.TwitterTitle = first("twitter:title")
+	result.TwitterDescription = first("twitter:description")
+	result.TwitterImage = first("twitter:image", "twitter:image:src")
+
+	return result
+}
+
+// MetaRefresh def
+// A <meta http-equiv="refresh"> redirect, Target is the URL as written, empty
+// when the page reloads itself, and URL the target resolved against the base
+type MetaRefresh struct {
+	Delay  time.Duration
+	Target string
+	URL    *url.URL
+}
+
+//
+// MetaRefresh : The refresh declared by the first valid <meta http-equiv="refresh">,
+// nil when there is none. Its content is a delay in seconds optionally
+// followed by the target, eg. "5; url=/next".
+//
+func (id *DOM) MetaRefresh() *MetaRefresh {
+	for _, node := range id.Find("meta", nil) {
+		if !strings.EqualFold(strings.TrimSpace(node.Attr("http-equiv")), "refresh") {
+			continue
+		}
+		if result := parseMetaRefresh(node.Attr("content")); result != nil {
+			if len(result.Target) > 0 {
+				result.URL = resolveURL(id.BaseURL(), result.Target)
+			}
+			return result
+		}
+	}
+
+	return nil
+}
+
+//
+// parseMetaRefresh : Parse the content of a refresh, nil when it has no delay.
+//
+func parseMetaRefresh(content string) *MetaRefresh {
+	content = strings.TrimSpace(content)
+	end := strings.IndexFunc(content, func(r rune) bool {
+		return (r < '0' || r > '9') && r != '.'
+	})
+	if end < 0 {
+		end = len(content)
+	}
+	seconds, err := strconv.ParseFloat(content[:end], 64)
+	if err != nil {
+		return nil
+	}
+
+	result := &MetaRefresh{Delay: time.Duration(seconds * float64(time.Second))}
+	target := strings.TrimLeft(content[end:], " \t\n\f\r;,")
+	if len(target) >= 3 && strings.EqualFold(target[:3], "url") {
+		if rest := strings.TrimLeft(target[3:], " \t\n\f\r"); strings.HasPrefix(rest, "=") {
+			target = strings.TrimLeft(rest[1:], " \t\n\f\r")
+		}
+	}
+	if len(target) > 0 && (target[0] == '"' || target[0] == '\'') {
+		if end := strings.IndexByte(target[1:], target[0]); end >= 0 {
+			target = target[1 : end+1]
+		} else {
+			target = target[1:]
+		}
+	}
+	result.Target = strings.TrimSpace(target)
+
+	return result
+}
+
+//
+// CanonicalURL : The href of the first <link rel="canonical"> resolved against
+// the base, nil when there is none or it can't be parsed.
+//
+func (id *DOM) CanonicalURL() *url.URL {
+	for _, node := range id.Find("link", nil) {
+		for _, rel := range strings.Fields(strings.ToLower(node.Attr("rel"))) {
+			if rel == "canonical" && len(strings.TrimSpace(node.Attr("href"))) > 0 {
+				return resolveURL(id.BaseURL(), strings.TrimSpace(node.Attr("href")))
+			}
+		}
+	}
+
+	return nil
+}
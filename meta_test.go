@@ -0,0 +1,81 @@
+// Copyright 2016, Marc Lavergne <mlavergn@gmail.com>. All rights reserved.
+// Use of this source code is governed by the MIT
+// license that can be found in the LICENSE file.
+
+package godom
+
+import (
+	"net/url"
+	"testing"
+	"time"
+)
+
+func TestSocialMeta(t *testing.T) {
+	d := NewDOM()
+	d.SetContents(`<html><head>
+<meta property="og:title" content="Widget">
+<meta property="og:image:url" content="https://example.com/a.png">
+<meta property="og:image" content="https://example.com/b.png">
+<meta property="OG:URL" content=" https://example.com/widget ">
+<meta name="twitter:card" content="summary_large_image">
+<meta property="twitter:site" content="@example">
+<meta name="description" content="not social">
+</head></html>`)
+
+	meta := d.SocialMeta()
+	if meta.Title != "Widget" || meta.URL != "https://example.com/widget" {
+		t.Errorf("failed to read OpenGraph %+v", meta)
+	}
+	if meta.Image != "https://example.com/b.png" {
+		t.Errorf("failed to prefer og:image [%s]", meta.Image)
+	}
+	if meta.TwitterCard != "summary_large_image" || meta.TwitterSite != "@example" {
+		t.Errorf("failed to read Twitter Card %+v", meta)
+	}
+	if _, ok := meta.Properties["description"]; ok || len(meta.Properties) != 6 {
+		t.Errorf("failed to collect properties %v", meta.Properties)
+	}
+}
+
+func TestMetaRefresh(t *testing.T) {
+	checks := []struct {
+		content string
+		delay   time.Duration
+		target  string
+	}{
+		{"5; url=/next", 5 * time.Second, "/next"},
+		{"0;URL='https://x.org/a?b=1'", 0, "https://x.org/a?b=1"},
+		{"1.5, page.html", 1500 * time.Millisecond, "page.html"},
+		{"30", 30 * time.Second, ""},
+	}
+	for _, check := range checks {
+		d := NewDOM()
+		d.SetContents("<html><head><meta http-equiv=\"Refresh\" content=\"" + check.content + "\"></head></html>")
+		d.url, _ = url.Parse("https://example.com/dir/")
+		refresh := d.MetaRefresh()
+		if refresh == nil || refresh.Delay != check.delay || refresh.Target != check.target {
+			t.Errorf("unexpected refresh %+v for %s", refresh, check.content)
+		}
+	}
+
+	d := NewDOM()
+	d.SetContents("<html><head><meta http-equiv=\"refresh\" content=\"soon\"><meta http-equiv=\"refresh\" content=\"2;url=b.html\"></head></html>")
+	d.url, _ = url.Parse("https://example.com/dir/a.html")
+	if refresh := d.MetaRefresh(); refresh == nil || refresh.URL.String() != "https://example.com/dir/b.html" {
+		t.Errorf("failed to resolve refresh target")
+	}
+	d = NewDOM()
+	d.SetContents("<html></html>")
+	if d.MetaRefresh() != nil || d.CanonicalURL() != nil {
+		t.Errorf("unexpected refresh or canonical URL")
+	}
+}
+
+func TestCanonicalURL(t *testing.T) {
+	d := NewDOM()
+	d.SetContents("<html><head><link rel=\"alternate\" href=\"/fr\"><link rel=\"Canonical\" href=\"/product/1\"></head></html>")
+	d.url, _ = url.Parse("https://example.com/product/1?ref=x")
+	if canonical := d.CanonicalURL(); canonical == nil || canonical.String() != "https://example.com/product/1" {
+		t.Errorf("unexpected canonical URL %s", canonical)
+	}
+}
@@ -0,0 +1,134 @@
+// Copyright 2016 Marc Lavergne <mlavergn@gmail.com>. All rights reserved.
+// Use of this source code is governed by
+// license that can be found in the LICENSE file.
+
+package godom
+
+import (
+	"strings"
+)
+
+// microdataURLAttrs elements whose property value is a URL attribute
+var microdataURLAttrs = map[string]string{
+	"a": "href", "area": "href", "link": "href",
+	"audio": "src", "embed": "src", "iframe": "src", "img": "src", "source": "src", "track": "src", "video": "src",
+	"object": "data",
+}
+
+//
+// Microdata : Extract the top-level microdata items following the WHATWG
+// microdata to JSON algorithm, eg. {"items": [{"type": [...], "properties": {...}}]}
+//
+func (id *DOM) Microdata() (result JSONMap) {
+	items := []interface{}{}
+	for _, node := range id.document {
+		if node.HasAttr("itemscope") && !node.HasAttr("itemprop") && pseudoTags[node.Tag] == 0 {
+			items = append(items, id.microdataItem(node, map[*DOMNode]bool{}))
+		}
+	}
+
+	return JSONMap{"items": items}
+}
+
+//
+// DOM: Build the JSON object for the item node, memory guards against itemref cycles.
+//
+func (id *DOM) microdataItem(item *DOMNode, memory map[*DOMNode]bool) (result JSONMap) {
+	memory[item] = true
+	result = JSONMap{}
+
+	if itemType := strings.Fields(item.Attr("itemtype")); len(itemType) > 0 {
+		types := make([]interface{}, len(itemType))
+		for i, t := range itemType {
+			types[i] = t
+		}
+		result["type"] = types
+	}
+	if item.HasAttr("itemid") {
+		result["id"] = strings.TrimSpace(item.Attr("itemid"))
+	}
+
+	properties := JSONMap{}
+	for _, prop := range id.microdataProperties(item) {
+		var value interface{}
+		if prop.HasAttr("itemscope") {
+			if memory[prop] {
+				value = "ERROR"
+			} else {
+				value = id.microdataItem(prop, memory)
+			}
+		} else {
+			value = microdataValue(prop)
+		}
+		for _, name := range strings.Fields(prop.Attr("itemprop")) {
+			values, _ := properties[name].([]interface{})
+			properties[name] = append(values, value)
+		}
+	}
+	result["properties"] = properties
+	delete(memory, item)
+
+	return result
+}
+
+//
+// DOM: The property nodes of the item, including those reached via itemref,
+// in document order.
+//
+func (id *DOM) microdataProperties(item *DOMNode) (result []*DOMNode) {
+	// the roots to crawl are the children of the item and the itemref targets
+	pending := append([]*DOMNode{}, item.Children...)
+	for _, ref := range strings.Fields(item.Attr("itemref")) {
+		for _, node := range id.document {
+			if node.Attr("id") == ref && pseudoTags[node.Tag] == 0 {
+				pending = append(pending, node)
+				break
+			}
+		}
+	}
+
+	seen := map[*DOMNode]bool{item: true}
+	for len(pending) > 0 {
+		node := pending[0]
+		pending = pending[1:]
+		if seen[node] {
+			continue
+		}
+		seen[node] = true
+
+		if node.HasAttr("itemprop") {
+			result = append(result, node)
+		}
+		// nested items own their properties
+		if !node.HasAttr("itemscope") {
+			pending = append(pending, node.Children...)
+		}
+	}
+
+	// the crawl is breadth first, order by document position
+	sortNodes(result)
+
+	return result
+}
+
+//
+// microdataValue : The property value of a non-item property node.
+//
+func microdataValue(node *DOMNode) string {
+	if attr, ok := microdataURLAttrs[node.Tag]; ok {
+		return node.Attr(attr)
+	}
+
+	switch node.Tag {
+	case "meta":
+		return node.Attr("content")
+	case "data", "meter":
+		return node.Attr("value")
+	case "time":
+		if node.HasAttr("datetime") {
+			return node.Attr("datetime")
+		}
+	}
+
+	return node.ReaderText()
+}
@@ -0,0 +1,38 @@
+// Copyright 2016, Marc Lavergne <mlavergn@gmail.com>. All rights reserved.
+// Use of this source code is governed by the MIT
+// license that can be found in the LICENSE file.
+
+package godom
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestMicrodata(t *testing.T) {
+	d := NewDOM()
+	d.SetContents(`<html><body>
+<div itemscope itemtype="https://schema.org/Product" itemref="extra">
+  <h1 itemprop="name">Widget</h1>
+  <img itemprop="image" src="/widget.png">
+  <div itemprop="offers" itemscope itemtype="https://schema.org/Offer">
+    <meta itemprop="priceCurrency" content="USD">
+    <span itemprop="price">9.99</span>
+  </div>
+  <span itemprop="color tag">Red</span>
+</div>
+<p id="extra" itemprop="description">A fine widget</p>
+</body></html>`)
+
+	bytes, err := json.Marshal(d.Microdata())
+	if err != nil {
+		t.Fatalf("failed to marshal microdata %s", err)
+	}
+
+	expected := `{"items":[{"properties":{"color":["Red"],"description":["A fine widget"],"image":["/widget.png"],"name":["Widget"],` +
+		`"offers":[{"properties":{"price":["9.99"],"priceCurrency":["USD"]},"type":["https://schema.org/Offer"]}],"tag":["Red"]},` +
+		`"type":["https://schema.org/Product"]}]}`
+	if string(bytes) != expected {
+		t.Errorf("failed to extract microdata %s", bytes)
+	}
+}
@@ -0,0 +1,616 @@
+// Copyright 2016 Marc Lavergne <mlavergn@gmail.com>. All rights reserved.
+// Use of this source code is governed by
+// license that can be found in the LICENSE file.
+
+package godom
+
+import (
+	"errors"
+	"sort"
+	"strings"
+)
+
+// ErrNotChild the reference node is not a child of the node
+var ErrNotChild = errors.New("godom: node is not a child")
+
+// ErrHierarchy the node cannot be inserted at the requested position
+var ErrHierarchy = errors.New("godom: node cannot be inserted here")
+
+// ErrFrozen the DOM has been frozen and can no longer be modified
+var ErrFrozen = errors.New("godom: DOM is frozen")
+
+// pseudoTags tags given to the non-element nodes of the document
+var pseudoTags = map[string]int{"document": 1, "comment": 1, "doctype": 1, "error": 1}
+
+//
+// AppendChild : Append child and its subtree as the last child of the node.
+// The child is first removed from its current position, including when it
+// belongs to another DOM.
+//
+func (id *DOMNode) AppendChild(child *DOMNode) error {
+	return id.insertChild(child, len(id.Children))
+}
+
+//
+// InsertBefore : Insert child and its subtree immediately before the
+// reference child ref.
+//
+func (id *DOMNode) InsertBefore(child *DOMNode, ref *DOMNode) error {
+	position := id.childPosition(ref)
+	if position < 0 {
+		return ErrNotChild
+	}
+
+	return id.insertChild(child, position)
+}
+
+//
+// RemoveChild : Remove child and its subtree from the node, the child is
+// left detached from the DOM.
+//
+func (id *DOMNode) RemoveChild(child *DOMNode) error {
+	position := id.childPosition(child)
+	if position < 0 {
+		return ErrNotChild
+	}
+	if id.frozen() {
+		return ErrFrozen
+	}
+
+	id.detachChild(position)
+	child.setDOM(nil)
+
+	return nil
+}
+
+//
+// ReplaceChild : Replace the child old with child and its subtree.
+//
+func (id *DOMNode) ReplaceChild(child *DOMNode, old *DOMNode) error {
+	if child == old {
+		return nil
+	}
+
+	err := id.InsertBefore(child, old)
+	if err != nil {
+		return err
+	}
+
+	return id.RemoveChild(old)
+}
+
+//
+// SetText : Replace the children and text of the node with the text, the
+// children are left detached from the DOM. An empty text leaves the node
+// empty. Fails with ErrFrozen when the DOM is frozen.
+//
+func (id *DOMNode) SetText(text string) error {
+	if id.frozen() {
+		return ErrFrozen
+	}
+
+	if id.dom != nil {
+		// the comments of the node are removed along with the children
+		if nodes := id.dom.subtreeNodes(id)[1:]; len(nodes) > 0 {
+			id.dom.removeSubtree(id, nodes)
+			for _, node := range nodes {
+				node.dom = nil
+			}
+		}
+	}
+	for _, child := range id.Children {
+		child.Parent = nil
+		child.setDOM(nil)
+	}
+	id.Children = []*DOMNode{}
+	id.TextFragments = nil
+	id.textIndex = nil
+	if len(text) > 0 {
+		id.TextFragments = []string{text}
+		id.textIndex = []int{0}
+	}
+	id.dirty = true
+
+	return nil
+}
+
+//
+// ReplaceTextFunc : Replace each text fragment of the node and its element
+// descendants with the result of fn, marking the changed nodes as modified.
+// Fragments replaced with empty text are kept so the child positions of the
+// remaining fragments are unchanged. Fails with ErrFrozen when the DOM is
+// frozen.
+//
+func (id *DOMNode) ReplaceTextFunc(fn func(text string) string) error {
+	if id.frozen() {
+		return ErrFrozen
+	}
+
+	for _, node := range id.elementNodes() {
+		for i, text := range node.TextFragments {
+			if replaced := fn(text); replaced != text {
+				node.TextFragments[i] = replaced
+				node.dirty = true
+			}
+		}
+	}
+
+	return nil
+}
+
+//
+// DOMNode: The position of child in Children or -1.
+//
+func (id *DOMNode) childPosition(child *DOMNode) int {
+	if child == nil || child.Parent != id {
+		return -1
+	}
+	for i, node := range id.Children {
+		if node == child {
+			return i
+		}
+	}
+
+	return -1
+}
+
+//
+// DOMNode: Is the owning DOM frozen?
+//
+func (id *DOMNode) frozen() bool {
+	return id.dom != nil && id.dom.frozen
+}
+
+//
+// DOMNode: Link child at position in Children and update the owning DOM.
+//
+func (id *DOMNode) insertChild(child *DOMNode, position int) error {
+	if child == nil {
+		return ErrHierarchy
+	}
+	if id.frozen() || child.frozen() {
+		return ErrFrozen
+	}
+	// the child can't be the node or one of its ancestors
+	for node := id; node != nil; node = node.Parent {
+		if node == child {
+			return ErrHierarchy
+		}
+	}
+
+	// capture the subtree document entries before detaching
+	var subtree []*DOMNode
+	if child.dom != nil {
+		subtree = child.dom.subtreeNodes(child)
+	} else {
+		subtree = child.elementNodes()
+	}
+
+	if parent := child.Parent; parent != nil {
+		oldPosition := parent.childPosition(child)
+		if oldPosition >= 0 {
+			// account for the shift when moving within the same parent
+			if parent == id && oldPosition < position {
+				position--
+			}
+			parent.detachChild(oldPosition)
+		}
+	} else if child.dom != nil {
+		child.dom.removeSubtree(nil, subtree)
+	}
+
+	id.linkChildren(position, []*DOMNode{child}, subtree)
+
+	return nil
+}
+
+//
+// DOMNode: Insert detached children and their subtrees at position with a
+// single update of the owning DOM, eg. the copies of a template.
+//
+func (id *DOMNode) insertDetached(position int, children []*DOMNode) error {
+	if id.frozen() {
+		return ErrFrozen
+	}
+
+	subtree := []*DOMNode{}
+	for _, child := range children {
+		subtree = append(subtree, child.elementNodes()...)
+	}
+	id.linkChildren(position, children, subtree)
+
+	return nil
+}
+
+//
+// DOMNode: Link the unlinked children at position in Children and add their
+// subtree entries to the owning DOM.
+//
+func (id *DOMNode) linkChildren(position int, children []*DOMNode, subtree []*DOMNode) {
+	if len(children) == 0 {
+		return
+	}
+
+	// shift text fragments following the insertion point
+	for i := range id.textIndex {
+		if id.textIndex[i] > position {
+			id.textIndex[i] += len(children)
+		}
+	}
+	id.Children = append(id.Children[:position], append(append([]*DOMNode(nil), children...), id.Children[position:]...)...)
+	for _, child := range children {
+		child.Parent = id
+	}
+	id.dirty = true
+
+	for _, node := range subtree {
+		node.dom = id.dom
+	}
+	if id.dom != nil {
+		id.dom.insertSubtree(id, position+len(children), subtree)
+	}
+}
+
+//
+// DOMNode: Unlink the child at position from Children and the owning DOM.
+//
+func (id *DOMNode) detachChild(position int) {
+	child := id.Children[position]
+
+	if child.dom != nil {
+		child.dom.removeSubtree(id, child.dom.subtreeNodes(child))
+	}
+
+	id.unlinkChild(position)
+}
+
+//
+// DOMNode: Unlink the child at position from Children, leaving the document
+// entries to the caller.
+//
+func (id *DOMNode) unlinkChild(position int) {
+	child := id.Children[position]
+	id.Children = append(id.Children[:position], id.Children[position+1:]...)
+	for i := range id.textIndex {
+		if id.textIndex[i] > position {
+			id.textIndex[i]--
+		}
+	}
+	child.Parent = nil
+	id.dirty = true
+}
+
+//
+// DOMNode: Replace the child at position with its text and children, leaving
+// the document entries to the caller.
+//
+func (id *DOMNode) unwrapChild(position int) {
+	child := id.Children[position]
+
+	fragments := []string{}
+	textIndex := []int{}
+	children := []*DOMNode{}
+	appendText := func(text string) {
+		fragments = append(fragments, text)
+		textIndex = append(textIndex, len(children))
+	}
+	id.eachContent(appendText, func(node *DOMNode) {
+		if node != child {
+			children = append(children, node)
+			return
+		}
+		node.eachContent(appendText, func(grandchild *DOMNode) {
+			grandchild.Parent = id
+			children = append(children, grandchild)
+		})
+	})
+
+	id.TextFragments = fragments
+	id.textIndex = textIndex
+	id.Children = children
+	id.dirty = true
+
+	child.Parent = nil
+	child.Children = []*DOMNode{}
+	child.TextFragments = nil
+	child.textIndex = nil
+}
+
+//
+// DOMNode: The node and its element descendants in document order.
+//
+func (id *DOMNode) elementNodes() (result []*DOMNode) {
+	result = append(result, id)
+	for _, child := range id.Children {
+		result = append(result, child.elementNodes()...)
+	}
+
+	return result
+}
+
+//
+// DOMNode: Set the owning DOM for the node and its element descendants.
+//
+func (id *DOMNode) setDOM(dom *DOM) {
+	for _, node := range id.elementNodes() {
+		node.dom = dom
+	}
+}
+
+//
+// DOM: The position of node in the document or -1.
+//
+func (id *DOM) documentPosition(node *DOMNode) int {
+	// indexes are kept in sync with positions, so try the fast path first
+	i := node.Index - 1
+	if i >= 0 && i < len(id.document) && id.document[i] == node {
+		return i
+	}
+	for i, docNode := range id.document {
+		if docNode == node {
+			return i
+		}
+	}
+
+	return -1
+}
+
+//
+// DOM: The document entries for node and its subtree, including non-element
+// nodes such as comments.
+//
+func (id *DOM) subtreeNodes(node *DOMNode) []*DOMNode {
+	start := id.documentPosition(node)
+	if start < 0 {
+		return node.elementNodes()
+	}
+	end := start + 1
+	for end < len(id.document) && isAncestorNode(node, id.document[end]) {
+		end++
+	}
+
+	result := make([]*DOMNode, end-start)
+	copy(result, id.document[start:end])
+
+	return result
+}
+
+//
+// DOM: Remove the contiguous run of document entries starting with nodes[0].
+//
+func (id *DOM) removeDocumentNodes(nodes []*DOMNode) {
+	if len(nodes) == 0 {
+		return
+	}
+	start := id.documentPosition(nodes[0])
+	if start < 0 {
+		return
+	}
+	id.document = append(id.document[:start], id.document[start+len(nodes):]...)
+}
+
+//
+// DOM: Insert the subtree entries for the children of parent preceding the
+// child at next, returning where they were inserted.
+//
+func (id *DOM) insertDocumentNodes(parent *DOMNode, next int, nodes []*DOMNode) int {
+	// the entries follow the subtree of the preceding sibling, or the parent
+	var at int
+	if next < len(parent.Children) {
+		at = id.documentPosition(parent.Children[next])
+	} else {
+		at = id.documentPosition(parent)
+		if at >= 0 {
+			at++
+			for at < len(id.document) && isAncestorNode(parent, id.document[at]) {
+				at++
+			}
+		}
+	}
+	if at < 0 {
+		at = len(id.document)
+	}
+
+	document := make([]*DOMNode, 0, len(id.document)+len(nodes))
+	document = append(document, id.document[:at]...)
+	document = append(document, nodes...)
+	document = append(document, id.document[at:]...)
+	id.document = document
+
+	return at
+}
+
+//
+// DOM: Remove the contiguous document entries of a subtree of parent,
+// updating the numbering and indexes of the nodes that remain rather than
+// rebuilding them.
+//
+func (id *DOM) removeSubtree(parent *DOMNode, nodes []*DOMNode) {
+	if len(nodes) == 0 {
+		return
+	}
+	start := id.documentPosition(nodes[0])
+	if start < 0 {
+		return
+	}
+
+	// buckets are searched by the numbering before the removal
+	ids := map[string]int{}
+	for _, node := range nodes {
+		if !id.indexed(node) {
+			continue
+		}
+		id.nodes[node.Tag] = removeIndexed(id.nodes[node.Tag], node)
+		if len(id.nodes[node.Tag]) == 0 {
+			delete(id.nodes, node.Tag)
+		}
+		if id.attrs != nil {
+			for key := range node.Attributes {
+				key = strings.ToLower(key)
+				id.attrs[key] = removeIndexed(id.attrs[key], node)
+				if len(id.attrs[key]) == 0 {
+					delete(id.attrs, key)
+				}
+			}
+		}
+		if elementID := node.Attr("id"); len(elementID) > 0 && id.ids[elementID] == node {
+			delete(id.ids, elementID)
+			ids[elementID] = 1
+		}
+	}
+
+	end := start + len(nodes)
+	id.document = append(id.document[:start], id.document[end:]...)
+	// release the entries past the end
+	clear(id.document[len(id.document) : len(id.document)+len(nodes)])
+	id.renumber(start)
+
+	// an id passes to the next element holding it
+	if len(ids) > 0 {
+		for _, node := range id.document {
+			if elementID := node.Attr("id"); ids[elementID] == 1 && id.indexed(node) {
+				id.indexID(node)
+			}
+		}
+	}
+	id.changed(parent, nodes)
+}
+
+//
+// DOM: Insert the subtree entries of children of parent preceding the child
+// at next, numbering and indexing them in place.
+//
+func (id *DOM) insertSubtree(parent *DOMNode, next int, nodes []*DOMNode) {
+	at := id.insertDocumentNodes(parent, next, nodes)
+	id.renumber(at)
+
+	for _, node := range nodes {
+		node.depth = 0
+		if node.Parent != nil {
+			node.depth = node.Parent.depth + 1
+		}
+		node.subtreeSize = 0
+		if !id.indexed(node) {
+			continue
+		}
+		id.nodes[node.Tag] = insertIndexed(id.nodes[node.Tag], node)
+		id.indexID(node)
+		if id.options.IndexAttributes && pseudoTags[node.Tag] == 0 {
+			if id.attrs == nil {
+				id.attrs = map[string][]*DOMNode{}
+			}
+			for key := range node.Attributes {
+				key = strings.ToLower(key)
+				id.attrs[key] = insertIndexed(id.attrs[key], node)
+			}
+		}
+	}
+	id.changed(parent, nodes)
+}
+
+//
+// DOM: Is the node in the tag index?
+//
+func (id *DOM) indexed(node *DOMNode) bool {
+	return pseudoTags[node.Tag] == 0 || (node.Tag == "comment" && id.options.IndexComments)
+}
+
+//
+// DOM: Number the nodes from document position from onwards.
+//
+func (id *DOM) renumber(from int) {
+	for i := from; i < len(id.document); i++ {
+		id.document[i].Index = i + 1
+	}
+	id.nodeCount = len(id.document)
+}
+
+//
+// DOM: Discard the state derived from the document after the nodes were
+// added to or removed from the children of parent.
+//
+func (id *DOM) changed(parent *DOMNode, nodes []*DOMNode) {
+	id.numbered = false
+	id.invalidateFinds()
+	for node := parent; node != nil; node = node.Parent {
+		node.subtreeSize = 0
+	}
+	for _, node := range nodes {
+		if node == id.rootNode || node.Tag == "html" {
+			id.rootNode = nil
+		}
+		if node.Tag == "style" {
+			id.styles = nil
+		}
+	}
+}
+
+//
+// removeIndexed : Remove the node from the bucket ordered by Index.
+//
+func removeIndexed(bucket []*DOMNode, node *DOMNode) []*DOMNode {
+	i := sort.Search(len(bucket), func(i int) bool { return bucket[i].Index >= node.Index })
+	if i >= len(bucket) || bucket[i] != node {
+		// not numbered in order, eg. a node found by a stale bucket
+		for i = 0; i < len(bucket) && bucket[i] != node; i++ {
+		}
+		if i == len(bucket) {
+			return bucket
+		}
+	}
+
+	return append(bucket[:i], bucket[i+1:]...)
+}
+
+//
+// insertIndexed : Insert the node into the bucket ordered by Index.
+//
+func insertIndexed(bucket []*DOMNode, node *DOMNode) []*DOMNode {
+	i := sort.Search(len(bucket), func(i int) bool { return bucket[i].Index > node.Index })
+	bucket = append(bucket, nil)
+	copy(bucket[i+1:], bucket[i:])
+	bucket[i] = node
+
+	return bucket
+}
+
+//
+// DOM: Renumber the nodes in document order and rebuild the tag index.
+// This is O(n) in the size of the document.
+//
+func (id *DOM) reindex() {
+	for tag := range id.nodes {
+		delete(id.nodes, tag)
+	}
+	id.ids = map[string]*DOMNode{}
+	id.attrs = nil
+	for i, node := range id.document {
+		node.Index = i + 1
+		node.depth = 0
+		if node.Parent != nil {
+			node.depth = node.Parent.depth + 1
+		}
+		node.subtreeSize = 0
+		if id.indexed(node) {
+			id.indexNode(node)
+		}
+	}
+	id.nodeCount = len(id.document)
+	id.numbered = false
+	id.invalidateFinds()
+	id.rootNode = nil
+	id.styles = nil
+}
+
+//
+// isAncestorNode : Is parent a strict ancestor of node via the parent chain?
+//
+func isAncestorNode(parent *DOMNode, node *DOMNode) bool {
+	for node = node.Parent; node != nil; node = node.Parent {
+		if node == parent {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,197 @@
+// Copyright 2016, Marc Lavergne <mlavergn@gmail.com>. All rights reserved.
+// Use of this source code is governed by the MIT
+// license that can be found in the LICENSE file.
+
+package godom
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func checkIndexes(t *testing.T, d *DOM) {
+	for i, node := range d.document {
+		if node.Index != i+1 {
+			t.Errorf("node %s index %d vs expected %d", node.Tag, node.Index, i+1)
+		}
+	}
+}
+
+func TestRemoveChild(t *testing.T) {
+	d := NewDOM()
+	d.SetContents("<html><div id='a'>Hello <div class='ad'><p>Buy</p></div>world</div></html>")
+	p := d.Find("div", map[string]string{"id": "a"})
+	ad := d.Find("div", map[string]string{"class": "ad"})
+	if len(p) != 1 || len(ad) != 1 {
+		d.Dump()
+		t.Fatalf("failed to find nodes")
+	}
+
+	err := p[0].RemoveChild(ad[0])
+	if err != nil {
+		t.Errorf("failed to remove child %s", err)
+	}
+	if len(d.Find("p", nil)) != 0 || len(d.Find("div", nil)) != 1 {
+		d.Dump()
+		t.Errorf("failed to remove child from index")
+	}
+	if p[0].InnerHTML() != "Helloworld" {
+		t.Errorf("failed to remove child [%s]", p[0].InnerHTML())
+	}
+	checkIndexes(t, &d)
+
+	if p[0].RemoveChild(ad[0]) != ErrNotChild {
+		t.Errorf("failed to reject removed child")
+	}
+}
+
+func TestAppendChild(t *testing.T) {
+	d := NewDOM()
+	d.SetContents("<html><div id='a'><p>one</p><p>three</p></div></html>")
+	p := d.Find("div", map[string]string{"id": "a"})
+	if len(p) != 1 {
+		d.Dump()
+		t.Fatalf("failed to find node")
+	}
+
+	marker := NewDOMNode(0, nil, "span", DOMNodeAttributes{"class": "marker"})
+	err := p[0].AppendChild(&marker)
+	if err != nil {
+		t.Errorf("failed to append child %s", err)
+	}
+
+	two := NewDOMNode(0, nil, "p", DOMNodeAttributes{})
+	two.TextFragments = []string{"two"}
+	err = p[0].InsertBefore(&two, p[0].Children[1])
+	if err != nil {
+		t.Errorf("failed to insert child %s", err)
+	}
+
+	if p[0].InnerHTML() != "<p>one</p><p>two</p><p>three</p><span class=\"marker\"></span>" {
+		t.Errorf("failed to insert children [%s]", p[0].InnerHTML())
+	}
+	nodes := d.Find("p", nil)
+	if len(nodes) != 3 || nodes[1] != &two {
+		d.Dump()
+		t.Errorf("failed to index children in document order")
+	}
+	if len(d.ChildFind(p[0], "span", DOMNodeAttributes{"class": "marker"})) != 1 {
+		d.Dump()
+		t.Errorf("failed to index appended child")
+	}
+	checkIndexes(t, &d)
+
+	if p[0].AppendChild(d.RootNode()) != ErrHierarchy {
+		t.Errorf("failed to reject ancestor")
+	}
+}
+
+func TestReplaceChild(t *testing.T) {
+	d := NewDOM()
+	d.SetContents("<html><div id='a'><p>one</p><p>two</p></div><div id='b'><span>moved</span></div></html>")
+	a := d.Find("div", map[string]string{"id": "a"})
+	span := d.Find("span", nil)
+	if len(a) != 1 || len(span) != 1 {
+		d.Dump()
+		t.Fatalf("failed to find nodes")
+	}
+
+	err := a[0].ReplaceChild(span[0], a[0].Children[0])
+	if err != nil {
+		t.Errorf("failed to replace child %s", err)
+	}
+
+	if a[0].InnerHTML() != "<span>moved</span><p>two</p>" {
+		t.Errorf("failed to replace child [%s]", a[0].InnerHTML())
+	}
+	b := d.Find("div", map[string]string{"id": "b"})
+	if len(b) != 1 || len(b[0].Children) != 0 {
+		d.Dump()
+		t.Errorf("failed to move child")
+	}
+	if len(d.Find("p", nil)) != 1 {
+		d.Dump()
+		t.Errorf("failed to remove replaced child from index")
+	}
+	checkIndexes(t, &d)
+}
+
+func TestSetText(t *testing.T) {
+	d := NewDOM()
+	d.SetContents("<html><body><div id='a'>Call <b>555-1234</b> or <i>555-9876</i> today</div><p>after</p></body></html>")
+	a := d.Find("div", map[string]string{"id": "a"})[0]
+
+	a.ReplaceTextFunc(func(text string) string {
+		return strings.Replace(text, "555", "XXX", -1)
+	})
+	if a.InnerHTML() != "Call<b>XXX-1234</b>or<i>XXX-9876</i>today" || !a.Children[0].Dirty() {
+		t.Errorf("failed to replace text [%s]", a.InnerHTML())
+	}
+
+	a.SetText("redacted")
+	checkIndexes(t, &d)
+	if a.InnerHTML() != "redacted" || a.ReaderText() != "redacted" || len(d.Find("b", nil)) != 0 || len(d.Find("p", nil)) != 1 {
+		t.Errorf("failed to set text [%s]", a.InnerHTML())
+	}
+
+	a.SetText("")
+	if len(a.TextFragments) != 0 || a.OuterHTML() != `<div id="a"></div>` {
+		t.Errorf("failed to clear text [%s]", a.OuterHTML())
+	}
+
+	d = NewDOM()
+	d.SetContents("<div id='c'>x<!-- one --><p>y<!-- two --></p></div><span>z</span>")
+	c := d.ByID("c")
+	c.SetText("cleared")
+	checkIndexes(t, &d)
+	if len(d.Comments()) != 0 || c.NextSibling() == nil || c.NextSibling().Tag != "span" {
+		t.Errorf("expected the comments to be removed with the children, got %d", len(d.Comments()))
+	}
+
+	d.SetContents("<div id='b'><p>frozen</p></div>")
+	d.Freeze()
+	b := d.ByID("b")
+	if err := b.SetText("x"); err != ErrFrozen || len(d.ChildFind(b, "p", nil)) != 1 {
+		t.Errorf("expected SetText to fail with ErrFrozen, got %v", err)
+	}
+	if err := b.ReplaceTextFunc(strings.ToUpper); err != ErrFrozen || d.ChildFind(b, "p", nil)[0].Text() != "frozen" {
+		t.Errorf("expected ReplaceTextFunc to fail with ErrFrozen, got %v", err)
+	}
+}
+
+func TestIncrementalIndex(t *testing.T) {
+	d := NewDOM()
+	d.SetParseOptions(ParseOptions{IndexAttributes: true, IndexComments: true})
+	d.SetContents("<html><body><div id='a' class='x'><p id='b'>one</p><!-- c --><p id='b' class='y'>two</p></div><div id='c'><span id='a'>three</span></div></body></html>")
+
+	divs := d.Find("div", nil)
+	ps := d.Find("p", nil)
+	if len(divs) != 2 || len(ps) != 2 {
+		t.Fatalf("failed to find nodes")
+	}
+	if err := divs[1].AppendChild(ps[0]); err != nil {
+		t.Fatalf("failed to move child %s", err)
+	}
+	if err := divs[0].InsertBefore(d.Find("span", nil)[0], ps[1]); err != nil {
+		t.Fatalf("failed to move child %s", err)
+	}
+	if err := divs[1].SetText("four"); err != nil {
+		t.Fatalf("failed to set text %s", err)
+	}
+	checkIndexes(t, &d)
+
+	nodes := map[string][]*DOMNode{}
+	for tag, bucket := range d.nodes {
+		nodes[tag] = bucket
+	}
+	ids := d.ids
+	attrs := d.attrs
+	d.reindex()
+	if !reflect.DeepEqual(nodes, d.nodes) || !reflect.DeepEqual(ids, d.ids) || !reflect.DeepEqual(attrs, d.attrs) {
+		t.Errorf("failed to match the rebuilt indexes")
+	}
+	if d.ByID("a") != divs[0] || d.ByID("b") != ps[1] {
+		t.Errorf("failed to pass the ids on")
+	}
+}
@@ -0,0 +1,153 @@
+// Copyright 2016 Marc Lavergne <mlavergn@gmail.com>. All rights reserved.
+// Use of this source code is governed by
+// license that can be found in the LICENSE file.
+
+package godom
+
+// Option type
+// Configures a DOM created by NewDOM
+type Option func(dom *DOM)
+
+//
+// WithParseOptions : Parse with opts, replacing the parse options set by the
+// preceding options.
+//
+func WithParseOptions(opts ParseOptions) Option {
+	return func(dom *DOM) {
+		dom.options = opts
+	}
+}
+
+//
+// WithSkipTags : Leave the elements of the tags out of the DOM along with
+// their subtrees.
+//
+func WithSkipTags(tags ...string) Option {
+	return func(dom *DOM) {
+		dom.options.SkipTags = append(dom.options.SkipTags, tags...)
+	}
+}
+
+//
+// WithPreserveWhitespace : Keep the whitespace of the text of the elements of
+// the tags and of their descendants instead of trimming it.
+//
+func WithPreserveWhitespace(tags ...string) Option {
+	return func(dom *DOM) {
+		dom.options.PreserveWhitespace = append(dom.options.PreserveWhitespace, tags...)
+	}
+}
+
+//
+// WithEscapedHTML : Decide which text containing < is parsed as escaped HTML.
+//
+func WithEscapedHTML(mode EscapedHTML) Option {
+	return func(dom *DOM) {
+		dom.options.EscapedHTML = mode
+	}
+}
+
+//
+// WithEscapedSkipTags : Never parse the text of the elements of the tags as
+// escaped HTML, replacing the default of body.
+//
+func WithEscapedSkipTags(tags ...string) Option {
+	return func(dom *DOM) {
+		dom.options.EscapedSkipTags = append([]string{}, tags...)
+	}
+}
+
+//
+// WithEntities : Decide how character references are parsed.
+//
+func WithEntities(mode EntityMode) Option {
+	return func(dom *DOM) {
+		dom.options.Entities = mode
+	}
+}
+
+//
+// WithDiscardScriptText : Drop the text of script and style elements.
+//
+func WithDiscardScriptText() Option {
+	return func(dom *DOM) {
+		dom.options.DiscardScriptText = true
+	}
+}
+
+//
+// WithSourcePositions : Record the position of the start tag of each element.
+//
+func WithSourcePositions() Option {
+	return func(dom *DOM) {
+		dom.options.SourcePositions = true
+	}
+}
+
+//
+// WithLimits : Stop a parse holding more elements, nesting elements deeper, or
+// holding more bytes of text, 0 is unlimited.
+//
+func WithLimits(maxNodes int, maxDepth int, maxTextBytes int) Option {
+	return func(dom *DOM) {
+		dom.options.MaxNodes = maxNodes
+		dom.options.MaxDepth = maxDepth
+		dom.options.MaxTextBytes = maxTextBytes
+	}
+}
+
+//
+// WithPreserveCase : Keep the tag and attribute keys as written, see
+// ParseOptions.PreserveCase.
+//
+func WithPreserveCase() Option {
+	return func(dom *DOM) {
+		dom.options.PreserveCase = true
+	}
+}
+
+//
+// WithLogger : Send the messages of the DOM to logger.
+//
+func WithLogger(logger Logger) Option {
+	return func(dom *DOM) {
+		dom.logger = logger
+	}
+}
+
+//
+// WithIndexComments : Add comment nodes to the tag index.
+//
+func WithIndexComments() Option {
+	return func(dom *DOM) {
+		dom.options.IndexComments = true
+	}
+}
+
+//
+// WithIndexAttributes : Index the elements by attribute name for FindByAttr.
+//
+func WithIndexAttributes() Option {
+	return func(dom *DOM) {
+		dom.options.IndexAttributes = true
+	}
+}
+
+//
+// WithFindCache : Memoize the results of Find, see SetFindCache.
+//
+func WithFindCache() Option {
+	return func(dom *DOM) {
+		dom.findCache = map[findKey][]*DOMNode{}
+	}
+}
+
+//
+// WithArena : Allocate the parsed nodes from per-DOM chunks, see
+// NewDOMWithArena.
+//
+func WithArena() Option {
+	return func(dom *DOM) {
+		dom.arena = &nodeArena{}
+	}
+}
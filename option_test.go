@@ -0,0 +1,50 @@
+// Copyright 2016, Marc Lavergne <mlavergn@gmail.com>. All rights reserved.
+// Use of this source code is governed by the MIT
+// license that can be found in the LICENSE file.
+
+package godom
+
+import (
+	"testing"
+)
+
+func TestNewDOMOptions(t *testing.T) {
+	logger := &recordLogger{}
+	d := NewDOM(
+		WithSkipTags("script"),
+		WithPreserveWhitespace("pre"),
+		WithEscapedHTML(EscapedHTMLNever),
+		WithIndexAttributes(),
+		WithFindCache(),
+		WithLogger(logger),
+	)
+	d.SetContents("<html><body><script>x()</script><pre>  a  b  </pre><p itemprop=\"name\">&lt;b&gt;5&lt;/b&gt;</p></body></html>")
+
+	if len(d.Find("script", nil)) != 0 {
+		t.Errorf("expected script to be skipped")
+	}
+	if pre := d.Find("pre", nil); len(pre) != 1 || pre[0].Text() != "  a  b  " {
+		t.Errorf("expected the whitespace of pre to be kept")
+	}
+	if p := d.FindByAttr("itemprop"); len(p) != 1 || p[0].Text() != "<b>5</b>" || len(d.Find("b", nil)) != 0 {
+		t.Errorf("expected the escaped HTML to stay text")
+	}
+	if d.attrs == nil || d.findCache == nil || d.Logger() != Logger(logger) {
+		t.Errorf("expected the index, cache, and logger to be set")
+	}
+
+	d = NewDOM(WithLimits(3, 0, 0))
+	if err := d.SetContents("<html><body><p>a</p><p>b</p></body></html>"); err != ErrMaxNodes {
+		t.Errorf("expected ErrMaxNodes, got %v", err)
+	}
+
+	// a later option replaces the earlier parse options
+	d = NewDOM(WithSkipTags("p"), WithParseOptions(ParseOptions{DiscardScriptText: true}))
+	if len(d.options.SkipTags) != 0 || !d.options.DiscardScriptText || len(d.tags.skip) != 0 {
+		t.Errorf("unexpected options %+v", d.options)
+	}
+
+	if d = NewDOM(WithArena()); d.arena == nil {
+		t.Errorf("expected an arena")
+	}
+}
@@ -0,0 +1,186 @@
+// Copyright 2016 Marc Lavergne <mlavergn@gmail.com>. All rights reserved.
+// Use of this source code is governed by
+// license that can be found in the LICENSE file.
+
+package godom
+
+import (
+	"strings"
+)
+
+// defaultEscapedSkipTags elements whose text is not parsed as escaped HTML
+var defaultEscapedSkipTags = map[string]int{"body": 1}
+
+// scriptTags elements whose text is code, kept as is whatever the options
+var scriptTags = map[string]int{"script": 1, "style": 1}
+
+// EscapedHTML type
+// How text containing < is recognized as escaped HTML to be parsed into elements
+type EscapedHTML int
+
+// EscapedHTML values
+const (
+	// EscapedHTMLAny parses any text containing < as HTML
+	EscapedHTMLAny EscapedHTML = iota
+	// EscapedHTMLTags parses text holding a tag, eg. <b> or </b>, or a comment
+	// as HTML, leaving text such as "5 < 10" unchanged
+	EscapedHTMLTags
+	// EscapedHTMLNever keeps all text as text
+	EscapedHTMLNever
+)
+
+// ParseOptions def
+// Controls how the contents are parsed into the DOM, the zero value parses
+// as NewDOM always has
+type ParseOptions struct {
+	// PreserveWhitespace lists the tags, eg. pre, code, and textarea, whose text
+	// and the text of their descendants keeps its whitespace instead of being trimmed
+	PreserveWhitespace []string
+	// SourcePositions records the position of the start tag of each element,
+	// SetContents tokenizes the contents a second time to do so
+	SourcePositions bool
+	// SkipTags lists the elements left out of the DOM along with their subtrees
+	SkipTags []string
+	// EscapedSkipTags lists the elements whose text containing < is never
+	// parsed as escaped HTML, nil keeps the default of body. The text of script
+	// and style is never parsed as HTML
+	EscapedSkipTags []string
+	// DiscardScriptText drops the text of script and style elements
+	DiscardScriptText bool
+	// EscapedHTML decides which text containing < is parsed as escaped HTML,
+	// the zero value parses any
+	EscapedHTML EscapedHTML
+	// Entities decides how character references in text and attribute values
+	// are parsed, the zero value decodes them once
+	Entities EntityMode
+	// IndexComments adds comment nodes to the tag index, Find otherwise scans
+	// the document for the "comment" tag
+	IndexComments bool
+	// IndexAttributes indexes the elements by attribute name for FindByAttr
+	IndexAttributes bool
+	// PreserveCase keeps the tag and attribute keys as written, eg. viewBox,
+	// for TagName and rendering while lookups still ignore case. SetContents
+	// tokenizes the contents a second time to do so
+	PreserveCase bool
+	// MaxNodes, MaxDepth, and MaxTextBytes stop a parse holding more elements,
+	// nesting elements deeper, or holding more bytes of text, 0 is unlimited
+	MaxNodes     int
+	MaxDepth     int
+	MaxTextBytes int
+}
+
+// parseTags def
+// The tag sets compiled from the ParseOptions
+type parseTags struct {
+	preserve map[string]int
+	skip     map[string]int
+	// escaped is nil for the default tag set
+	escaped map[string]int
+}
+
+//
+// SetParseOptions : Set the options applied by subsequent parses.
+//
+func (id *DOM) SetParseOptions(opts ParseOptions) {
+	id.options = opts
+	id.tags = parseTags{
+		preserve: tagSet(opts.PreserveWhitespace),
+		skip:     tagSet(opts.SkipTags),
+	}
+	if opts.EscapedSkipTags != nil {
+		id.tags.escaped = tagSet(opts.EscapedSkipTags)
+	}
+}
+
+//
+// parseTags: The elements whose text is not parsed as escaped HTML.
+//
+func (id parseTags) escapedSkip() map[string]int {
+	if id.escaped == nil {
+		return defaultEscapedSkipTags
+	}
+
+	return id.escaped
+}
+
+//
+// DOM: Is the text of the element dropped by the options?
+//
+func (id *DOM) discardText(tag string) bool {
+	return id.options.DiscardScriptText && scriptTags[tag] == 1
+}
+
+//
+// DOM: Is the text of the element kept as text even when it looks like
+// escaped HTML?
+//
+func (id *DOM) keepsText(tag string) bool {
+	return scriptTags[tag] == 1 || id.tags.escapedSkip()[tag] == 1
+}
+
+//
+// DOM: Is the text escaped HTML to be parsed into elements?
+//
+func (id *DOM) escapedHTML(text string) bool {
+	switch id.options.EscapedHTML {
+	case EscapedHTMLNever:
+		return false
+	case EscapedHTMLTags:
+		return containsMarkup(text)
+	}
+
+	return strings.Contains(text, "<")
+}
+
+//
+// containsMarkup : Does the text hold a start or end tag closed by >, or a
+// comment?
+//
+func containsMarkup(text string) bool {
+	for i := strings.IndexByte(text, '<'); i >= 0; {
+		rest := text[i+1:]
+		if strings.HasPrefix(rest, "!--") {
+			return true
+		}
+		rest = strings.TrimPrefix(rest, "/")
+		if len(rest) > 0 && (rest[0]|0x20 >= 'a' && rest[0]|0x20 <= 'z') {
+			// the tag must close before the next tag opens
+			end := strings.IndexByte(rest, '>')
+			next := strings.IndexByte(rest, '<')
+			if end > 0 && (next < 0 || end < next) {
+				return true
+			}
+		}
+		next := strings.IndexByte(text[i+1:], '<')
+		if next < 0 {
+			break
+		}
+		i += next + 1
+	}
+
+	return false
+}
+
+//
+// tagSet : The lowercased tags as a set.
+//
+func tagSet(tags []string) map[string]int {
+	result := map[string]int{}
+	for _, tag := range tags {
+		result[strings.ToLower(strings.TrimSpace(tag))] = 1
+	}
+
+	return result
+}
+
+//
+// parseText : The text of a parsed text node, trimmed unless the enclosing
+// element preserves whitespace.
+//
+func parseText(parent *DOMNode, text string) string {
+	if parent != nil && parent.preserveSpace {
+		return text
+	}
+
+	return strings.TrimSpace(text)
+}
@@ -0,0 +1,100 @@
+// Copyright 2016, Marc Lavergne <mlavergn@gmail.com>. All rights reserved.
+// Use of this source code is governed by the MIT
+// license that can be found in the LICENSE file.
+
+package godom
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestParseOptions(t *testing.T) {
+	contents := "<html><head><script>var a = 1;</script></head><body><!-- note --><div id=\"a\">one<svg><g><svg></svg></g></svg>two</div><p>&lt;b&gt;bold&lt;/b&gt;</p></body></html>"
+	opts := ParseOptions{SkipTags: []string{"svg"}, EscapedSkipTags: []string{"p"}, DiscardScriptText: true, IndexComments: true}
+
+	for _, stream := range []bool{false, true} {
+		d := NewDOM()
+		d.SetParseOptions(opts)
+		if stream {
+			d.SetContentsFromReader(strings.NewReader(contents))
+		} else {
+			d.SetContents(contents)
+		}
+
+		if len(d.Find("svg", nil)) != 0 || len(d.Find("g", nil)) != 0 {
+			d.Dump()
+			t.Errorf("failed to skip tags [stream %t]", stream)
+		}
+		div := d.Find("div", nil)
+		if len(div) != 1 || div[0].Text() != "one two" {
+			d.Dump()
+			t.Errorf("failed to retain text around skipped tags [stream %t]", stream)
+		}
+		script := d.Find("script", nil)
+		if len(script) != 1 || len(script[0].Text()) != 0 {
+			t.Errorf("failed to discard script text [stream %t]", stream)
+		}
+		if len(d.Find("comment", nil)) != 1 {
+			t.Errorf("failed to index comment [stream %t]", stream)
+		}
+		if len(d.Find("b", nil)) != 0 {
+			t.Errorf("failed to skip escaped HTML [stream %t]", stream)
+		}
+	}
+
+	// the options of one DOM do not leak into another
+	d := NewDOM()
+	d.SetContents(contents)
+	if len(d.Find("svg", nil)) != 2 || len(d.Find("comment", nil)) != 1 || len(d.Find("b", nil)) != 1 {
+		d.Dump()
+		t.Errorf("failed to parse with the defaults")
+	}
+}
+
+func TestEscapedHTML(t *testing.T) {
+	contents := "<html><body><p>x &lt;y</p><p>&lt;b&gt;bold&lt;/b&gt;</p></body></html>"
+	tests := []struct {
+		mode  EscapedHTML
+		text  string
+		bolds int
+	}{
+		{EscapedHTMLAny, "x", 1},
+		{EscapedHTMLTags, "x <y", 1},
+		{EscapedHTMLNever, "x <y", 0},
+	}
+
+	for _, test := range tests {
+		d := NewDOM()
+		d.SetParseOptions(ParseOptions{EscapedHTML: test.mode})
+		d.SetContents(contents)
+		p := d.Find("p", nil)
+		if len(p) != 2 || p[0].Text() != test.text || len(d.Find("b", nil)) != test.bolds {
+			d.Dump()
+			t.Errorf("unexpected parse with mode %d [%s]", test.mode, p[0].Text())
+		}
+	}
+
+	if containsMarkup("a <- b") || containsMarkup("x < y > z") || !containsMarkup("a <br/> b") || !containsMarkup("<!-- c") {
+		t.Errorf("unexpected markup detection")
+	}
+}
+
+func TestScriptText(t *testing.T) {
+	d := NewDOM()
+	d.SetParseOptions(ParseOptions{EscapedSkipTags: []string{}})
+	d.SetContents("<html><head><style>a > b { color: red }</style><script>if (a < b) { x = \"<b>\" }</script></head><body></body></html>")
+
+	if len(d.Find("b", nil)) != 0 {
+		d.Dump()
+		t.Fatalf("failed to keep script text as text")
+	}
+	script := d.Find("script", nil)
+	if len(script) != 1 || script[0].Text() != "if (a < b) { x = \"<b>\" }" {
+		t.Errorf("failed to retain script text")
+	}
+	style := d.Find("style", nil)
+	if len(style) != 1 || style[0].Text() != "a > b { color: red }" {
+		t.Errorf("failed to retain style text")
+	}
+}
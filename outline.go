@@ -0,0 +1,122 @@
+// Copyright 2016 Marc Lavergne <mlavergn@gmail.com>. All rights reserved.
+// Use of this source code is governed by
+// license that can be found in the LICENSE file.
+
+package godom
+
+import (
+	"strings"
+)
+
+// sectioningTags elements which open a section of the outline
+var sectioningTags = map[string]int{"article": 1, "aside": 1, "nav": 1, "section": 1}
+
+// headingLevels the level of each heading element
+var headingLevels = map[string]int{"h1": 1, "h2": 2, "h3": 3, "h4": 4, "h5": 5, "h6": 6}
+
+// Section def
+// A part of the document outline, opened by a heading or a sectioning
+// element. Level is that of the heading, 0 for the document and untitled
+// sectioning elements. Text is the reader text of the section outside of its
+// heading and subsections
+type Section struct {
+	Node     *DOMNode
+	Level    int
+	Heading  string
+	Text     string
+	Sections []*Section
+}
+
+// outlineWriter def
+// Builds the outline, the stack holds the open sections innermost last
+type outlineWriter struct {
+	stack []*Section
+	texts map[*Section][]string
+}
+
+//
+// Outline : The heading hierarchy of the body, or of the root node without a
+// body. Each h1 to h6 opens a section nested under the nearest open section
+// of a lower level, a sectioning element (article, aside, nav, section) opens
+// a section titled by its first heading which closes with the element. The
+// document section holds the text preceding the first heading.
+//
+func (id *DOM) Outline() *Section {
+	root := id.RootNode()
+	if bodies := id.Find("body", nil); len(bodies) > 0 {
+		root = bodies[0]
+	}
+
+	result := &Section{Node: root}
+	writer := &outlineWriter{stack: []*Section{result}, texts: map[*Section][]string{}}
+	if root != nil {
+		writer.node(root)
+	}
+	writer.finish(result)
+
+	return result
+}
+
+//
+// outlineWriter: Add the content of the node to the open sections.
+//
+func (id *outlineWriter) node(node *DOMNode) {
+	node.eachContent(func(text string) {
+		if text = strings.TrimSpace(text); len(text) > 0 {
+			top := id.stack[len(id.stack)-1]
+			id.texts[top] = append(id.texts[top], text)
+		}
+	}, func(child *DOMNode) {
+		switch {
+		case textSkipTags[child.Tag] == 1:
+		case headingLevels[child.Tag] > 0:
+			id.heading(child)
+		case sectioningTags[child.Tag] == 1:
+			section := &Section{Node: child}
+			top := id.stack[len(id.stack)-1]
+			top.Sections = append(top.Sections, section)
+			// the element bounds its headings
+			stack := id.stack
+			id.stack = []*Section{section}
+			id.node(child)
+			id.stack = stack
+		default:
+			id.node(child)
+		}
+	})
+}
+
+//
+// outlineWriter: Open the section of the heading, or title the innermost
+// sectioning element when it is the first heading within it.
+//
+func (id *outlineWriter) heading(node *DOMNode) {
+	level := headingLevels[node.Tag]
+	text := strings.TrimSpace(node.ReaderTextWithOptions(TextOptions{Separator: " "}))
+
+	if len(id.stack) == 1 {
+		if top := id.stack[0]; top.Level == 0 && sectioningTags[top.Node.Tag] == 1 && len(top.Sections) == 0 && len(top.Heading) == 0 {
+			top.Level = level
+			top.Heading = text
+			return
+		}
+	}
+
+	section := &Section{Node: node, Level: level, Heading: text}
+	for len(id.stack) > 1 && id.stack[len(id.stack)-1].Level >= level {
+		id.stack = id.stack[:len(id.stack)-1]
+	}
+	top := id.stack[len(id.stack)-1]
+	top.Sections = append(top.Sections, section)
+	id.stack = append(id.stack, section)
+}
+
+//
+// outlineWriter: Join the text of the section and its subsections.
+//
+func (id *outlineWriter) finish(section *Section) {
+	section.Text = strings.Join(id.texts[section], " ")
+	for _, child := range section.Sections {
+		id.finish(child)
+	}
+}
@@ -0,0 +1,49 @@
+// Copyright 2016, Marc Lavergne <mlavergn@gmail.com>. All rights reserved.
+// Use of this source code is governed by the MIT
+// license that can be found in the LICENSE file.
+
+package godom
+
+import (
+	"testing"
+)
+
+func TestOutline(t *testing.T) {
+	d := NewDOM()
+	d.SetContents(`<html><head><title>T</title></head><body><p>Intro</p>
+		<h1>Guide</h1><p>Overview</p>
+		<h2>Install</h2><div><p>Run it</p><h3>Linux</h3><p>apt</p></div>
+		<h2>Use <em>it</em></h2><p>Call</p>
+		<section><h2>Notes</h2><p>Extra</p><h3>More</h3></section>
+		<script>var x;</script><p>After</p>
+		<h1>Appendix</h1></body></html>`)
+
+	outline := d.Outline()
+	if outline.Node.Tag != "body" || outline.Text != "Intro" || len(outline.Sections) != 2 {
+		t.Fatalf("unexpected document section %q %d", outline.Text, len(outline.Sections))
+	}
+	guide := outline.Sections[0]
+	if guide.Heading != "Guide" || guide.Level != 1 || guide.Text != "Overview" || len(guide.Sections) != 2 {
+		t.Fatalf("unexpected guide %+v", guide)
+	}
+	install := guide.Sections[0]
+	if install.Heading != "Install" || install.Text != "Run it" || len(install.Sections) != 1 || install.Sections[0].Text != "apt" {
+		t.Errorf("unexpected install %+v", install)
+	}
+	use := guide.Sections[1]
+	if use.Heading != "Use it" || use.Text != "Call After" || len(use.Sections) != 1 {
+		t.Fatalf("unexpected use %+v", use)
+	}
+	notes := use.Sections[0]
+	if notes.Node.Tag != "section" || notes.Heading != "Notes" || notes.Level != 2 || notes.Text != "Extra" || len(notes.Sections) != 1 || notes.Sections[0].Heading != "More" {
+		t.Errorf("unexpected notes %+v", notes)
+	}
+	if appendix := outline.Sections[1]; appendix.Heading != "Appendix" || len(appendix.Sections) != 0 {
+		t.Errorf("unexpected appendix %+v", appendix)
+	}
+
+	empty := NewDOM()
+	if outline := empty.Outline(); outline.Node != nil || len(outline.Sections) != 0 {
+		t.Error("unexpected outline of an empty DOM")
+	}
+}
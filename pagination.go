@@ -0,0 +1,209 @@
+// Copyright 2016 Marc Lavergne <mlavergn@gmail.com>. All rights reserved.
+// Use of this source code is governed by
+// license that can be found in the LICENSE file.
+
+package godom
+
+import (
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// paginationNextRels the rel tokens of a next page link
+var paginationNextRels = map[string]int{"next": 1}
+
+// paginationPrevRels the rel tokens of a previous page link
+var paginationPrevRels = map[string]int{"prev": 1, "previous": 1}
+
+// paginationNextTexts link texts of a next page link
+var paginationNextTexts = map[string]int{
+	"next": 1, "next page": 1, "next »": 1, "next ›": 1, "next >": 1, "›": 1, "»": 1, "→": 1, ">": 1, ">>": 1,
+}
+
+// paginationPrevTexts link texts of a previous page link
+var paginationPrevTexts = map[string]int{
+	"prev": 1, "previous": 1, "previous page": 1, "« previous": 1, "‹ previous": 1, "< previous": 1, "« prev": 1,
+	"‹ prev": 1, "‹": 1, "«": 1, "←": 1, "<": 1, "<<": 1,
+}
+
+// paginationWords splits class and id values into words
+var paginationWords = regexp.MustCompile(`[^a-z]+`)
+
+// paginationNumbers finds the numbers of a URL
+var paginationNumbers = regexp.MustCompile(`[0-9]+`)
+
+// Pagination def
+// The next and previous page links of a document, nil when not found. The
+// nodes are the a or link elements the URLs were taken from. Pages are the
+// numbered page links in document order
+type Pagination struct {
+	Next     *url.URL
+	NextNode *DOMNode
+	Prev     *url.URL
+	PrevNode *DOMNode
+	Pages    []*Link
+}
+
+//
+// Pagination : The next and previous page links of the document, resolved
+// against the base. Links are taken, in order of preference, from rel="next"
+// and rel="prev" link and a elements, from link text such as "Next" or "›"
+// or an aria-label, class, or id naming next or previous, from the numbered
+// page links around the current page, and from links whose URL differs from
+// the document URL only by a page number one more or one less.
+//
+func (id *DOM) Pagination() *Pagination {
+	result := &Pagination{}
+	base := id.BaseURL()
+	links := id.Links(nil)
+
+	set := func(next bool, node *DOMNode, href string) {
+		if next && result.Next == nil {
+			result.Next, result.NextNode = resolveURL(base, href), node
+		} else if !next && result.Prev == nil {
+			result.Prev, result.PrevNode = resolveURL(base, href), node
+		}
+	}
+
+	// rel links
+	for _, node := range id.Find("link", nil) {
+		rel := strings.Fields(strings.ToLower(node.Attr("rel")))
+		if href := strings.TrimSpace(node.Attr("href")); len(href) > 0 {
+			if containsRel(rel, paginationNextRels) {
+				set(true, node, href)
+			} else if containsRel(rel, paginationPrevRels) {
+				set(false, node, href)
+			}
+		}
+	}
+	for _, link := range links {
+		if !paginationLink(link) {
+			continue
+		}
+		if containsRel(link.Rel, paginationNextRels) {
+			set(true, link.Node, link.Href)
+		} else if containsRel(link.Rel, paginationPrevRels) {
+			set(false, link.Node, link.Href)
+		}
+	}
+
+	// text, then labels and classes
+	for _, labels := range []bool{false, true} {
+		for _, link := range links {
+			if !paginationLink(link) {
+				continue
+			}
+			if next, ok := paginationDirection(link, labels); ok {
+				set(next, link.Node, link.Href)
+			}
+		}
+	}
+
+	// numbered pages
+	current := 0
+	for _, link := range links {
+		number, err := strconv.Atoi(strings.TrimSpace(link.Text))
+		if err != nil || number < 1 || !paginationLink(link) {
+			continue
+		}
+		result.Pages = append(result.Pages, link)
+		if link.Node.Attr("aria-current") == "page" || link.Node.HasClass("active") || link.Node.HasClass("current") {
+			current = number
+		}
+	}
+	if current > 0 {
+		for _, link := range result.Pages {
+			number, _ := strconv.Atoi(strings.TrimSpace(link.Text))
+			if number == current+1 {
+				set(true, link.Node, link.Href)
+			} else if number == current-1 {
+				set(false, link.Node, link.Href)
+			}
+		}
+	}
+
+	// page numbers of the document URL
+	if base != nil && (result.Next == nil || result.Prev == nil) {
+		for _, link := range links {
+			if link.URL == nil || !paginationLink(link) {
+				continue
+			}
+			if step := paginationStep(base, link.URL); step == 1 {
+				set(true, link.Node, link.Href)
+			} else if step == -1 {
+				set(false, link.Node, link.Href)
+			}
+		}
+	}
+
+	return result
+}
+
+//
+// paginationLink : Can the link lead to another page?
+//
+func paginationLink(link *Link) bool {
+	href := strings.ToLower(link.Href)
+	return len(href) > 0 && !strings.HasPrefix(href, "#") && !strings.HasPrefix(href, "javascript:")
+}
+
+//
+// paginationDirection : Does the text, or with labels the aria-label, class,
+// or id, of the link name the next or previous page?
+//
+func paginationDirection(link *Link, labels bool) (next bool, ok bool) {
+	if !labels {
+		text := strings.ToLower(strings.Join(strings.Fields(link.Text), " "))
+		if paginationNextTexts[text] == 1 {
+			return true, true
+		}
+		return false, paginationPrevTexts[text] == 1
+	}
+
+	label := strings.ToLower(strings.Join([]string{link.Node.Attr("aria-label"), link.Node.Attr("class"), link.Node.Attr("id")}, " "))
+	for _, word := range paginationWords.Split(label, -1) {
+		switch word {
+		case "next":
+			return true, true
+		case "prev", "previous":
+			return false, true
+		}
+	}
+
+	return false, false
+}
+
+//
+// paginationStep : The difference of the single number differing between
+// the URLs, 0 when they differ otherwise.
+//
+func paginationStep(current *url.URL, link *url.URL) int {
+	if current.Host != link.Host {
+		return 0
+	}
+	a, b := current.RequestURI(), link.RequestURI()
+	if paginationNumbers.ReplaceAllString(a, "0") != paginationNumbers.ReplaceAllString(b, "0") {
+		return 0
+	}
+
+	numbersA, numbersB := paginationNumbers.FindAllString(a, -1), paginationNumbers.FindAllString(b, -1)
+	step := 0
+	for i := range numbersA {
+		if numbersA[i] == numbersB[i] {
+			continue
+		}
+		if step != 0 {
+			return 0
+		}
+		x, errA := strconv.Atoi(numbersA[i])
+		y, errB := strconv.Atoi(numbersB[i])
+		if errA != nil || errB != nil {
+			return 0
+		}
+		step = y - x
+	}
+
+	return step
+}
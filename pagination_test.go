@@ -0,0 +1,47 @@
+// Copyright 2016, Marc Lavergne <mlavergn@gmail.com>. All rights reserved.
+// Use of this source code is governed by the MIT
+// license that can be found in the LICENSE file.
+
+package godom
+
+import (
+	"net/url"
+	"testing"
+)
+
+func TestPagination(t *testing.T) {
+	base, _ := url.Parse("https://example.com/list?page=3")
+
+	d := NewDOM()
+	d.SetContents(`<html><head><link rel="next" href="/list?page=4"></head><body><a href="?page=2">‹</a></body></html>`)
+	d.SetBaseURL(base)
+	pages := d.Pagination()
+	if pages.Next == nil || pages.Next.String() != "https://example.com/list?page=4" || pages.NextNode.Tag != "link" {
+		t.Errorf("unexpected rel next %v", pages.Next)
+	}
+	if pages.Prev == nil || pages.Prev.String() != "https://example.com/list?page=2" || pages.PrevNode.Tag != "a" {
+		t.Errorf("unexpected text prev %v", pages.Prev)
+	}
+
+	d = NewDOM()
+	d.SetContents(`<html><body><ul class="pager"><li><a href="/p/1">1</a></li><li><a class="active" href="/p/2">2</a></li><li><a href="/p/3">3</a></li><li><a class="pagination-next" href="/p/3"><span>Go</span></a></li></ul></body></html>`)
+	d.SetBaseURL(base)
+	pages = d.Pagination()
+	if len(pages.Pages) != 3 || pages.Prev == nil || pages.Prev.Path != "/p/1" || pages.Next == nil || pages.Next.Path != "/p/3" || pages.NextNode.Text() != "" {
+		t.Errorf("unexpected numbered pages %v %v", pages.Prev, pages.Next)
+	}
+
+	d = NewDOM()
+	d.SetContents(`<html><body><a href="/list?page=4&sort=a">x</a><a href="/list?page=4">more</a><a href="/list?page=2">back</a><a href="#top">next</a></body></html>`)
+	d.SetBaseURL(base)
+	pages = d.Pagination()
+	if pages.Next == nil || pages.Next.String() != "https://example.com/list?page=4" || pages.Prev == nil || pages.Prev.RawQuery != "page=2" {
+		t.Errorf("unexpected url pattern pages %v %v", pages.Next, pages.Prev)
+	}
+
+	d = NewDOM()
+	d.SetContents(`<html><body><a href="/about">About</a></body></html>`)
+	if pages := d.Pagination(); pages.Next != nil || pages.Prev != nil || len(pages.Pages) != 0 {
+		t.Error("unexpected pagination")
+	}
+}
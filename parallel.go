@@ -0,0 +1,104 @@
+// Copyright 2016 Marc Lavergne <mlavergn@gmail.com>. All rights reserved.
+// Use of this source code is governed by
+// license that can be found in the LICENSE file.
+
+package godom
+
+import (
+	"runtime"
+	"sort"
+	"sync"
+)
+
+// parallelMinShard candidates below which a shard isn't worth a goroutine
+const parallelMinShard = 1024
+
+//
+// ParallelFind : Find the Nodes of any of the tags with the specified
+// attributes as ChildParallelFind does from the root node.
+//
+func (id *DOM) ParallelFind(tags []string, attributes DOMNodeAttributes, workers int) []*DOMNode {
+	return id.ChildParallelFind(id.RootNode(), tags, attributes, workers)
+}
+
+//
+// ChildParallelFind : Find the child Nodes of any of the tags with the
+// specified attributes, sharding the candidates across at most workers
+// goroutines, GOMAXPROCS when 0 or less. The matches are merged in document
+// order. Small searches are scanned by the calling goroutine. The DOM must
+// not be modified during the search.
+//
+func (id *DOM) ChildParallelFind(parent *DOMNode, tags []string, attributes DOMNodeAttributes, workers int) (result []*DOMNode) {
+	if workers <= 0 {
+		workers = runtime.GOMAXPROCS(0)
+	}
+	// build the lazy state up front so the workers only read
+	id.RootNode()
+
+	var candidates []*DOMNode
+	within := true
+	seen := map[string]int{}
+	for _, tag := range tags {
+		if seen[tag] == 1 {
+			continue
+		}
+		seen[tag] = 1
+		tagNodes, tagWithin := id.childTagNodes(parent, tag)
+		candidates = append(candidates, tagNodes...)
+		within = within && tagWithin
+	}
+
+	match := func(nodes []*DOMNode) (matches []*DOMNode) {
+		for _, node := range nodes {
+			if (seen["*"] == 0 || pseudoTags[node.Tag] == 0) && node.matchAttributes(attributes) {
+				if within || id.IsDescendantNode(parent, node) {
+					matches = append(matches, node)
+				}
+			}
+		}
+		return matches
+	}
+
+	if shards := (len(candidates) + parallelMinShard - 1) / parallelMinShard; shards < workers {
+		workers = shards
+	}
+	if workers <= 1 {
+		result = match(candidates)
+	} else {
+		shardMatches := make([][]*DOMNode, workers)
+		size := (len(candidates) + workers - 1) / workers
+		wg := sync.WaitGroup{}
+		for i := 0; i < workers; i++ {
+			start, end := i*size, (i+1)*size
+			if end > len(candidates) {
+				end = len(candidates)
+			}
+			if start > end {
+				start = end
+			}
+			wg.Add(1)
+			go func(i int, shard []*DOMNode) {
+				defer wg.Done()
+				shardMatches[i] = match(shard)
+			}(i, candidates[start:end])
+		}
+		wg.Wait()
+		for _, matches := range shardMatches {
+			result = append(result, matches...)
+		}
+	}
+
+	if len(seen) > 1 {
+		// merge the tags, * repeats the nodes of the other tags
+		sort.Slice(result, func(i, j int) bool { return result[i].Index < result[j].Index })
+		merged := result[:0]
+		for i, node := range result {
+			if i == 0 || node != result[i-1] {
+				merged = append(merged, node)
+			}
+		}
+		result = merged
+	}
+
+	return result
+}
@@ -0,0 +1,58 @@
+// Copyright 2016, Marc Lavergne <mlavergn@gmail.com>. All rights reserved.
+// Use of this source code is governed by the MIT
+// license that can be found in the LICENSE file.
+
+package godom
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestParallelFind(t *testing.T) {
+	sb := strings.Builder{}
+	sb.WriteString("<html><body>")
+	for i := 0; i < 3000; i++ {
+		fmt.Fprintf(&sb, `<div class="item k%d"><span class="k%d">%d</span></div>`, i%3, i%3, i)
+	}
+	sb.WriteString("</body></html>")
+
+	d := NewDOM()
+	d.SetContents(sb.String())
+
+	attrs := DOMNodeAttributes{"class": "k1"}
+	expected := d.Find("div", attrs)
+	found := d.ParallelFind([]string{"div"}, attrs, 4)
+	if len(found) != 1000 || len(found) != len(expected) {
+		t.Fatalf("expected %d found %d", len(expected), len(found))
+	}
+	for i := range found {
+		if found[i] != expected[i] {
+			t.Fatalf("mismatch at %d", i)
+		}
+	}
+
+	found = d.ParallelFind([]string{"span", "div", "span"}, attrs, 0)
+	if len(found) != 2000 {
+		t.Fatalf("expected 2000 found %d", len(found))
+	}
+	for i := 1; i < len(found); i++ {
+		if found[i-1].Index >= found[i].Index {
+			t.Fatalf("out of document order at %d", i)
+		}
+	}
+	if found := d.ParallelFind([]string{"*", "span"}, attrs, 3); len(found) != 2000 {
+		t.Errorf("expected 2000 merged found %d", len(found))
+	}
+
+	div := d.Find("div", nil)[1]
+	if found := d.ChildParallelFind(div, []string{"span"}, nil, 8); len(found) != 1 || found[0].Parent != div {
+		t.Errorf("unexpected child matches %v", found)
+	}
+
+	d.Freeze()
+	if found := d.ParallelFind([]string{"span"}, DOMNodeAttributes{"class": "k2"}, 8); len(found) != 1000 {
+		t.Errorf("expected 1000 frozen matches found %d", len(found))
+	}
+}
@@ -0,0 +1,268 @@
+// Copyright 2016 Marc Lavergne <mlavergn@gmail.com>. All rights reserved.
+// Use of this source code is governed by
+// license that can be found in the LICENSE file.
+
+package godom
+
+import (
+	"strconv"
+	"strings"
+)
+
+//
+// CSSPath : A selector matching only the node, the > separated steps from the
+// nearest ancestor with an id unique in the document, or from the top, with
+// :nth-of-type(n) where there are several siblings of the type, the top level
+// elements being siblings. Empty for pseudo nodes.
+//
+func (id *DOMNode) CSSPath() string {
+	if pseudoTags[id.Tag] == 1 {
+		return ""
+	}
+
+	var steps []string
+	var ids map[string]int
+	for node := id; node != nil; node = node.Parent {
+		if elementID := node.uniqueID(&ids); len(elementID) > 0 && isSelectorIdent(elementID) {
+			steps = append(steps, "#"+elementID)
+			break
+		}
+		step := node.Tag
+		if node.typeCount() > 1 {
+			step += ":nth-of-type(" + strconv.Itoa(nthOfType(node)) + ")"
+		}
+		steps = append(steps, step)
+	}
+
+	return joinReversed(steps, " > ")
+}
+
+//
+// XPathString : An XPath expression matching only the node, the / separated
+// steps from the nearest ancestor with an id unique in the document, or from
+// the top, with [n] where there are several siblings of the type, the top
+// level elements being siblings. Empty for pseudo nodes.
+//
+func (id *DOMNode) XPathString() string {
+	if pseudoTags[id.Tag] == 1 {
+		return ""
+	}
+
+	var steps []string
+	var ids map[string]int
+	for node := id; node != nil; node = node.Parent {
+		if elementID := node.uniqueID(&ids); len(elementID) > 0 && !strings.ContainsAny(elementID, `"'`) {
+			steps = append(steps, `//*[@id="`+elementID+`"]`)
+			break
+		}
+		step := node.Tag
+		if node.typeCount() > 1 {
+			step += "[" + strconv.Itoa(nthOfType(node)) + "]"
+		}
+		steps = append(steps, "/"+step)
+	}
+
+	return joinReversed(steps, "")
+}
+
+//
+// IndexPath : The / separated child positions from 0 leading from the top to
+// the node, empty for the top. Empty for pseudo nodes.
+//
+func (id *DOMNode) IndexPath() string {
+	if pseudoTags[id.Tag] == 1 {
+		return ""
+	}
+
+	var steps []string
+	for node := id; node.Parent != nil; node = node.Parent {
+		steps = append(steps, strconv.Itoa(node.Parent.childPosition(node)))
+	}
+
+	return joinReversed(steps, "/")
+}
+
+//
+// NodeAt : The node located by a path of CSSPath, XPathString, or IndexPath,
+// so paths saved from one document resolve against another. A CSS path
+// yields the first match in document order, XPath steps without [n] the
+// first child of the type, and index paths count from the root node. Nil
+// when the path is invalid or locates no node.
+//
+func (id *DOM) NodeAt(path string) *DOMNode {
+	path = strings.TrimSpace(path)
+	switch {
+	case isIndexPath(path):
+		var positions []int
+		for _, step := range strings.Split(strings.Trim(path, "/"), "/") {
+			if len(step) > 0 {
+				position, _ := strconv.Atoi(step)
+				positions = append(positions, position)
+			}
+		}
+		return id.pathNode(positions)
+	case strings.HasPrefix(path, "/"):
+		return id.xpathNode(path)
+	}
+
+	selector, err := ParseSelector(path)
+	if err != nil {
+		return nil
+	}
+	for _, node := range id.document {
+		if selector.Match(node) {
+			return node
+		}
+	}
+
+	return nil
+}
+
+//
+// isIndexPath : Is the path made of / separated positions only?
+//
+func isIndexPath(path string) bool {
+	for i := 0; i < len(path); i++ {
+		if path[i] != '/' && (path[i] < '0' || path[i] > '9') {
+			return false
+		}
+	}
+
+	return true
+}
+
+//
+// DOM: The node located by an XPath of the form XPathString writes.
+//
+func (id *DOM) xpathNode(path string) *DOMNode {
+	var node *DOMNode
+	if strings.HasPrefix(path, "//*[@id=") {
+		end := strings.Index(path, "]")
+		if end < 0 {
+			return nil
+		}
+		elementID := strings.Trim(path[len("//*[@id="):end], `"'`)
+		if node = id.ByID(elementID); node == nil {
+			return nil
+		}
+		path = path[end+1:]
+	}
+
+	for _, step := range strings.Split(path, "/")[1:] {
+		tag, nth := step, 1
+		if open := strings.IndexByte(step, '['); open >= 0 && strings.HasSuffix(step, "]") {
+			n, err := strconv.Atoi(step[open+1 : len(step)-1])
+			if err != nil {
+				return nil
+			}
+			tag, nth = step[:open], n
+		}
+		if node == nil {
+			// the first step names a top level element
+			for _, top := range id.topElements() {
+				if top.Tag == tag {
+					if nth--; nth == 0 {
+						node = top
+						break
+					}
+				}
+			}
+			if node == nil {
+				return nil
+			}
+			continue
+		}
+		if node = node.NthOfType(tag, nth); node == nil {
+			return nil
+		}
+	}
+
+	return node
+}
+
+//
+// DOMNode: The id of the node when no other element of the document holds it,
+// empty otherwise. The element count of each id is counted into ids on first
+// use, so the document is scanned once for the steps of a path.
+//
+func (id *DOMNode) uniqueID(ids *map[string]int) string {
+	elementID := id.Attr("id")
+	if len(elementID) == 0 || id.dom == nil || id.dom.ByID(elementID) != id {
+		return ""
+	}
+	if *ids == nil {
+		*ids = map[string]int{}
+		for _, node := range id.dom.document {
+			if nodeID := node.Attr("id"); len(nodeID) > 0 && pseudoTags[node.Tag] == 0 {
+				(*ids)[nodeID]++
+			}
+		}
+	}
+	if (*ids)[elementID] > 1 {
+		return ""
+	}
+
+	return elementID
+}
+
+//
+// DOMNode: The number of sibling elements of the same type, including the
+// node.
+//
+func (id *DOMNode) typeCount() int {
+	count := 0
+	for _, sibling := range id.siblings() {
+		if sibling.Tag == id.Tag {
+			count++
+		}
+	}
+
+	return count
+}
+
+//
+// DOMNode: The child elements of the parent, or the top level elements of
+// the document for a node without a parent, eg. of a fragment.
+//
+func (id *DOMNode) siblings() []*DOMNode {
+	if id.Parent != nil {
+		return id.Parent.Children
+	}
+	if id.dom == nil {
+		return []*DOMNode{id}
+	}
+
+	return id.dom.topElements()
+}
+
+//
+// DOM: The elements without a parent in document order.
+//
+func (id *DOM) topElements() (result []*DOMNode) {
+	for _, node := range id.document {
+		if node.Parent == nil && pseudoTags[node.Tag] == 0 {
+			result = append(result, node)
+		}
+	}
+
+	return result
+}
+
+//
+// isSelectorIdent : Can the name be written as a selector identifier?
+//
+func isSelectorIdent(name string) bool {
+	ident, end := scanSelectorIdent(name, 0)
+	return end == len(name) && len(ident) > 0 && !(name[0] >= '0' && name[0] <= '9') && !strings.HasPrefix(name, "-")
+}
+
+//
+// joinReversed : The steps joined with sep in reverse order.
+//
+func joinReversed(steps []string, sep string) string {
+	for i, j := 0, len(steps)-1; i < j; i, j = i+1, j-1 {
+		steps[i], steps[j] = steps[j], steps[i]
+	}
+
+	return strings.Join(steps, sep)
+}
@@ -0,0 +1,100 @@
+// Copyright 2016, Marc Lavergne <mlavergn@gmail.com>. All rights reserved.
+// Use of this source code is governed by the MIT
+// license that can be found in the LICENSE file.
+
+package godom
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestCSSPath(t *testing.T) {
+	d := NewDOM()
+	d.SetContents(`<html><body><div id="main"><p>a</p><p>b<span>x</span></p></div><div><ul><li>1</li><li id="dup">2</li><li id="dup">3</li></ul></div><section id="9x"><b>y</b></section></body></html>`)
+
+	span := d.Find("span", nil)[0]
+	if path := span.CSSPath(); path != "#main > p:nth-of-type(2) > span" {
+		t.Errorf("unexpected path %s", path)
+	}
+	if path := span.XPathString(); path != `//*[@id="main"]/p[2]/span` {
+		t.Errorf("unexpected xpath %s", path)
+	}
+
+	li := d.Find("li", nil)[2]
+	if path := li.CSSPath(); path != "html > body > div:nth-of-type(2) > ul > li:nth-of-type(3)" {
+		t.Errorf("unexpected path %s", path)
+	}
+	if path := li.XPathString(); path != "/html/body/div[2]/ul/li[3]" {
+		t.Errorf("unexpected xpath %s", path)
+	}
+	if path := d.Find("b", nil)[0].CSSPath(); path != "html > body > section > b" {
+		t.Errorf("unexpected path for an invalid identifier %s", path)
+	}
+
+	// each path selects only its node
+	nodes := d.Find("*", nil)
+	for _, node := range nodes {
+		selector := MustParseSelector(node.CSSPath())
+		for _, other := range nodes {
+			if selector.Match(other) != (other == node) {
+				t.Errorf("path %s matched %s %d", node.CSSPath(), other.Tag, other.Index)
+			}
+		}
+	}
+
+	d.SetContents("<!-- c -->")
+	if comment := d.Comments()[0]; comment.CSSPath() != "" || comment.XPathString() != "" {
+		t.Error("expected no path for a comment")
+	}
+	if _, err := ParseSelector("p:first-child"); err == nil {
+		t.Error("expected an unsupported pseudo-class error")
+	}
+	if sel := MustParseSelector("li:nth-of-type(2)"); !sel.Match(d.Find("li", nil)[1]) || sel.Match(li) {
+		t.Error("unexpected nth-of-type match")
+	}
+}
+
+func TestNodeAt(t *testing.T) {
+	contents := `<html><body><div id="main"><p>a</p><p>b<span>x</span></p></div><div><ul><li>1</li><li>2</li></ul></div></body></html>`
+	d := NewDOM()
+	d.SetContents(contents)
+	other := NewDOM()
+	other.SetContents(contents)
+
+	for _, node := range d.Find("*", nil) {
+		for _, path := range []string{node.CSSPath(), node.XPathString(), node.IndexPath()} {
+			if found := other.NodeAt(path); found == nil || found.Index != node.Index {
+				t.Errorf("failed to resolve %q", path)
+			}
+		}
+	}
+
+	li := d.Find("li", nil)[1]
+	if path := li.IndexPath(); path != "1/1/0/1" {
+		t.Errorf("unexpected index path %s", path)
+	}
+	if d.NodeAt("/1/1/0/1") != li || d.NodeAt("") != d.RootNode() || d.NodeAt("/html/body/div[2]/ul/li[2]") != li {
+		t.Error("failed to resolve the item")
+	}
+	for _, path := range []string{"1/5", "/html/body/div[3]", "/body", "//*[@id=\"none\"]/p", "p[", "/html/body/div[x]"} {
+		if node := d.NodeAt(path); node != nil {
+			t.Errorf("unexpected node for %q", path)
+		}
+	}
+
+	// the top level elements of a fragment are siblings
+	fragment := NewDOM()
+	fragment.SetContentsFromReader(strings.NewReader("<p><b>x</b></p><p><b>y</b></p>"))
+	b := fragment.Find("b", nil)[1]
+	if b.CSSPath() != "p:nth-of-type(2) > b" || b.XPathString() != "/p[2]/b" {
+		t.Errorf("unexpected top level paths %s %s", b.CSSPath(), b.XPathString())
+	}
+	for _, node := range fragment.Find("*", nil) {
+		for _, path := range []string{node.CSSPath(), node.XPathString()} {
+			if found := fragment.NodeAt(path); found != node {
+				t.Errorf("failed to resolve %q", path)
+			}
+		}
+	}
+}
@@ -0,0 +1,157 @@
+// Copyright 2016 Marc Lavergne <mlavergn@gmail.com>. All rights reserved.
+// Use of this source code is governed by
+// license that can be found in the LICENSE file.
+
+package godom
+
+import (
+	"strings"
+)
+
+// Query def
+// A compiled selector run against any number of DOMs, it is never modified
+// once compiled so goroutines may share it
+type Query struct {
+	selector *Selector
+	// tags are the candidate buckets, nil when any element may match
+	tags []string
+}
+
+//
+// CompileQuery : Compile the CSS selector into a Query.
+//
+func CompileQuery(selector string) (*Query, error) {
+	compiled, err := ParseSelector(selector)
+	if err != nil {
+		return nil, err
+	}
+
+	return newQuery(compiled), nil
+}
+
+//
+// MustCompileQuery : Compile the CSS selector into a Query, panicking if it
+// is invalid.
+//
+func MustCompileQuery(selector string) *Query {
+	result, err := CompileQuery(selector)
+	if err != nil {
+		panic(err)
+	}
+
+	return result
+}
+
+//
+// NewQuery : A Query for the elements of type tag with the specified
+// attributes, matching as Find does, so an HTML tag ignores case and an empty
+// value also matches elements without the attribute. The tag "*" matches any
+// element.
+//
+func NewQuery(tag string, attributes DOMNodeAttributes) *Query {
+	step := selectorStep{combinator: ' '}
+	if tag != "*" {
+		step.tag = tag
+	}
+	for key, val := range attributes {
+		classes := strings.Fields(val)
+		if key != "class" || len(classes) == 0 {
+			step.attrs = append(step.attrs, selectorAttr{key: key, op: "=", val: val, missing: true})
+			continue
+		}
+		for _, class := range classes {
+			step.attrs = append(step.attrs, selectorAttr{key: key, op: "~=", val: class})
+		}
+	}
+
+	return newQuery(&Selector{groups: [][]selectorStep{{step}}})
+}
+
+//
+// newQuery : The Query of the selector, scanning only the tag buckets of the
+// rightmost steps when each names a tag.
+//
+func newQuery(selector *Selector) *Query {
+	result := &Query{selector: selector}
+	seen := map[string]int{}
+	for _, steps := range selector.groups {
+		tag := steps[len(steps)-1].tag
+		if len(tag) == 0 {
+			result.tags = nil
+			return result
+		}
+		if seen[tag] == 0 {
+			seen[tag] = 1
+			result.tags = append(result.tags, tag)
+		}
+	}
+
+	return result
+}
+
+//
+// String : The selector source of the query.
+//
+func (id *Query) String() string {
+	return id.selector.String()
+}
+
+//
+// Run : The elements of the root node matching the query in document order.
+//
+func (id *Query) Run(dom *DOM) []*DOMNode {
+	return id.ChildRun(dom, dom.RootNode(), 0)
+}
+
+//
+// RunFirst : The first element of the root node matching the query, nil if
+// there is none.
+//
+func (id *Query) RunFirst(dom *DOM) *DOMNode {
+	if nodes := id.ChildRun(dom, dom.RootNode(), 1); len(nodes) > 0 {
+		return nodes[0]
+	}
+
+	return nil
+}
+
+//
+// ChildRun : At most limit elements of parent matching the query in document
+// order, a limit of 0 or less finds every element. The parent may be
+// included, as ChildFind includes it.
+//
+func (id *Query) ChildRun(dom *DOM, parent *DOMNode, limit int) (result []*DOMNode) {
+	candidates, within := dom.childTagNodes(parent, "*")
+	if id.tags != nil {
+		candidates = nil
+		within = true
+		seen := map[string]int{}
+		for _, tag := range id.tags {
+			// HTML tags are indexed in lowercase
+			if !dom.xml {
+				tag = strings.ToLower(tag)
+			}
+			if seen[tag] == 1 {
+				continue
+			}
+			seen[tag] = 1
+			tagNodes, tagWithin := dom.childTagNodes(parent, tag)
+			candidates = append(candidates, tagNodes...)
+			within = within && tagWithin
+		}
+		if len(seen) > 1 {
+			sortNodes(candidates)
+		}
+	}
+
+	for _, node := range candidates {
+		if id.selector.Match(node) && (within || dom.IsDescendantNode(parent, node)) {
+			result = append(result, node)
+			if len(result) == limit {
+				break
+			}
+		}
+	}
+
+	return result
+}
@@ -0,0 +1,67 @@
+// Copyright 2016, Marc Lavergne <mlavergn@gmail.com>. All rights reserved.
+// Use of this source code is governed by the MIT
+// license that can be found in the LICENSE file.
+
+package godom
+
+import (
+	"testing"
+)
+
+func TestQuery(t *testing.T) {
+	q, err := CompileQuery("div.item > a[href], h1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if q.String() != "div.item > a[href], h1" {
+		t.Errorf("unexpected source %s", q.String())
+	}
+
+	pages := []string{
+		`<html><body><h1>One</h1><div class="item"><a href="/1">1</a><a>none</a></div></body></html>`,
+		`<html><body><div class="item x"><a href="/2">2</a></div><p><a href="/3">3</a></p><h1>Two</h1></body></html>`,
+	}
+	expected := [][]string{{"One", "1"}, {"2", "Two"}}
+	for i, page := range pages {
+		d := NewDOM()
+		d.SetContents(page)
+		nodes := q.Run(&d)
+		if len(nodes) != len(expected[i]) {
+			t.Fatalf("page %d expected %d matches found %d", i, len(expected[i]), len(nodes))
+		}
+		for j, node := range nodes {
+			if node.Text() != expected[i][j] {
+				t.Errorf("page %d expected %s found %s", i, expected[i][j], node.Text())
+			}
+		}
+		if first := q.RunFirst(&d); first != nodes[0] {
+			t.Errorf("unexpected first match %v", first)
+		}
+	}
+
+	d := NewDOM()
+	d.SetContents(`<html><body><div class="a b" id="x"><p class="b">1</p></div><div class="b"><p class="a b">2</p></div></body></html>`)
+	found := NewQuery("*", DOMNodeAttributes{"class": "b a"}).Run(&d)
+	if expected := d.Find("*", DOMNodeAttributes{"class": "b a"}); len(found) != 2 || found[0] != expected[0] || found[1] != expected[1] {
+		t.Errorf("unexpected matches %v", found)
+	}
+	div := d.ByID("x")
+	if found := NewQuery("p", nil).ChildRun(&d, div, 0); len(found) != 1 || found[0].Text() != "1" {
+		t.Errorf("unexpected child matches %v", found)
+	}
+	for _, attributes := range []DOMNodeAttributes{{"title": ""}, {"id": "x"}, {"class": ""}} {
+		found := NewQuery("div", attributes).Run(&d)
+		if expected := d.Find("div", attributes); len(found) != len(expected) {
+			t.Errorf("expected %d matches of %v, got %d", len(expected), attributes, len(found))
+		}
+	}
+	if found := NewQuery("DIV", nil).Run(&d); len(found) != len(d.Find("DIV", nil)) || len(found) != 2 {
+		t.Errorf("expected the HTML tag to ignore case, got %d", len(found))
+	}
+	if found := MustCompileQuery("P, p").Run(&d); len(found) != 2 {
+		t.Errorf("expected each element once, got %d", len(found))
+	}
+	if _, err := CompileQuery("p["); err == nil {
+		t.Error("expected an invalid selector error")
+	}
+}
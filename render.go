@@ -0,0 +1,475 @@
+// Copyright 2016 Marc Lavergne <mlavergn@gmail.com>. All rights reserved.
+// Use of this source code is governed by
+// license that can be found in the LICENSE file.
+
+package godom
+
+import (
+	"io"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// rawTextTags elements whose text is written without escaping
+var rawTextTags = map[string]int{
+	"script": 1, "style": 1, "xmp": 1, "iframe": 1, "noembed": 1, "noframes": 1, "noscript": 1, "plaintext": 1,
+}
+
+// renderPhrasingTags elements kept on the line of their parent by a
+// pretty-printing Render
+var renderPhrasingTags = map[string]int{
+	"a": 1, "abbr": 1, "b": 1, "bdi": 1, "bdo": 1, "br": 1, "cite": 1, "code": 1, "data": 1, "del": 1,
+	"dfn": 1, "em": 1, "i": 1, "img": 1, "ins": 1, "kbd": 1, "label": 1, "mark": 1, "q": 1, "s": 1,
+	"samp": 1, "small": 1, "span": 1, "strong": 1, "sub": 1, "sup": 1, "time": 1, "u": 1, "var": 1, "wbr": 1,
+}
+
+// renderDefaultAttrs attribute values equal to the default, dropped by Minify
+var renderDefaultAttrs = map[string]map[string]string{
+	"script": {"type": "text/javascript", "language": "javascript"},
+	"style":  {"type": "text/css", "media": "all"},
+	"link":   {"type": "text/css"},
+	"form":   {"method": "get"},
+	"input":  {"type": "text"},
+	"button": {"type": "submit"},
+	"area":   {"shape": "rect"},
+}
+
+// renderSpace whitespace runs collapsed by Minify
+var renderSpace = regexp.MustCompile(`\s+`)
+
+// AttrQuote type
+// The quoting of attribute values written by Render
+type AttrQuote int
+
+const (
+	// QuoteDouble quotes values with "
+	QuoteDouble AttrQuote = iota
+	// QuoteSingle quotes values with '
+	QuoteSingle
+	// QuoteMinimal leaves values unquoted where HTML allows it and writes
+	// empty values as bare attribute names
+	QuoteMinimal
+)
+
+// RenderOptions def
+// Controls the HTML written by Render, the zero value writes what OuterHTML does
+type RenderOptions struct {
+	// Indent is repeated once per depth at the start of each line, empty
+	// writes the HTML on a single line. Elements holding only text and
+	// phrasing content, such as <p> or <a>, stay on one line
+	Indent string
+	// Quote is the quoting style of attribute values
+	Quote AttrQuote
+	// SelfClose writes void elements as <br />
+	SelfClose bool
+	// Minify writes compact HTML: whitespace runs in text collapse to a single
+	// space outside of preformatted text, comments and attributes set to their
+	// default value are dropped, and values are quoted only where required.
+	// Indent, Quote, and SelfClose are ignored
+	Minify bool
+	// Escape is the escaping of text and attribute values
+	Escape TextEscape
+}
+
+//
+// OuterHTML : Serialize the node and its subtree to HTML.
+// Whitespace trimmed from text fragments during parse is not restored.
+//
+func (id *DOMNode) OuterHTML() string {
+	sb := strings.Builder{}
+	renderer := &renderer{w: &sb}
+	renderer.outer(id)
+	return sb.String()
+}
+
+//
+// InnerHTML : Serialize the children and text of the node to HTML.
+//
+func (id *DOMNode) InnerHTML() string {
+	sb := strings.Builder{}
+	renderer := &renderer{w: &sb}
+	renderer.inner(id)
+	return sb.String()
+}
+
+//
+// Render : Write the document, including its doctype and comments, as HTML
+// formatted by the options.
+//
+func (id *DOM) Render(w io.Writer, opts RenderOptions) error {
+	renderer := &renderer{w: w, options: opts}
+	for _, node := range id.document {
+		if node.Parent == nil && node.Tag != "document" {
+			renderer.node(node, 0)
+		}
+	}
+	renderer.end()
+
+	return renderer.err
+}
+
+//
+// Render : Write the node and its subtree as HTML formatted by the options.
+//
+func (id *DOMNode) Render(w io.Writer, opts RenderOptions) error {
+	renderer := &renderer{w: w, options: opts}
+	renderer.node(id, 0)
+	renderer.end()
+
+	return renderer.err
+}
+
+// renderer def
+// Writes nodes as HTML, keeping the first write error
+type renderer struct {
+	w       io.Writer
+	options RenderOptions
+	err     error
+	// lines is set once a line has been started
+	lines bool
+	// comments holds the comments of each node, nil until needed
+	comments map[*DOMNode][]*DOMNode
+}
+
+//
+// renderer: Write the string unless a write already failed.
+//
+func (id *renderer) write(s string) {
+	if id.err == nil {
+		_, id.err = io.WriteString(id.w, s)
+	}
+}
+
+//
+// renderer: Start an indented line when pretty printing.
+//
+func (id *renderer) line(depth int) {
+	if len(id.options.Indent) == 0 || id.options.Minify {
+		return
+	}
+	if id.lines {
+		id.write("\n")
+	}
+	id.write(strings.Repeat(id.options.Indent, depth))
+	id.lines = true
+}
+
+//
+// renderer: End the last line when pretty printing.
+//
+func (id *renderer) end() {
+	if id.lines {
+		id.write("\n")
+	}
+}
+
+//
+// renderer: Write the node at the depth, on its own lines when pretty
+// printing a node with block content.
+//
+func (id *renderer) node(node *DOMNode, depth int) {
+	id.line(depth)
+	if len(id.options.Indent) == 0 || id.options.Minify || pseudoTags[node.Tag] == 1 || node.renderInline() {
+		id.outer(node)
+		return
+	}
+
+	id.startTag(node)
+	id.eachContent(node, func(text string) {
+		if len(text) > 0 {
+			id.line(depth + 1)
+			id.text(node, text)
+		}
+	}, func(child *DOMNode) {
+		id.node(child, depth+1)
+	})
+	id.line(depth)
+	id.endTag(node)
+}
+
+//
+// renderer: Write the start tag, contents, and end tag of the node.
+//
+func (id *renderer) outer(node *DOMNode) {
+	switch node.Tag {
+	case "document":
+		id.inner(node)
+		return
+	case "comment":
+		if id.options.Minify {
+			return
+		}
+		id.write("<!--")
+		id.write(node.Text())
+		id.write("-->")
+		return
+	case "doctype":
+		id.doctype(node)
+		return
+	case "error":
+		return
+	}
+
+	id.startTag(node)
+	if node.void() {
+		return
+	}
+
+	id.inner(node)
+	id.endTag(node)
+}
+
+//
+// renderer: Write the doctype from its name and identifiers, so a legacy
+// declaration keeps the rendering mode of the document.
+//
+func (id *renderer) doctype(node *DOMNode) {
+	name := node.Text()
+	if len(name) == 0 {
+		name = "html"
+	}
+	id.write("<!DOCTYPE ")
+	id.write(name)
+	public, hasPublic := node.Attributes["public"]
+	system, hasSystem := node.Attributes["system"]
+	if hasPublic {
+		id.write(" PUBLIC ")
+		id.write(doctypeQuote(public))
+		if hasSystem {
+			id.write(" ")
+			id.write(doctypeQuote(system))
+		}
+	} else if hasSystem {
+		id.write(" SYSTEM ")
+		id.write(doctypeQuote(system))
+	}
+	id.write(">")
+}
+
+//
+// doctypeQuote : The doctype identifier quoted, single quotes for one holding
+// a double quote.
+//
+func doctypeQuote(s string) string {
+	if strings.IndexByte(s, '"') >= 0 {
+		return "'" + s + "'"
+	}
+
+	return `"` + s + `"`
+}
+
+//
+// renderer: Write the text fragments interleaved with the children.
+//
+func (id *renderer) inner(node *DOMNode) {
+	id.eachContent(node, func(text string) {
+		id.text(node, text)
+	}, func(child *DOMNode) {
+		id.outer(child)
+	})
+}
+
+//
+// renderer: Call textFn for the text fragments and childFn for the children
+// of the node, with its comments passed to childFn in document order.
+//
+func (id *renderer) eachContent(node *DOMNode, textFn func(text string), childFn func(child *DOMNode)) {
+	comments := id.nodeComments(node)
+	texts, children := 0, 0
+	// a comment follows its preceding text fragments and children
+	precede := func() {
+		for len(comments) > 0 && comments[0].textBefore <= texts &&
+			(children == len(node.Children) || comments[0].Index < node.Children[children].Index) {
+			childFn(comments[0])
+			comments = comments[1:]
+		}
+	}
+	node.eachContent(func(text string) {
+		precede()
+		textFn(text)
+		texts++
+	}, func(child *DOMNode) {
+		precede()
+		childFn(child)
+		children++
+	})
+
+	for _, comment := range comments {
+		childFn(comment)
+	}
+}
+
+//
+// renderer: The comments of the node in document order, gathered from the
+// document on first use.
+//
+func (id *renderer) nodeComments(node *DOMNode) []*DOMNode {
+	if node.dom == nil {
+		return nil
+	}
+	if id.comments == nil {
+		id.comments = map[*DOMNode][]*DOMNode{}
+		for _, docNode := range node.dom.document {
+			if docNode.Tag == "comment" && docNode.Parent != nil {
+				id.comments[docNode.Parent] = append(id.comments[docNode.Parent], docNode)
+			}
+		}
+	}
+
+	return id.comments[node]
+}
+
+//
+// renderer: Write a text fragment of the node, escaped unless raw text.
+//
+func (id *renderer) text(node *DOMNode, text string) {
+	if rawTextTags[node.Tag] == 1 {
+		id.write(text)
+		return
+	}
+
+	if id.options.Minify && !node.preformatted() {
+		text = renderSpace.ReplaceAllString(text, " ")
+		if len(text) == 0 {
+			return
+		}
+	}
+	id.write(escapeText(text, id.options.Escape))
+}
+
+//
+// renderer: Write the start tag of the node.
+//
+func (id *renderer) startTag(node *DOMNode) {
+	id.write("<")
+	id.write(node.TagName())
+	id.attributes(node)
+	if id.options.SelfClose && !id.options.Minify && node.void() {
+		id.write(" />")
+	} else {
+		id.write(">")
+	}
+}
+
+//
+// DOMNode: Is the node an HTML void element, without content or an end tag?
+// XML documents have no void elements, eg. the RSS link.
+//
+func (id *DOMNode) void() bool {
+	return voidTags[id.Tag] == 1 && (id.dom == nil || !id.dom.xml)
+}
+
+//
+// renderer: Write the end tag of the node.
+//
+func (id *renderer) endTag(node *DOMNode) {
+	id.write("</")
+	id.write(node.TagName())
+	id.write(">")
+}
+
+//
+// renderer: Write the attributes in key order so output is deterministic.
+//
+func (id *renderer) attributes(node *DOMNode) {
+	keys := make([]string, 0, len(node.Attributes))
+	for key := range node.Attributes {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	quote := id.options.Quote
+	if id.options.Minify {
+		quote = QuoteMinimal
+	}
+
+	for _, key := range keys {
+		val := escapeText(node.Attributes[key], id.options.Escape)
+		if id.options.Minify && strings.EqualFold(strings.TrimSpace(node.Attributes[key]), renderDefaultAttrs[node.Tag][strings.ToLower(key)]) && len(val) > 0 {
+			continue
+		}
+		id.write(" ")
+		id.write(key)
+		switch {
+		case quote == QuoteMinimal && len(val) == 0:
+		case quote == QuoteMinimal && !strings.ContainsAny(val, " \t\n\f\r=`\"'<>"):
+			// the quotes and angle brackets are escaped unless raw
+			id.write("=")
+			id.write(val)
+		case quote == QuoteSingle && !strings.Contains(val, "'"),
+			strings.Contains(val, "\"") && !strings.Contains(val, "'"):
+			// a raw value holding double quotes is single quoted
+			id.write("='")
+			id.write(val)
+			id.write("'")
+		default:
+			id.write("=\"")
+			id.write(val)
+			id.write("\"")
+		}
+	}
+}
+
+//
+// DOMNode: Does the node hold only text and phrasing content, which a
+// pretty-printing Render keeps on one line?
+//
+func (id *DOMNode) renderInline() bool {
+	if id.preserveSpace || rawTextTags[id.Tag] == 1 || id.Tag == "pre" || id.Tag == "textarea" {
+		return true
+	}
+	for _, child := range id.Children {
+		if renderPhrasingTags[child.Tag] == 0 || !child.renderInline() {
+			return false
+		}
+	}
+
+	return true
+}
+
+//
+// DOMNode: Visit the text fragments and children in document order.
+//
+func (id *DOMNode) eachContent(textFn func(text string), childFn func(child *DOMNode)) {
+	i := 0
+	for childIndex, child := range id.Children {
+		for ; i < len(id.TextFragments) && id.textPosition(i) <= childIndex; i++ {
+			textFn(id.TextFragments[i])
+		}
+		childFn(child)
+	}
+
+	// trailing fragments
+	for ; i < len(id.TextFragments); i++ {
+		textFn(id.TextFragments[i])
+	}
+}
+
+//
+// DOMNode: The child position of the text fragment, fragments assigned
+// outside of parsing are treated as trailing.
+//
+func (id *DOMNode) textPosition(fragment int) int {
+	if fragment < len(id.textIndex) {
+		return id.textIndex[fragment]
+	}
+
+	return len(id.Children)
+}
+
+//
+// DOMNode: Is the text of the node preformatted, its whitespace significant?
+//
+func (id *DOMNode) preformatted() bool {
+	if id.preserveSpace {
+		return true
+	}
+	for node := id; node != nil; node = node.Parent {
+		if node.Tag == "pre" || node.Tag == "textarea" {
+			return true
+		}
+	}
+
+	return false
+}
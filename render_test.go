@@ -0,0 +1,119 @@
+// Copyright 2016, Marc Lavergne <mlavergn@gmail.com>. All rights reserved.
+// Use of this source code is governed by the MIT
+// license that can be found in the LICENSE file.
+
+package godom
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestOuterHTML(t *testing.T) {
+	d := NewDOM()
+	d.SetContents("<html><div id=\"a\" class=\"x\">Hello <strong>there</strong> world<br>&amp; more</div></html>")
+	p := d.Find("div", map[string]string{"id": "a"})
+	if len(p) != 1 {
+		d.Dump()
+		t.Fatalf("failed to find node")
+	}
+
+	expected := "<div class=\"x\" id=\"a\">Hello<strong>there</strong>world<br>&amp; more</div>"
+	if p[0].OuterHTML() != expected {
+		t.Errorf("failed to serialize node [%s]", p[0].OuterHTML())
+	}
+
+	expected = "Hello<strong>there</strong>world<br>&amp; more"
+	if p[0].InnerHTML() != expected {
+		t.Errorf("failed to serialize children [%s]", p[0].InnerHTML())
+	}
+
+	// comments are kept in place though they are not children
+	d = NewDOM()
+	d.SetContents("<html><body><div>a<!--x-->b<p>c<!--y--></p><!--[if IE]>ie<![endif]--><i>d</i></div></body></html>")
+	expected = "<div>a<!--x-->b<p>c<!--y--></p><!--[if IE]>ie<![endif]--><i>d</i></div>"
+	if html := d.Find("div", nil)[0].OuterHTML(); html != expected {
+		t.Errorf("failed to serialize comments [%s]", html)
+	}
+}
+
+func TestOuterHTMLScript(t *testing.T) {
+	d := NewDOM()
+	d.SetContents("<html><head><script>if (a < b) {}</script></head></html>")
+	p := d.Find("script", nil)
+	if len(p) != 1 {
+		d.Dump()
+		t.Fatalf("failed to find node")
+	}
+
+	if p[0].OuterHTML() != "<script>if (a < b) {}</script>" {
+		t.Errorf("failed to serialize raw text [%s]", p[0].OuterHTML())
+	}
+}
+
+func TestRender(t *testing.T) {
+	d := NewDOM()
+	d.SetContents("<!DOCTYPE html><html><head><title>T</title></head><body><div class=\"a b\" hidden=\"\"><p>Hello <b>you</b></p><img src=\"x.png\" alt=\"it's\"><ul><li>one</li></ul></div><pre>  keep\n  me</pre></body></html>")
+
+	sb := strings.Builder{}
+	err := d.Render(&sb, RenderOptions{Indent: "  "})
+	expected := `<!DOCTYPE html>
+<html>
+  <head>
+    <title>T</title>
+  </head>
+  <body>
+    <div class="a b" hidden="">
+      <p>Hello<b>you</b></p>
+      <img alt="it&#39;s" src="x.png">
+      <ul>
+        <li>one</li>
+      </ul>
+    </div>
+    <pre>keep
+  me</pre>
+  </body>
+</html>
+`
+	if err != nil || sb.String() != expected {
+		t.Errorf("failed to pretty print [%s]", sb.String())
+	}
+
+	sb.Reset()
+	div := d.Find("div", nil)[0]
+	div.Render(&sb, RenderOptions{})
+	if sb.String() != div.OuterHTML() {
+		t.Errorf("failed to render as OuterHTML by default [%s]", sb.String())
+	}
+
+	sb.Reset()
+	d.Find("img", nil)[0].Render(&sb, RenderOptions{Quote: QuoteSingle, SelfClose: true})
+	if sb.String() != "<img alt='it&#39;s' src='x.png' />" {
+		t.Errorf("failed to render single quotes [%s]", sb.String())
+	}
+
+	sb.Reset()
+	div.Render(&sb, RenderOptions{Quote: QuoteMinimal})
+	if !strings.HasPrefix(sb.String(), "<div class=\"a b\" hidden><p>") || !strings.Contains(sb.String(), "src=x.png") {
+		t.Errorf("failed to render minimal quotes [%s]", sb.String())
+	}
+
+	// XML has no void elements
+	feed := NewDOM()
+	feed.SetContentsXML("<rss><channel><link>https://example.com/</link><link/><title>T</title></channel></rss>")
+	if html := feed.RootNode().OuterHTML(); html != "<rss><channel><link>https://example.com/</link><link></link><title>T</title></channel></rss>" {
+		t.Errorf("failed to render XML link [%s]", html)
+	}
+}
+
+func TestRenderMinify(t *testing.T) {
+	d := NewDOM()
+	d.SetContents("<html><head><script type=\"text/javascript\">var a  =  1;</script></head><body><!-- note --><form method=\"GET\" action=\"/s?q=1\"><input type=\"text\" name=\"q\" disabled=\"\"></form><p title=\"a b\">many   spaced\n words</p><pre>a   b</pre></body></html>")
+
+	sb := strings.Builder{}
+	err := d.Render(&sb, RenderOptions{Minify: true, Indent: "  ", SelfClose: true})
+	expected := "<html><head><script>var a  =  1;</script></head><body><form action=\"/s?q=1\"><input disabled name=q></form><p title=\"a b\">many spaced words</p><pre>a   b</pre></body></html>"
+	if err != nil || sb.String() != expected {
+		t.Errorf("failed to minify [%s]", sb.String())
+	}
+}
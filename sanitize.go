@@ -0,0 +1,243 @@
+// Copyright 2016 Marc Lavergne <mlavergn@gmail.com>. All rights reserved.
+// Use of this source code is governed by
+// license that can be found in the LICENSE file.
+
+package godom
+
+import (
+	"strings"
+)
+
+// sanitizeDropTags disallowed elements removed along with their content
+var sanitizeDropTags = map[string]int{
+	"script": 1, "style": 1, "iframe": 1, "frame": 1, "frameset": 1, "object": 1, "embed": 1, "applet": 1,
+	"noscript": 1, "noembed": 1, "noframes": 1, "xmp": 1, "plaintext": 1, "template": 1, "title": 1,
+	"textarea": 1, "select": 1, "svg": 1, "math": 1, "base": 1, "link": 1, "meta": 1,
+}
+
+// sanitizeStructureTags elements of the document skeleton which are kept
+// without attributes
+var sanitizeStructureTags = map[string]int{"html": 1, "head": 1, "body": 1}
+
+// sanitizeURLAttrs attributes holding a URL
+var sanitizeURLAttrs = map[string]int{
+	"href": 1, "src": 1, "action": 1, "formaction": 1, "cite": 1, "poster": 1, "background": 1,
+	"longdesc": 1, "usemap": 1, "data": 1, "xlink:href": 1,
+}
+
+// Policy def
+// The allowlist applied by Sanitize
+type Policy struct {
+	// Tags maps each allowed tag to the attributes allowed on it
+	Tags map[string][]string
+	// Attributes are allowed on every allowed tag
+	Attributes []string
+	// URLSchemes are allowed in URL attributes, relative URLs are always allowed
+	URLSchemes []string
+	// AllowComments retains comment nodes
+	AllowComments bool
+}
+
+//
+// DefaultPolicy : A policy for user generated content allowing text
+// formatting, lists, tables, links, and images over http, https, and mailto.
+//
+func DefaultPolicy() Policy {
+	return Policy{
+		Tags: map[string][]string{
+			"a": {"href", "title"}, "abbr": {"title"}, "b": nil, "blockquote": {"cite"}, "br": nil,
+			"caption": nil, "code": nil, "dd": nil, "del": nil, "div": nil, "dl": nil, "dt": nil, "em": nil,
+			"h1": nil, "h2": nil, "h3": nil, "h4": nil, "h5": nil, "h6": nil, "hr": nil, "i": nil,
+			"img": {"src", "alt", "title", "width", "height"}, "ins": nil, "kbd": nil, "li": nil,
+			"ol": {"start"}, "p": nil, "pre": nil, "q": {"cite"}, "s": nil, "small": nil, "span": nil,
+			"strong": nil, "sub": nil, "sup": nil, "table": nil, "tbody": nil, "td": {"colspan", "rowspan"},
+			"tfoot": nil, "th": {"colspan", "rowspan", "scope"}, "thead": nil, "tr": nil, "u": nil, "ul": nil,
+		},
+		Attributes: []string{"lang", "dir"},
+		URLSchemes: []string{"http", "https", "mailto"},
+	}
+}
+
+//
+// Sanitize : Apply the policy to the DOM. Disallowed elements are unwrapped,
+// keeping their content, except for scripts, styles, embedded content, and
+// other elements whose content is not safe or meaningful as text, which are
+// removed. Disallowed attributes, event handlers, and URLs with disallowed
+// schemes such as javascript: are removed. Serialize the result with
+// OuterHTML. Allowing raw text elements such as style defeats the policy.
+//
+func (id *DOM) Sanitize(policy Policy) error {
+	if id.frozen {
+		return ErrFrozen
+	}
+
+	sanitizer := &sanitizer{
+		tags:       map[string]map[string]int{},
+		attributes: map[string]int{},
+		schemes:    map[string]int{},
+		removed:    map[*DOMNode]bool{},
+		unwrapped:  map[*DOMNode]*DOMNode{},
+	}
+	for tag, attributes := range policy.Tags {
+		allowed := map[string]int{}
+		for _, key := range attributes {
+			allowed[attrKey(key)] = 1
+		}
+		sanitizer.tags[strings.ToLower(tag)] = allowed
+	}
+	for _, key := range policy.Attributes {
+		sanitizer.attributes[attrKey(key)] = 1
+	}
+	for _, scheme := range policy.URLSchemes {
+		sanitizer.schemes[strings.ToLower(scheme)] = 1
+	}
+
+	// gather the top level elements under a placeholder so they are
+	// sanitized like any other children
+	root := &DOMNode{}
+	for _, node := range id.document {
+		if node.Parent == nil && pseudoTags[node.Tag] == 0 {
+			root.Children = append(root.Children, node)
+			node.Parent = root
+		}
+	}
+	sanitizer.children(root)
+	for _, node := range root.Children {
+		node.Parent = nil
+	}
+
+	// the tree is final, drop the document entries that left it
+	document := make([]*DOMNode, 0, len(id.document))
+	for _, node := range id.document {
+		for sanitizer.unwrapped[node.Parent] != nil {
+			node.Parent = sanitizer.unwrapped[node.Parent]
+		}
+		if node.Parent == root {
+			node.Parent = nil
+		}
+		if sanitizer.isRemoved(node) || (node.Tag == "comment" && !policy.AllowComments) {
+			node.dom = nil
+			continue
+		}
+		document = append(document, node)
+	}
+	id.document = document
+	id.reindex()
+
+	return nil
+}
+
+// sanitizer def
+// The compiled policy and the nodes taken out of the tree
+type sanitizer struct {
+	tags       map[string]map[string]int
+	attributes map[string]int
+	schemes    map[string]int
+	removed    map[*DOMNode]bool
+	// unwrapped maps each unwrapped node to the parent which took its content
+	unwrapped map[*DOMNode]*DOMNode
+}
+
+//
+// sanitizer: Sanitize the children of the node, unwrapping or removing the
+// disallowed ones.
+//
+func (id *sanitizer) children(node *DOMNode) {
+	for i := 0; i < len(node.Children); {
+		child := node.Children[i]
+		_, allowed := id.tags[child.Tag]
+		switch {
+		case allowed || sanitizeStructureTags[child.Tag] == 1:
+			id.element(child)
+			i++
+		case sanitizeDropTags[child.Tag] == 1:
+			id.removed[child] = true
+			node.unlinkChild(i)
+		default:
+			// the content of the child is sanitized before it is hoisted
+			id.children(child)
+			count := len(child.Children)
+			node.unwrapChild(i)
+			id.unwrapped[child] = node
+			i += count
+		}
+	}
+}
+
+//
+// sanitizer: Filter the attributes of an allowed node, then its children.
+//
+func (id *sanitizer) element(node *DOMNode) {
+	for key, val := range node.Attributes {
+		if !id.allowAttr(node.Tag, key, val) {
+			node.RemoveAttr(key)
+		}
+	}
+
+	id.children(node)
+}
+
+//
+// sanitizer: Is the attribute allowed on the tag with its value?
+//
+func (id *sanitizer) allowAttr(tag string, key string, val string) bool {
+	switch {
+	case id.tags[tag][key] == 0 && id.attributes[key] == 0:
+		return false
+	case strings.HasPrefix(key, "on"):
+		// event handlers run script
+		return false
+	case sanitizeURLAttrs[key] == 1:
+		return id.allowURL(val)
+	case key == "srcset":
+		return id.allowSrcset(val)
+	}
+
+	return true
+}
+
+//
+// sanitizer: Is the URL relative or of an allowed scheme?
+//
+func (id *sanitizer) allowURL(raw string) bool {
+	// browsers ignore whitespace and control characters within a scheme
+	cleaned := strings.Map(func(r rune) rune {
+		if r <= ' ' || r == 0x7f {
+			return -1
+		}
+		return r
+	}, raw)
+
+	colon := strings.IndexByte(cleaned, ':')
+	if colon < 0 || strings.ContainsAny(cleaned[:colon], "/?#") {
+		return true
+	}
+
+	return id.schemes[strings.ToLower(cleaned[:colon])] == 1
+}
+
+//
+// sanitizer: Are the URLs of every srcset candidate allowed?
+//
+func (id *sanitizer) allowSrcset(srcset string) bool {
+	for _, candidate := range strings.Split(srcset, ",") {
+		if fields := strings.Fields(candidate); len(fields) > 0 && !id.allowURL(fields[0]) {
+			return false
+		}
+	}
+
+	return true
+}
+
+//
+// sanitizer: Has the node or one of its ancestors been taken out of the tree?
+//
+func (id *sanitizer) isRemoved(node *DOMNode) bool {
+	for ; node != nil; node = node.Parent {
+		if id.removed[node] || id.unwrapped[node] != nil {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,47 @@
+// Copyright 2016, Marc Lavergne <mlavergn@gmail.com>. All rights reserved.
+// Use of this source code is governed by the MIT
+// license that can be found in the LICENSE file.
+
+package godom
+
+import (
+	"testing"
+)
+
+func TestSanitize(t *testing.T) {
+	d := NewDOM()
+	d.SetContents("<html><body onload=\"x()\"><div id=\"a\"><p onclick=\"x()\" title=\"t\">Hello <font color=\"red\">red <b>bold</b></font> text</p>" +
+		"<script>alert(1)</script><!-- note --><a href=\"java\tscript:alert(1)\">bad</a> <a href=\"/ok\" target=\"_blank\">ok</a>" +
+		"<img src=\"https://x/a.png\" alt=\"a\"></div></body></html>")
+
+	err := d.Sanitize(DefaultPolicy())
+	if err != nil {
+		t.Fatalf("failed to sanitize %v", err)
+	}
+	checkIndexes(t, &d)
+
+	if len(d.Find("script", nil)) != 0 || len(d.Find("font", nil)) != 0 {
+		d.Dump()
+		t.Fatalf("failed to remove disallowed tags")
+	}
+	for _, node := range d.document {
+		if node.Tag == "comment" {
+			t.Errorf("failed to remove comment")
+		}
+	}
+
+	body := d.Find("body", nil)
+	if len(body) != 1 || body[0].HasAttr("onload") {
+		t.Errorf("failed to strip body attributes")
+	}
+
+	p := d.Find("div", nil)
+	if len(p) != 1 {
+		d.Dump()
+		t.Fatalf("failed to find node")
+	}
+	expected := "<div><p>Hellored<b>bold</b>text</p><a>bad</a><a href=\"/ok\">ok</a><img alt=\"a\" src=\"https://x/a.png\"></div>"
+	if p[0].OuterHTML() != expected {
+		t.Errorf("failed to sanitize [%s]", p[0].OuterHTML())
+	}
+}
@@ -0,0 +1,61 @@
+// Copyright 2016 Marc Lavergne <mlavergn@gmail.com>. All rights reserved.
+// Use of this source code is governed by
+// license that can be found in the LICENSE file.
+
+package godom
+
+//
+// Scope : A read-only view of the subtree of node, which is its root node,
+// with its own tag, id, and attribute indexes so Find and ChildFind scan only
+// the subtree. The view shares the nodes of the DOM and is frozen, it reflects
+// the DOM when created and is replaced once the DOM is modified. Nil when the
+// node does not belong to the DOM, a view narrows to nodes of its subtree.
+//
+func (id *DOM) Scope(node *DOMNode) *DOM {
+	if id.source != nil {
+		if node == nil || !id.IsDescendantNode(id.rootNode, node) {
+			return nil
+		}
+		return id.source.Scope(node)
+	}
+	if node == nil || node.dom != id || !id.intervals(node) {
+		return nil
+	}
+
+	result := NewDOM()
+	result.source = id
+	result.contents = id.contents
+	result.charset = id.charset
+	result.url = id.url
+	result.baseURL = id.baseURL
+	result.options = id.options
+	result.tags = id.tags
+	result.xml = id.xml
+	result.externalStyles = id.externalStyles
+	result.logger = id.logger
+	// style rules apply to the whole document
+	result.styles = id.styleRules()
+
+	result.document = append([]*DOMNode(nil), id.document[node.Index-1:node.last]...)
+	for _, docNode := range result.document {
+		if pseudoTags[docNode.Tag] == 0 || (docNode.Tag == "comment" && id.options.IndexComments) {
+			result.indexNode(docNode)
+		}
+	}
+	result.nodeCount = id.nodeCount
+	result.rootNode = node
+	result.frozen = true
+
+	return &result
+}
+
+//
+// DOM: The DOM owning the nodes, the source of a scoped view.
+//
+func (id *DOM) owner() *DOM {
+	if id.source != nil {
+		return id.source
+	}
+
+	return id
+}
@@ -0,0 +1,63 @@
+// Copyright 2016, Marc Lavergne <mlavergn@gmail.com>. All rights reserved.
+// Use of this source code is governed by the MIT
+// license that can be found in the LICENSE file.
+
+package godom
+
+import (
+	"testing"
+)
+
+func TestScope(t *testing.T) {
+	d := NewDOM()
+	d.SetContents(`<html><body><div id="a"><p id="one">One</p><!-- c --><ul><li>x</li><li>y</li></ul></div><div id="b"><p id="two">Two</p><li>z</li></div></body></html>`)
+
+	a := d.ByID("a")
+	view := d.Scope(a)
+	if view == nil || view.RootNode() != a || !view.Frozen() {
+		t.Fatal("failed to create the view")
+	}
+	if ps := view.Find("p", nil); len(ps) != 1 || ps[0].Attr("id") != "one" {
+		t.Errorf("unexpected view matches %v", ps)
+	}
+	if lis := view.ChildFind(view.Find("ul", nil)[0], "li", nil); len(lis) != 2 {
+		t.Errorf("expected 2 items found %d", len(lis))
+	}
+	if view.ByID("one") == nil || view.ByID("two") != nil {
+		t.Error("unexpected id index")
+	}
+	if len(view.Comments()) != 1 || len(view.Find("*", nil)) != 5 {
+		t.Errorf("unexpected view nodes %d", len(view.Find("*", nil)))
+	}
+	if view.ChildFind(d.ByID("b"), "p", nil) != nil {
+		t.Error("matched outside the view")
+	}
+
+	inner := view.Scope(view.Find("ul", nil)[0])
+	if inner == nil || len(inner.Find("li", nil)) != 2 || view.Scope(d.ByID("b")) != nil {
+		t.Error("failed to narrow the view")
+	}
+
+	other := NewDOM()
+	if d.Scope(other.RootNode()) != nil || d.Scope(nil) != nil {
+		t.Error("scoped a foreign node")
+	}
+	if err := view.SetContents("<p>x</p>"); err != ErrFrozen {
+		t.Errorf("expected ErrFrozen found %v", err)
+	}
+}
+
+func TestScopeReset(t *testing.T) {
+	d := NewDOM()
+	d.SetContents(`<html><body><div id="a"><p>One</p></div></body></html>`)
+
+	a := d.ByID("a")
+	view := d.Scope(a)
+	view.Reset()
+	if view.RootNode() != nil || len(view.Find("p", nil)) != 0 {
+		t.Error("expected an empty view")
+	}
+	if a.dom != &d || len(d.ChildFind(a, "p", nil)) != 1 {
+		t.Error("expected the source nodes to stay attached")
+	}
+}
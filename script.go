@@ -0,0 +1,387 @@
+// Copyright 2016 Marc Lavergne <mlavergn@gmail.com>. All rights reserved.
+// Use of this source code is governed by
+// license that can be found in the LICENSE file.
+
+package godom
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// ErrScriptNotFound error returned when no script contains the key
+var ErrScriptNotFound = errors.New("godom: no script contains the key")
+
+// ErrScriptPath error returned when the path leads to a missing or null value
+var ErrScriptPath = errors.New("godom: no value at path")
+
+// WrapKind type
+// How a JSON payload is wrapped in script
+type WrapKind int
+
+// WrapKind values
+const (
+	// WrapNone is a bare literal
+	WrapNone WrapKind = iota
+	// WrapCallback is a JSONP callback call, eg. cb({...});
+	WrapCallback
+	// WrapAssignment is an assignment, eg. window.foo = {...};
+	WrapAssignment
+)
+
+// xssiPrefixes guards prepended to JSON responses to defeat script inclusion
+var xssiPrefixes = []string{")]}'", "while(1);", "for(;;);"}
+
+// WrappedJSON def
+// A JSON payload unwrapped from a JSONP callback or an assignment
+type WrappedJSON struct {
+	// Node is the script holding the payload, nil for UnwrapJSON
+	Node *DOMNode
+	Kind WrapKind
+	// Wrapper is the callback or assigned name as written, eg. window.foo
+	Wrapper string
+	// Value is the payload as ParseJSLiteral decodes it
+	Value interface{}
+}
+
+//
+// UnwrapJSON : Parse the JSON or JavaScript object or array of s, a JSONP response
+// such as cb({...}); or a script such as window.foo = {...};, stripping the
+// wrapper and reporting its name. var, let, and const declarations, a
+// JSON.parse('...') payload, and XSSI guards such as )]}' are handled. The
+// text following the wrapped payload is ignored.
+//
+func UnwrapJSON(s string) (WrappedJSON, error) {
+	result := WrappedJSON{}
+	parser := &jsParser{s: s}
+	parser.space()
+	for _, prefix := range xssiPrefixes {
+		if strings.HasPrefix(parser.s[parser.pos:], prefix) {
+			parser.pos += len(prefix)
+			parser.space()
+		}
+	}
+
+	start := parser.pos
+	if ident := parser.ident(); ident == "var" || ident == "let" || ident == "const" {
+		parser.space()
+		start = parser.pos
+	} else {
+		parser.pos = start
+	}
+	result.Wrapper = parser.wrapperName()
+	parser.space()
+
+	var err error
+	switch {
+	case len(result.Wrapper) == 0:
+		result.Value, err = parser.value(0)
+	case parser.pos < len(parser.s) && parser.s[parser.pos] == '(':
+		result.Kind = WrapCallback
+		parser.pos++
+		result.Value, err = parser.value(0)
+		parser.space()
+		if err == nil && (parser.pos >= len(parser.s) || parser.s[parser.pos] != ')') {
+			err = parser.errorf("expected ) closing %s", result.Wrapper)
+		}
+	case parser.pos < len(parser.s) && parser.s[parser.pos] == '=':
+		result.Kind = WrapAssignment
+		result.Value, err = parseAssignedLiteral(parser.s[parser.pos+1:])
+	default:
+		parser.pos = start
+		err = parser.errorf("expected a literal, callback, or assignment")
+	}
+	if err != nil {
+		return WrappedJSON{}, err
+	}
+	switch result.Value.(type) {
+	case map[string]interface{}, []interface{}:
+	default:
+		// a call such as console.log("text") is not a payload
+		return WrappedJSON{}, fmt.Errorf("godom: %s payload is not an object or array", result.Wrapper)
+	}
+
+	return result, nil
+}
+
+//
+// FindWrappedJSON : The payloads of the scripts holding a JSONP callback or an
+// assignment named wrapper, or any wrapper or bare literal when wrapper is
+// empty, in document order. Scripts which don't parse are skipped.
+//
+func (id *DOM) FindWrappedJSON(wrapper string) (result []WrappedJSON) {
+	for _, script := range id.Find("script", nil) {
+		wrapped, err := UnwrapJSON(script.Text())
+		if err != nil || (len(wrapper) > 0 && wrapped.Wrapper != wrapper) {
+			continue
+		}
+		wrapped.Node = script
+		result = append(result, wrapped)
+	}
+
+	return result
+}
+
+//
+// jsParser: Parse the dotted name of a wrapper, eg. window.foo or
+// window["foo"], empty if there is none.
+//
+func (id *jsParser) wrapperName() string {
+	start := id.pos
+	for {
+		if len(id.ident()) == 0 {
+			id.pos = start
+			return ""
+		}
+		for strings.HasPrefix(id.s[id.pos:], "[\"") || strings.HasPrefix(id.s[id.pos:], "['") {
+			id.pos++
+			if _, err := id.string(); err != nil || id.pos >= len(id.s) || id.s[id.pos] != ']' {
+				id.pos = start
+				return ""
+			}
+			id.pos++
+		}
+		if id.pos >= len(id.s) || id.s[id.pos] != '.' {
+			return id.s[start:id.pos]
+		}
+		id.pos++
+	}
+}
+
+//
+// FindJSONForScriptInto : Decode the JavaScript literal following substring
+// in the first script containing it into target, as json.Unmarshal would.
+// The literal opens with the first { or [ following the key and is parsed as
+// ParseJSLiteral does, numbers keep their precision.
+//
+func (id *DOM) FindJSONForScriptInto(substring string, target interface{}) error {
+	return id.ChildFindJSONForScriptInto(id.RootNode(), substring, target)
+}
+
+//
+// ChildFindJSONForScriptInto : Decode the JavaScript literal following
+// substring in the first child script containing it into target.
+//
+func (id *DOM) ChildFindJSONForScriptInto(parent *DOMNode, substring string, target interface{}) error {
+	literal, err := id.scriptLiteral(parent, substring)
+	if err != nil {
+		return err
+	}
+
+	data, err := jsLiteralJSON(literal)
+	if err != nil {
+		return err
+	}
+
+	return json.Unmarshal(data, target)
+}
+
+//
+// ScriptValue : The value at path within the JavaScript literal following
+// substring in the first script containing it, eg.
+// props.pageProps.offers[0].price. See JSONPath for the path syntax.
+//
+func (id *DOM) ScriptValue(substring string, path string) (interface{}, error) {
+	literal, err := id.scriptLiteral(id.RootNode(), substring)
+	if err != nil {
+		return nil, err
+	}
+
+	value, err := ParseJSLiteral(literal)
+	if err != nil {
+		return nil, err
+	}
+
+	return JSONPath(value, path)
+}
+
+//
+// JSONPath : The value at path within a decoded JSON value, such as a JSONMap.
+// The path is a dotted list of keys, each optionally followed by array
+// indexes, eg. a.b[0][1].c, where a negative index counts from the end.
+// Keys holding dots or brackets are quoted, eg. a["b.c"]. An empty path or $
+// is the value itself.
+//
+func JSONPath(value interface{}, path string) (interface{}, error) {
+	path = strings.TrimPrefix(strings.TrimSpace(path), "$")
+	for i := 0; i < len(path); {
+		switch {
+		case path[i] == '.':
+			i++
+		case path[i] == '[':
+			end := strings.IndexByte(path[i:], ']')
+			if end < 0 {
+				return nil, fmt.Errorf("godom: invalid path %q", path)
+			}
+			segment := strings.TrimSpace(path[i+1 : i+end])
+			i += end + 1
+			if len(segment) >= 2 && (segment[0] == '"' || segment[0] == '\'') && segment[len(segment)-1] == segment[0] {
+				value = jsonPathKey(value, segment[1:len(segment)-1])
+			} else {
+				index, err := strconv.Atoi(segment)
+				if err != nil {
+					return nil, fmt.Errorf("godom: invalid path %q", path)
+				}
+				value = jsonPathIndex(value, index)
+			}
+		default:
+			end := strings.IndexAny(path[i:], ".[")
+			if end < 0 {
+				end = len(path) - i
+			}
+			value = jsonPathKey(value, path[i:i+end])
+			i += end
+		}
+		if value == nil {
+			return nil, ErrScriptPath
+		}
+	}
+
+	return value, nil
+}
+
+//
+// jsonPathKey : The member key of the object, nil if there is none.
+//
+func jsonPathKey(value interface{}, key string) interface{} {
+	switch object := value.(type) {
+	case map[string]interface{}:
+		return object[key]
+	case JSONMap:
+		return object[key]
+	}
+
+	return nil
+}
+
+//
+// jsonPathIndex : The element at index of the array, nil if there is none.
+//
+func jsonPathIndex(value interface{}, index int) interface{} {
+	array, ok := value.([]interface{})
+	if !ok {
+		return nil
+	}
+	if index < 0 {
+		index += len(array)
+	}
+	if index < 0 || index >= len(array) {
+		return nil
+	}
+
+	return array[index]
+}
+
+//
+// NextData : The Next.js page data held by the __NEXT_DATA__ script.
+//
+func (id *DOM) NextData() (JSONMap, error) {
+	script := id.FindFirst("script", DOMNodeAttributes{"id": "__NEXT_DATA__"})
+	if script == nil {
+		return nil, ErrScriptNotFound
+	}
+
+	value, err := ParseJSLiteral(script.Text())
+	if err != nil {
+		return nil, err
+	}
+
+	return scriptObject(value)
+}
+
+//
+// NuxtState : The Nuxt state assigned to window.__NUXT__. State assigned by a
+// function call rather than a literal can't be parsed.
+//
+func (id *DOM) NuxtState() (JSONMap, error) {
+	return id.InitialState("__NUXT__")
+}
+
+//
+// InitialState : The object assigned to varName by a script, as written by
+// window.__INITIAL_STATE__ = {...}, var __INITIAL_STATE__ = {...}, or
+// window["__INITIAL_STATE__"] = {...}. An assigned JSON.parse('...') call is
+// unwrapped. The literal is parsed as ParseJSLiteral does, so braces within
+// strings are not mistaken for the end of the object.
+//
+func (id *DOM) InitialState(varName string) (JSONMap, error) {
+	assignment, err := regexp.Compile(`(?:^|[^\w$])` + regexp.QuoteMeta(varName) + `(?:["']\])?\s*=[^=]`)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, script := range id.ChildFindWithKey(id.RootNode(), "script", varName) {
+		contents := script.Text()
+		loc := assignment.FindStringIndex(contents)
+		if loc == nil {
+			continue
+		}
+
+		value, err := parseAssignedLiteral(contents[loc[1]-1:])
+		if err != nil {
+			return nil, err
+		}
+		return scriptObject(value)
+	}
+
+	return nil, ErrScriptNotFound
+}
+
+//
+// parseAssignedLiteral : Parse the assigned literal, unwrapping a JSON.parse
+// call of a string literal.
+//
+func parseAssignedLiteral(s string) (interface{}, error) {
+	s = strings.TrimSpace(s)
+	if !strings.HasPrefix(s, "JSON.parse(") {
+		return ParseJSLiteral(s)
+	}
+
+	parser := &jsParser{s: s, pos: len("JSON.parse(")}
+	parser.space()
+	if parser.pos >= len(parser.s) || strings.IndexByte("\"'`", parser.s[parser.pos]) < 0 {
+		return nil, parser.errorf("expected a string argument to JSON.parse")
+	}
+	text, err := parser.string()
+	if err != nil {
+		return nil, err
+	}
+
+	return ParseJSLiteral(text)
+}
+
+//
+// scriptObject : The value as a JSONMap when it is an object.
+//
+func scriptObject(value interface{}) (JSONMap, error) {
+	object, ok := value.(map[string]interface{})
+	if !ok {
+		return nil, ErrScriptObject
+	}
+
+	return JSONMap(object), nil
+}
+
+//
+// DOM: The source of the literal following substring in the first child
+// script containing it, from its opening { or [ to the end of the script.
+//
+func (id *DOM) scriptLiteral(parent *DOMNode, substring string) (string, error) {
+	nodes := id.ChildFindWithKey(parent, "script", substring)
+	if len(nodes) == 0 {
+		return "", ErrScriptNotFound
+	}
+
+	contents := nodes[0].Text()
+	sub := contents[strings.Index(contents, substring):]
+	idx := strings.IndexAny(sub, "{[")
+	if idx < 0 {
+		return "", fmt.Errorf("godom: no object or array follows %q", substring)
+	}
+
+	return sub[idx:], nil
+}
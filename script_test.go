@@ -0,0 +1,196 @@
+// Copyright 2016, Marc Lavergne <mlavergn@gmail.com>. All rights reserved.
+// Use of this source code is governed by the MIT
+// license that can be found in the LICENSE file.
+
+package godom
+
+import (
+	"testing"
+)
+
+func TestFindJSONForScriptInto(t *testing.T) {
+	d := NewDOM()
+	d.SetContents(`<html><head><script>
+	window.product = {id: 9007199254740993, name: 'Lamp', price: 19.90, tags: ['a', 'b',], stock: {count: 0x10}};
+	var ids = [1, 2, 3];
+	</script></head><body></body></html>`)
+
+	var product struct {
+		ID    int64    `json:"id"`
+		Name  string   `json:"name"`
+		Price float64  `json:"price"`
+		Tags  []string `json:"tags"`
+		Stock struct {
+			Count int `json:"count"`
+		} `json:"stock"`
+	}
+	if err := d.FindJSONForScriptInto("window.product", &product); err != nil {
+		t.Fatalf("failed to decode script JSON %v", err)
+	}
+	if product.ID != 9007199254740993 || product.Name != "Lamp" || product.Price != 19.9 || len(product.Tags) != 2 || product.Stock.Count != 16 {
+		t.Errorf("unexpected product %+v", product)
+	}
+
+	var ids []int
+	if err := d.FindJSONForScriptInto("var ids", &ids); err != nil || len(ids) != 3 || ids[2] != 3 {
+		t.Errorf("failed to decode script array %v %v", ids, err)
+	}
+
+	if err := d.FindJSONForScriptInto("missing", &ids); err != ErrScriptNotFound {
+		t.Errorf("expected not found error %v", err)
+	}
+	if err := d.FindJSONForScriptInto("window.product", product); err == nil {
+		t.Errorf("expected invalid target error")
+	}
+}
+
+func TestScriptValue(t *testing.T) {
+	d := NewDOM()
+	d.SetContents(`<html><head><script>
+	window.__STATE__ = {"props": {"pageProps": {"product": {"offers": [{"price": 12.5}, {"price": 10}], "a.b": "dotted"}}}};
+	</script></head><body></body></html>`)
+
+	tests := []struct {
+		path     string
+		expected interface{}
+	}{
+		{"props.pageProps.product.offers[0].price", 12.5},
+		{"$.props.pageProps.product.offers[-1].price", 10.0},
+		{`props.pageProps.product["a.b"]`, "dotted"},
+	}
+	for _, test := range tests {
+		value, err := d.ScriptValue("__STATE__", test.path)
+		if err != nil || value != test.expected {
+			t.Errorf("unexpected value %v for %s %v", value, test.path, err)
+		}
+	}
+
+	for _, path := range []string{"props.missing", "props.pageProps.product.offers[2]", "props.pageProps.product.offers.price"} {
+		if _, err := d.ScriptValue("__STATE__", path); err != ErrScriptPath {
+			t.Errorf("expected path error for %s %v", path, err)
+		}
+	}
+	if _, err := d.ScriptValue("__STATE__", "props[x]"); err == nil || err == ErrScriptPath {
+		t.Errorf("expected invalid path error")
+	}
+
+	if value, _ := JSONPath(JSONMap{"a": []interface{}{JSONMap{"b": true}}}, "a[0].b"); value != true {
+		t.Errorf("failed to follow JSONMap path")
+	}
+}
+
+func TestInitialState(t *testing.T) {
+	d := NewDOM()
+	d.SetContents(`<html><head>
+<script id="__NEXT_DATA__" type="application/json">{"props": {"pageProps": {"title": "a } b"}}, "page": "/p"}</script>
+<script>if (window.__NUXT__ == null) {} window.__NUXT__ = {state: {cart: '{}', items: [{id: 1}]}};</script>
+<script>window["__APOLLO_STATE__"] = JSON.parse('{"ROOT": {"n": "}"}}');</script>
+<script>var __INITIAL_STATE__ = {user: {name: "x"}}; var __LIST__ = [1];</script>
+</head><body></body></html>`)
+
+	next, err := d.NextData()
+	if err != nil || next["page"] != "/p" {
+		t.Fatalf("failed to extract next data %v %v", next, err)
+	}
+	if title, _ := JSONPath(next, "props.pageProps.title"); title != "a } b" {
+		t.Errorf("unexpected next data title %v", title)
+	}
+
+	nuxt, err := d.NuxtState()
+	if value, _ := JSONPath(nuxt, "state.items[0].id"); err != nil || value != 1.0 {
+		t.Errorf("failed to extract nuxt state %v %v", nuxt, err)
+	}
+	if value, _ := JSONPath(nuxt, "state.cart"); value != "{}" {
+		t.Errorf("unexpected nuxt cart %v", value)
+	}
+
+	apollo, err := d.InitialState("__APOLLO_STATE__")
+	if value, _ := JSONPath(apollo, "ROOT.n"); err != nil || value != "}" {
+		t.Errorf("failed to unwrap JSON.parse %v %v", apollo, err)
+	}
+	state, err := d.InitialState("__INITIAL_STATE__")
+	if value, _ := JSONPath(state, "user.name"); err != nil || value != "x" {
+		t.Errorf("failed to extract initial state %v %v", state, err)
+	}
+
+	if _, err := d.InitialState("__LIST__"); err != ErrScriptObject {
+		t.Errorf("expected object error %v", err)
+	}
+	if _, err := d.InitialState("__MISSING__"); err != ErrScriptNotFound {
+		t.Errorf("expected not found error %v", err)
+	}
+	empty := NewDOM()
+	empty.SetContents("<html><body></body></html>")
+	if _, err := empty.NextData(); err != ErrScriptNotFound {
+		t.Errorf("expected not found error %v", err)
+	}
+}
+
+func TestFindAllJSONForScript(t *testing.T) {
+	d := NewDOM()
+	d.SetContents(`<html><head>
+<script>dataLayer.push({event: "view", page: {id: 1}}); dataLayer.push({event: "click"});</script>
+<script>dataLayer.push(broken);</script>
+<script>dataLayer.push({event: "load"});</script>
+</head><body></body></html>`)
+
+	results, err := d.FindAllJSONForScriptWithKey("dataLayer.push")
+	if err != nil || len(results) != 3 {
+		t.Fatalf("unexpected payloads %d %v", len(results), err)
+	}
+	scripts := d.Find("script", nil)
+	if results[0].Value["event"] != "view" || results[1].Value["event"] != "click" || results[0].Node != scripts[0] {
+		t.Errorf("unexpected first script payloads %v", results)
+	}
+	if results[2].Value["event"] != "load" || results[2].Node != scripts[2] {
+		t.Errorf("unexpected last script payload %v", results[2])
+	}
+
+	if results, err := d.FindAllJSONForScriptWithKey("missing"); err != nil || results != nil {
+		t.Errorf("expected no payloads %v", err)
+	}
+}
+
+func TestUnwrapJSON(t *testing.T) {
+	tests := []struct {
+		source  string
+		kind    WrapKind
+		wrapper string
+	}{
+		{`{"a": 1}`, WrapNone, ""},
+		{`/**/ jQuery123_456({"a": 1});`, WrapCallback, "jQuery123_456"},
+		{`)]}'` + "\n" + `callbacks.done({a: 1})`, WrapCallback, "callbacks.done"},
+		{`window.foo = {a: 1}; window.bar = 2;`, WrapAssignment, "window.foo"},
+		{`var config = {'a': 1,};`, WrapAssignment, "config"},
+		{`window["state"] = JSON.parse("{\"a\": 1}");`, WrapAssignment, `window["state"]`},
+	}
+	for _, test := range tests {
+		wrapped, err := UnwrapJSON(test.source)
+		if err != nil {
+			t.Errorf("failed to unwrap %s %v", test.source, err)
+			continue
+		}
+		if value, _ := JSONPath(wrapped.Value, "a"); wrapped.Kind != test.kind || wrapped.Wrapper != test.wrapper || value != 1.0 {
+			t.Errorf("unexpected unwrap of %s %+v", test.source, wrapped)
+		}
+	}
+
+	for _, invalid := range []string{`cb({"a": 1}`, `doSomething();`, `x += {}`} {
+		if _, err := UnwrapJSON(invalid); err == nil {
+			t.Errorf("expected error for %s", invalid)
+		}
+	}
+
+	d := NewDOM()
+	d.SetContents(`<html><head>
+<script>window.__CONFIG__ = {env: "prod"};</script>
+<script>track({event: "view"});</script>
+<script>console.log("hi");</script>
+</head><body></body></html>`)
+	if all := d.FindWrappedJSON(""); len(all) != 2 || all[1].Wrapper != "track" || all[1].Node != d.Find("script", nil)[1] {
+		t.Errorf("unexpected wrapped payloads %v", all)
+	}
+	if config := d.FindWrappedJSON("window.__CONFIG__"); len(config) != 1 || config[0].Value.(map[string]interface{})["env"] != "prod" {
+		t.Errorf("unexpected config payload %v", config)
+	}
+}
@@ -0,0 +1,165 @@
+// Copyright 2016 Marc Lavergne <mlavergn@gmail.com>. All rights reserved.
+// Use of this source code is governed by
+// license that can be found in the LICENSE file.
+
+package godom
+
+import (
+	"sort"
+	"strings"
+)
+
+// Selection def
+// A chainable set of nodes in document order
+type Selection struct {
+	Nodes []*DOMNode
+}
+
+//
+// NewSelection constructor
+//
+func NewSelection(nodes []*DOMNode) *Selection {
+	return &Selection{
+		Nodes: nodes,
+	}
+}
+
+//
+// Select : Select the Nodes of type tag with the specified attributes
+//
+func (id *DOM) Select(tag string, attributes DOMNodeAttributes) *Selection {
+	return NewSelection(id.Find(tag, attributes))
+}
+
+//
+// Length : The number of nodes in the selection.
+//
+func (id *Selection) Length() int {
+	return len(id.Nodes)
+}
+
+//
+// Find : Select the descendants of type tag with the specified attributes.
+//
+func (id *Selection) Find(tag string, attributes DOMNodeAttributes) *Selection {
+	seen := map[*DOMNode]bool{}
+	result := []*DOMNode{}
+	for _, node := range id.Nodes {
+		for _, child := range node.find(tag, attributes) {
+			if child != node && !seen[child] {
+				seen[child] = true
+				result = append(result, child)
+			}
+		}
+	}
+
+	// nested selections produce results out of order
+	sortNodes(result)
+
+	return NewSelection(result)
+}
+
+//
+// Filter : Select the nodes for which fn returns true.
+//
+func (id *Selection) Filter(fn func(i int, node *DOMNode) bool) *Selection {
+	result := []*DOMNode{}
+	for i, node := range id.Nodes {
+		if fn(i, node) {
+			result = append(result, node)
+		}
+	}
+
+	return NewSelection(result)
+}
+
+//
+// First : Select the first node.
+//
+func (id *Selection) First() *Selection {
+	return id.Eq(0)
+}
+
+//
+// Eq : Select the node at index i, negative indexes count from the end.
+//
+func (id *Selection) Eq(i int) *Selection {
+	if i < 0 {
+		i += len(id.Nodes)
+	}
+	if i < 0 || i >= len(id.Nodes) {
+		return NewSelection([]*DOMNode{})
+	}
+
+	return NewSelection(id.Nodes[i : i+1])
+}
+
+//
+// Each : Call fn for every node in the selection.
+//
+func (id *Selection) Each(fn func(i int, node *DOMNode)) *Selection {
+	for i, node := range id.Nodes {
+		fn(i, node)
+	}
+
+	return id
+}
+
+//
+// Map : Collect the result of fn for every node in the selection.
+//
+func (id *Selection) Map(fn func(i int, node *DOMNode) string) (result []string) {
+	result = make([]string, 0, len(id.Nodes))
+	for i, node := range id.Nodes {
+		result = append(result, fn(i, node))
+	}
+
+	return result
+}
+
+//
+// Attr : The value of the attribute key on the first node, and whether it exists.
+//
+func (id *Selection) Attr(key string) (string, bool) {
+	if len(id.Nodes) == 0 || !id.Nodes[0].HasAttr(key) {
+		return "", false
+	}
+
+	return id.Nodes[0].Attr(attrKey(key)), true
+}
+
+//
+// Text : The text of every node in the selection joined by a space.
+//
+func (id *Selection) Text() string {
+	return strings.Join(id.Map(func(i int, node *DOMNode) string {
+		return node.Text()
+	}), " ")
+}
+
+//
+// DOMNode: Find the descendants of type tag with the specified attributes,
+// walking the subtree when the node is detached.
+//
+func (id *DOMNode) find(tag string, attributes DOMNodeAttributes) (result []*DOMNode) {
+	if id.dom != nil {
+		return id.dom.ChildFind(id, tag, attributes)
+	}
+
+	for _, node := range id.elementNodes() {
+		if node.Tag == tag && node.matchAttributes(attributes) {
+			result = append(result, node)
+		}
+	}
+
+	return result
+}
+
+//
+// sortNodes : Sort the nodes into document order.
+//
+func sortNodes(nodes []*DOMNode) {
+	sort.SliceStable(nodes, func(i, j int) bool {
+		return nodes[i].Index < nodes[j].Index
+	})
+}
@@ -0,0 +1,64 @@
+// Copyright 2016, Marc Lavergne <mlavergn@gmail.com>. All rights reserved.
+// Use of this source code is governed by the MIT
+// license that can be found in the LICENSE file.
+
+package godom
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestSelection(t *testing.T) {
+	d := NewDOM()
+	d.SetContents("<html><ul class='menu'><li><a href='/a'>A</a></li><li class='skip'><a href='/b'>B</a></li><li><a href='/c'>C</a></li></ul><a href='/d'>D</a></html>")
+
+	links := d.Select("ul", DOMNodeAttributes{"class": "menu"}).
+		Find("li", nil).
+		Filter(func(i int, node *DOMNode) bool {
+			return !node.HasClass("skip")
+		}).
+		Find("a", nil)
+	if links.Length() != 2 {
+		d.Dump()
+		t.Fatalf("failed to select links %d", links.Length())
+	}
+
+	hrefs := links.Map(func(i int, node *DOMNode) string {
+		return node.Attr("href")
+	})
+	if strings.Join(hrefs, ",") != "/a,/c" {
+		t.Errorf("failed to map links %v", hrefs)
+	}
+	if links.Text() != "A C" {
+		t.Errorf("failed to select text [%s]", links.Text())
+	}
+	if href, ok := links.Eq(-1).Attr("href"); !ok || href != "/c" {
+		t.Errorf("failed to select last link [%s]", href)
+	}
+	if _, ok := links.First().Attr("title"); ok {
+		t.Errorf("failed to report missing attribute")
+	}
+	if links.Eq(5).Length() != 0 {
+		t.Errorf("failed to select out of range")
+	}
+
+	count := 0
+	d.Select("a", nil).Each(func(i int, node *DOMNode) {
+		count++
+	})
+	if count != 4 {
+		t.Errorf("failed to iterate selection %d", count)
+	}
+}
+
+func TestSelectionNested(t *testing.T) {
+	d := NewDOM()
+	d.SetContents("<html><div id='a'><div id='b'><p>one</p></div><p>two</p></div></html>")
+
+	p := d.Select("div", nil).Find("p", nil)
+	if p.Length() != 2 || p.Text() != "one two" {
+		d.Dump()
+		t.Errorf("failed to select nested nodes [%s]", p.Text())
+	}
+}
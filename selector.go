@@ -0,0 +1,346 @@
+// Copyright 2016 Marc Lavergne <mlavergn@gmail.com>. All rights reserved.
+// Use of this source code is governed by
+// license that can be found in the LICENSE file.
+
+package godom
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Selector def
+// A parsed CSS selector supporting tag, *, #id, .class, [attr] and [attr op val]
+// with the =, ~=, ^=, $=, *=, |= operators, :nth-of-type(n) with a positive n,
+// descendant and > child combinators, and comma separated groups
+type Selector struct {
+	source string
+	groups [][]selectorStep
+}
+
+// selectorStep a compound selector and the combinator joining it to the previous step
+type selectorStep struct {
+	combinator byte
+	tag        string
+	attrs      []selectorAttr
+	// nthOfType is the :nth-of-type position from 1, 0 when unset
+	nthOfType int
+}
+
+// selectorAttr an attribute condition of a compound selector
+type selectorAttr struct {
+	key string
+	op  string
+	val string
+	// missing matches an absent attribute as empty, as Find does
+	missing bool
+}
+
+//
+// ParseSelector : Parse the CSS selector string.
+//
+func ParseSelector(selector string) (*Selector, error) {
+	result := &Selector{source: selector}
+	for _, group := range splitSelectorGroups(selector) {
+		steps, err := parseSelectorGroup(group)
+		if err != nil {
+			return nil, err
+		}
+		result.groups = append(result.groups, steps)
+	}
+
+	return result, nil
+}
+
+//
+// MustParseSelector : Parse the CSS selector string, panicking if it is invalid.
+//
+func MustParseSelector(selector string) *Selector {
+	result, err := ParseSelector(selector)
+	if err != nil {
+		panic(err)
+	}
+
+	return result
+}
+
+//
+// String : The selector source.
+//
+func (id *Selector) String() string {
+	return id.source
+}
+
+//
+// Match : Does the node match any group of the selector?
+//
+func (id *Selector) Match(node *DOMNode) bool {
+	if node == nil {
+		return false
+	}
+	for _, steps := range id.groups {
+		if matchSelectorSteps(steps, len(steps)-1, node) {
+			return true
+		}
+	}
+
+	return false
+}
+
+//
+// splitSelectorGroups : Split the selector on commas outside of attribute conditions.
+//
+func splitSelectorGroups(selector string) (groups []string) {
+	depth := 0
+	start := 0
+	for i := 0; i < len(selector); i++ {
+		switch selector[i] {
+		case '[':
+			depth++
+		case ']':
+			depth--
+		case ',':
+			if depth == 0 {
+				groups = append(groups, selector[start:i])
+				start = i + 1
+			}
+		}
+	}
+
+	return append(groups, selector[start:])
+}
+
+//
+// parseSelectorGroup : Parse a selector without commas into compound steps.
+//
+func parseSelectorGroup(group string) (steps []selectorStep, err error) {
+	group = strings.TrimSpace(group)
+	if len(group) == 0 {
+		return nil, fmt.Errorf("godom: empty selector")
+	}
+
+	combinator := byte(' ')
+	i := 0
+	for i < len(group) {
+		step := selectorStep{combinator: combinator}
+		start := i
+		for i < len(group) && group[i] != ' ' && group[i] != '>' {
+			switch c := group[i]; {
+			case c == '*':
+				i++
+			case c == '#' || c == '.':
+				name, next := scanSelectorIdent(group, i+1)
+				i = next
+				if len(name) == 0 {
+					return nil, fmt.Errorf("godom: invalid selector %q", group)
+				}
+				if c == '#' {
+					step.attrs = append(step.attrs, selectorAttr{key: "id", op: "=", val: name})
+				} else {
+					step.attrs = append(step.attrs, selectorAttr{key: "class", op: "~=", val: name})
+				}
+			case c == '[':
+				end := strings.IndexByte(group[i:], ']')
+				if end < 0 {
+					return nil, fmt.Errorf("godom: unterminated attribute in selector %q", group)
+				}
+				attr, attrErr := parseSelectorAttr(group[i+1 : i+end])
+				if attrErr != nil {
+					return nil, attrErr
+				}
+				step.attrs = append(step.attrs, attr)
+				i += end + 1
+			case c == ':':
+				nth, next := parseSelectorNth(group, i)
+				if nth == 0 {
+					return nil, fmt.Errorf("godom: unsupported pseudo-class in selector %q", group)
+				}
+				step.nthOfType = nth
+				i = next
+			default:
+				name, next := scanSelectorIdent(group, i)
+				i = next
+				if len(name) == 0 {
+					return nil, fmt.Errorf("godom: invalid selector %q", group)
+				}
+				// kept as written for the case-sensitive XML tags
+				step.tag = name
+			}
+		}
+		if i == start {
+			return nil, fmt.Errorf("godom: invalid selector %q", group)
+		}
+		steps = append(steps, step)
+
+		// consume the combinator
+		combinator = ' '
+		for i < len(group) && (group[i] == ' ' || group[i] == '>') {
+			if group[i] == '>' {
+				combinator = '>'
+			}
+			i++
+		}
+	}
+
+	return steps, nil
+}
+
+//
+// scanSelectorIdent : Scan an identifier starting at i.
+//
+func scanSelectorIdent(s string, i int) (string, int) {
+	start := i
+	for i < len(s) {
+		c := s[i]
+		if c == '-' || c == '_' || c >= '0' && c <= '9' || c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= 0x80 {
+			i++
+		} else {
+			break
+		}
+	}
+
+	return s[start:i], i
+}
+
+//
+// parseSelectorNth : Parse the :nth-of-type(n) at i, 0 when it is not one.
+//
+func parseSelectorNth(s string, i int) (int, int) {
+	const prefix = ":nth-of-type("
+	if !strings.HasPrefix(strings.ToLower(s[i:]), prefix) {
+		return 0, i
+	}
+	end := strings.IndexByte(s[i:], ')')
+	if end < 0 {
+		return 0, i
+	}
+	nth, err := strconv.Atoi(strings.TrimSpace(s[i+len(prefix) : i+end]))
+	if err != nil || nth < 1 {
+		return 0, i
+	}
+
+	return nth, i + end + 1
+}
+
+//
+// parseSelectorAttr : Parse the contents of an [attr op val] condition.
+//
+func parseSelectorAttr(s string) (selectorAttr, error) {
+	// the operator precedes any quoted value, eg. [a="x^=y"]
+	end := strings.IndexAny(s, "\"'")
+	if end < 0 {
+		end = len(s)
+	}
+	if eq := strings.IndexByte(s[:end], '='); eq > 0 {
+		idx := eq
+		if strings.IndexByte("~^$*|", s[eq-1]) >= 0 {
+			idx--
+		}
+		if idx > 0 {
+			val := strings.TrimSpace(s[eq+1:])
+			if len(val) >= 2 && (val[0] == '"' || val[0] == '\'') && val[len(val)-1] == val[0] {
+				val = val[1 : len(val)-1]
+			}
+			return selectorAttr{key: attrKey(s[:idx]), op: s[idx : eq+1], val: val}, nil
+		}
+	}
+
+	key := attrKey(s)
+	if len(key) == 0 {
+		return selectorAttr{}, fmt.Errorf("godom: invalid attribute selector [%s]", s)
+	}
+
+	return selectorAttr{key: key}, nil
+}
+
+//
+// matchSelectorSteps : Match the steps up to and including i, right to left.
+//
+func matchSelectorSteps(steps []selectorStep, i int, node *DOMNode) bool {
+	if !steps[i].match(node) {
+		return false
+	}
+	if i == 0 {
+		return true
+	}
+
+	if steps[i].combinator == '>' {
+		return node.Parent != nil && matchSelectorSteps(steps, i-1, node.Parent)
+	}
+	for ancestor := node.Parent; ancestor != nil; ancestor = ancestor.Parent {
+		if matchSelectorSteps(steps, i-1, ancestor) {
+			return true
+		}
+	}
+
+	return false
+}
+
+//
+// selectorStep: Does the node satisfy the compound selector?
+//
+func (id selectorStep) match(node *DOMNode) bool {
+	if pseudoTags[node.Tag] == 1 || (len(id.tag) > 0 && !node.isTag(id.tag)) {
+		return false
+	}
+	for _, attr := range id.attrs {
+		if !attr.match(node) {
+			return false
+		}
+	}
+
+	return id.nthOfType == 0 || nthOfType(node) == id.nthOfType
+}
+
+//
+// nthOfType : The position of the node among its sibling elements of the
+// same type from 1, the top level elements being siblings.
+//
+func nthOfType(node *DOMNode) int {
+	nth := 0
+	for _, sibling := range node.siblings() {
+		if sibling.Tag == node.Tag {
+			nth++
+		}
+		if sibling == node {
+			break
+		}
+	}
+
+	return nth
+}
+
+//
+// selectorAttr: Does the node satisfy the attribute condition?
+//
+func (id selectorAttr) match(node *DOMNode) bool {
+	_, val, ok := node.lookupAttr(id.key)
+	if !ok && !id.missing {
+		return false
+	}
+
+	switch id.op {
+	case "":
+		return true
+	case "=":
+		return val == id.val
+	case "~=":
+		for _, token := range strings.Fields(val) {
+			if token == id.val {
+				return true
+			}
+		}
+		return false
+	case "^=":
+		return len(id.val) > 0 && strings.HasPrefix(val, id.val)
+	case "$=":
+		return len(id.val) > 0 && strings.HasSuffix(val, id.val)
+	case "*=":
+		return len(id.val) > 0 && strings.Contains(val, id.val)
+	case "|=":
+		return val == id.val || strings.HasPrefix(val, id.val+"-")
+	}
+
+	return false
+}
@@ -0,0 +1,60 @@
+// Copyright 2016, Marc Lavergne <mlavergn@gmail.com>. All rights reserved.
+// Use of this source code is governed by the MIT
+// license that can be found in the LICENSE file.
+
+package godom
+
+import (
+	"testing"
+)
+
+func TestSelectorMatch(t *testing.T) {
+	d := NewDOM()
+	d.SetContents("<html><div id='main' lang='en-US'><ul><li><a class='btn primary' href='https://example.com/a.pdf' title='Get it now'>A</a></li></ul></div></html>")
+	p := d.Find("a", nil)
+	if len(p) != 1 {
+		d.Dump()
+		t.Fatalf("failed to find node")
+	}
+
+	matches := []string{
+		"a", "*", ".btn", "a.primary.btn", "#main a", "div#main > ul > li > a", "li>a",
+		"[href]", "[href^=https]", "[href$='.pdf']", "[href*=example]", "[class~=primary]",
+		"[title=\"Get it now\"]", "[lang|=en] a", "span, a", "[title=\"a,b\"], a",
+	}
+	for _, selector := range matches {
+		if !MustParseSelector(selector).Match(p[0]) {
+			t.Errorf("failed to match selector %s", selector)
+		}
+	}
+
+	misses := []string{"span", ".btn.secondary", "div > a", "[href^=http:]", "[class=btn]", "#other a"}
+	for _, selector := range misses {
+		if MustParseSelector(selector).Match(p[0]) {
+			t.Errorf("failed to reject selector %s", selector)
+		}
+	}
+
+	for _, selector := range []string{"", "a[href", "a, ", "a:hover"} {
+		if _, err := ParseSelector(selector); err == nil {
+			t.Errorf("failed to reject invalid selector %q", selector)
+		}
+	}
+}
+
+func TestSelectorQuotedOperator(t *testing.T) {
+	d := NewDOM()
+	d.SetContents("<html><a title='x^=y' rel='a~=b'>A</a></html>")
+	a := d.Find("a", nil)[0]
+
+	for _, selector := range []string{"[title=\"x^=y\"]", "[title^='x^=']", "[rel$=\"~=b\"]", "[title*=^=]"} {
+		if !MustParseSelector(selector).Match(a) {
+			t.Errorf("failed to match selector %s", selector)
+		}
+	}
+	for _, selector := range []string{"[title=\"x\"]", "[rel~='a']"} {
+		if MustParseSelector(selector).Match(a) {
+			t.Errorf("failed to reject selector %s", selector)
+		}
+	}
+}
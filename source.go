@@ -0,0 +1,202 @@
+// Copyright 2016 Marc Lavergne <mlavergn@gmail.com>. All rights reserved.
+// Use of this source code is governed by
+// license that can be found in the LICENSE file.
+
+package godom
+
+import (
+	"golang.org/x/net/html"
+	"strings"
+)
+
+// sourceAlignWindow how far ahead alignment looks past implied elements and
+// start tags dropped by the parser
+const sourceAlignWindow = 8
+
+// sourceTracker def
+// The position reached in the contents, the line and column count from 1
+type sourceTracker struct {
+	offset int
+	line   int
+	column int
+}
+
+// sourceToken def
+// A start tag of the contents and where its element ends, with the tag and
+// attribute keys as written when scanned for case
+type sourceToken struct {
+	tag string
+	sourceTracker
+	end  int
+	name string
+	keys []string
+}
+
+//
+// SourceOffset : The byte offset of the start tag of the node in the parsed
+// contents, or -1 when unknown. Positions are recorded when the
+// SourcePositions parse option is set.
+//
+func (id *DOMNode) SourceOffset() int {
+	if id.sourceLine == 0 {
+		return -1
+	}
+
+	return id.sourceOffset
+}
+
+//
+// SourcePosition : The line and byte column, counted from 1, of the start tag
+// of the node in the parsed contents, or 0, 0 when unknown.
+//
+func (id *DOMNode) SourcePosition() (line int, column int) {
+	return id.sourceLine, id.sourceColumn
+}
+
+//
+// Source : The contents from the start tag of the node to its end tag as
+// parsed. When the end tag is implied the source runs to where the parser
+// closed the element. Empty when unknown or the contents were not retained.
+//
+func (id *DOMNode) Source() string {
+	if id.sourceLine == 0 || id.dom == nil || id.sourceEnd > len(id.dom.contents) {
+		return ""
+	}
+
+	return id.dom.contents[id.sourceOffset:id.sourceEnd]
+}
+
+//
+// sourceTracker: Advance past the raw bytes of a token.
+//
+func (id *sourceTracker) advance(raw []byte) {
+	for _, c := range raw {
+		id.offset++
+		if c == '\n' {
+			id.line++
+			id.column = 1
+		} else {
+			id.column++
+		}
+	}
+}
+
+//
+// DOMNode: Record the position of the start tag.
+//
+func (id *DOMNode) setSource(position sourceTracker, end int) {
+	id.sourceOffset = position.offset
+	id.sourceLine = position.line
+	id.sourceColumn = position.column
+	id.sourceEnd = end
+}
+
+//
+// scanSource : Tokenize the contents into its start tags, pairing each with
+// its end tag as the stream parse does, and with the case as written when
+// names is set.
+//
+func scanSource(contents string, names bool) (result []sourceToken) {
+	z := html.NewTokenizer(strings.NewReader(contents))
+	tracker := sourceTracker{line: 1, column: 1}
+
+	// positions in result of the open elements
+	stack := []int{}
+	for {
+		tokenType := z.Next()
+		start := tracker
+		tracker.advance(z.Raw())
+
+		switch tokenType {
+		case html.ErrorToken:
+			for _, open := range stack {
+				result[open].end = tracker.offset
+			}
+			return result
+		case html.StartTagToken, html.SelfClosingTagToken:
+			token := sourceToken{sourceTracker: start, end: tracker.offset}
+			if names {
+				token.name, token.keys = rawTagCase(string(z.Raw()))
+			}
+			name, _ := z.TagName()
+			token.tag = string(name)
+			if voidTags[token.tag] == 0 {
+				stack = append(stack, len(result))
+			}
+			result = append(result, token)
+		case html.EndTagToken:
+			name, _ := z.TagName()
+			tag := strings.ToLower(string(name))
+			for i := len(stack) - 1; i >= 0; i-- {
+				if result[stack[i]].tag == tag {
+					// elements left open are closed by the end tag
+					for _, open := range stack[i+1:] {
+						result[open].end = start.offset
+					}
+					result[stack[i]].end = tracker.offset
+					stack = stack[:i]
+					break
+				}
+			}
+		}
+	}
+}
+
+//
+// alignSource : Assign the start tags to the elements in document order.
+// The parser implies elements absent from the contents and drops misplaced
+// start tags, a mismatch is resolved by the nearest resynchronization.
+//
+func alignSource(elements []*DOMNode, tokens []sourceToken, assign func(node *DOMNode, token *sourceToken)) {
+	i, j := 0, 0
+	for i < len(elements) && j < len(tokens) {
+		if strings.EqualFold(elements[i].Tag, tokens[j].tag) {
+			assign(elements[i], &tokens[j])
+			i++
+			j++
+			continue
+		}
+
+		skipElements, skipTokens := 0, 0
+		for k := 1; k <= sourceAlignWindow; k++ {
+			if skipElements == 0 && i+k < len(elements) && strings.EqualFold(elements[i+k].Tag, tokens[j].tag) {
+				skipElements = k
+			}
+			if skipTokens == 0 && j+k < len(tokens) && strings.EqualFold(elements[i].Tag, tokens[j+k].tag) {
+				skipTokens = k
+			}
+		}
+
+		switch {
+		case skipElements > 0 && (skipTokens == 0 || skipElements <= skipTokens):
+			i += skipElements
+		case skipTokens > 0:
+			j += skipTokens
+		default:
+			i++
+			j++
+		}
+	}
+}
+
+//
+// DOM: Record the source positions, or the case as written, of the elements
+// parsed from the contents according to the options.
+//
+func (id *DOM) parseSource(nodes []*DOMNode) {
+	elements := make([]*DOMNode, 0, len(nodes))
+	for _, node := range nodes {
+		if pseudoTags[node.Tag] == 0 {
+			elements = append(elements, node)
+		}
+	}
+
+	alignSource(elements, scanSource(id.contents, id.options.PreserveCase), func(node *DOMNode, token *sourceToken) {
+		if id.options.SourcePositions {
+			node.setSource(token.sourceTracker, token.end)
+		}
+		if id.options.PreserveCase {
+			node.setCase(token.name, token.keys)
+		}
+	})
+}
@@ -0,0 +1,51 @@
+// Copyright 2016, Marc Lavergne <mlavergn@gmail.com>. All rights reserved.
+// Use of this source code is governed by the MIT
+// license that can be found in the LICENSE file.
+
+package godom
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestSourcePositions(t *testing.T) {
+	contents := "<html><body>\n<div id=\"a\">\n  <p>one<br>two</p>\n</div><ul><li>x<li>y</ul></body></html>"
+
+	d := NewDOM()
+	d.SetParseOptions(ParseOptions{SourcePositions: true})
+	d.SetContents(contents)
+
+	p := d.Find("p", nil)
+	if len(p) != 1 {
+		d.Dump()
+		t.Fatalf("failed to find node")
+	}
+	if p[0].SourceOffset() != strings.Index(contents, "<p>") {
+		t.Errorf("failed to record offset %d", p[0].SourceOffset())
+	}
+	if line, column := p[0].SourcePosition(); line != 3 || column != 3 {
+		t.Errorf("failed to record position %d:%d", line, column)
+	}
+	if p[0].Source() != "<p>one<br>two</p>" {
+		t.Errorf("failed to slice source [%s]", p[0].Source())
+	}
+
+	li := d.Find("li", nil)
+	if len(li) != 2 || li[1].Source() != "<li>y" {
+		t.Errorf("failed to slice implied end [%s]", li[1].Source())
+	}
+
+	s := NewDOM()
+	s.SetParseOptions(ParseOptions{SourcePositions: true})
+	s.SetContentsFromReader(strings.NewReader(contents))
+	div := s.Find("div", nil)
+	if len(div) != 1 || div[0].SourceOffset() != strings.Index(contents, "<div") {
+		t.Errorf("failed to record stream offset")
+	}
+
+	node := NewDOMNode(0, nil, "div", nil)
+	if node.SourceOffset() != -1 {
+		t.Errorf("failed to report unknown offset")
+	}
+}
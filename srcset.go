@@ -0,0 +1,263 @@
+// Copyright 2016 Marc Lavergne <mlavergn@gmail.com>. All rights reserved.
+// Use of this source code is governed by
+// license that can be found in the LICENSE file.
+
+package godom
+
+import (
+	"strconv"
+	"strings"
+)
+
+// srcsetFontSize pixels of an em or rem in a sizes length
+const srcsetFontSize = 16
+
+// SrcsetCandidate def
+// An image candidate of a srcset attribute, Width is the w descriptor and
+// Density the x descriptor, a candidate without descriptor has a Density of 1
+type SrcsetCandidate struct {
+	URL     string
+	Width   int
+	Density float64
+}
+
+//
+// ParseSrcset : Parse the srcset attribute into its candidates. URLs may
+// contain commas, eg. data: URLs. Candidates with invalid descriptors are
+// dropped.
+//
+func ParseSrcset(srcset string) (result []SrcsetCandidate) {
+	splitSrcset(srcset, func(rawURL string, descriptors []string) {
+		candidate := SrcsetCandidate{URL: rawURL}
+		if candidate.parseDescriptors(descriptors) {
+			result = append(result, candidate)
+		}
+	})
+
+	return result
+}
+
+//
+// splitSrcset : Call fn with the URL and descriptors of each candidate of the
+// srcset attribute.
+//
+func splitSrcset(srcset string, fn func(rawURL string, descriptors []string)) {
+	s := srcset
+	for {
+		s = strings.TrimLeft(s, " \t\n\f\r,")
+		if len(s) == 0 {
+			return
+		}
+
+		end := strings.IndexAny(s, " \t\n\f\r")
+		if end < 0 {
+			end = len(s)
+		}
+		rawURL := s[:end]
+		s = s[end:]
+
+		descriptors := ""
+		if strings.HasSuffix(rawURL, ",") {
+			// a trailing comma ends a candidate without descriptors
+			rawURL = strings.TrimRight(rawURL, ",")
+		} else {
+			// the descriptors run to the next comma outside of parentheses
+			depth := 0
+			end = len(s)
+			for i := 0; i < len(s); i++ {
+				if s[i] == '(' {
+					depth++
+				} else if s[i] == ')' && depth > 0 {
+					depth--
+				} else if s[i] == ',' && depth == 0 {
+					end = i
+					break
+				}
+			}
+			descriptors, s = s[:end], s[end:]
+		}
+
+		fn(rawURL, strings.Fields(descriptors))
+	}
+}
+
+//
+// SrcsetCandidate: Apply the descriptors, false when they are invalid.
+//
+func (id *SrcsetCandidate) parseDescriptors(descriptors []string) bool {
+	height := false
+	for _, descriptor := range descriptors {
+		if len(descriptor) < 2 {
+			return false
+		}
+		value := descriptor[:len(descriptor)-1]
+		switch descriptor[len(descriptor)-1] {
+		case 'w':
+			width, err := strconv.Atoi(value)
+			if err != nil || width <= 0 || id.Width > 0 || id.Density > 0 {
+				return false
+			}
+			id.Width = width
+		case 'x':
+			density, err := strconv.ParseFloat(value, 64)
+			if err != nil || density <= 0 || id.Width > 0 || id.Density > 0 || height {
+				return false
+			}
+			id.Density = density
+		case 'h':
+			// the future-compat height descriptor is ignored
+			h, err := strconv.Atoi(value)
+			if err != nil || h <= 0 || height || id.Density > 0 {
+				return false
+			}
+			height = true
+		default:
+			return false
+		}
+	}
+	if height && id.Width == 0 {
+		// a height requires a width
+		return false
+	}
+	if id.Width == 0 && id.Density == 0 {
+		id.Density = 1
+	}
+
+	return true
+}
+
+//
+// SourceSize : The slot width in pixels selected by the sizes attribute for the
+// viewport width. Media conditions on min-width and max-width are evaluated,
+// lengths in px, em, rem, and vw are supported, and other entries are skipped.
+// The default is the viewport width.
+//
+func SourceSize(sizes string, viewport int) float64 {
+	for _, entry := range strings.Split(sizes, ",") {
+		entry = strings.TrimSpace(entry)
+		condition := ""
+		length := entry
+		if idx := strings.LastIndexByte(entry, ')'); idx >= 0 {
+			condition, length = entry[:idx+1], strings.TrimSpace(entry[idx+1:])
+		}
+
+		size, ok := sizesLength(length, viewport)
+		if !ok {
+			continue
+		}
+		if len(condition) == 0 || sizesCondition(condition, viewport) {
+			return size
+		}
+	}
+
+	return float64(viewport)
+}
+
+//
+// sizesCondition : Evaluate a media condition such as (max-width: 600px) or
+// (min-width: 40em) and (max-width: 60em), unsupported features are false.
+//
+func sizesCondition(condition string, viewport int) bool {
+	condition = strings.ToLower(condition)
+	for _, part := range strings.Split(condition, " and ") {
+		part = strings.Trim(strings.TrimSpace(part), "()")
+		feature, value, found := strings.Cut(part, ":")
+		if !found {
+			return false
+		}
+		width, ok := sizesLength(strings.TrimSpace(value), viewport)
+		if !ok {
+			return false
+		}
+		switch strings.TrimSpace(feature) {
+		case "min-width":
+			if float64(viewport) < width {
+				return false
+			}
+		case "max-width":
+			if float64(viewport) > width {
+				return false
+			}
+		default:
+			return false
+		}
+	}
+
+	return true
+}
+
+//
+// sizesLength : The length in pixels for the viewport width.
+//
+func sizesLength(length string, viewport int) (float64, bool) {
+	units := []struct {
+		suffix string
+		scale  float64
+	}{
+		{"rem", srcsetFontSize}, {"px", 1}, {"em", srcsetFontSize}, {"vw", float64(viewport) / 100},
+	}
+	for _, unit := range units {
+		if strings.HasSuffix(length, unit.suffix) {
+			value, err := strconv.ParseFloat(strings.TrimSuffix(length, unit.suffix), 64)
+			return value * unit.scale, err == nil && value >= 0
+		}
+	}
+
+	return 0, false
+}
+
+//
+// BestSrcsetCandidate : The candidate to display in a slot of the width in
+// pixels on a screen of the pixel density, the smallest candidate at least as
+// dense as the screen, or failing that the densest. Nil without candidates.
+//
+func BestSrcsetCandidate(candidates []SrcsetCandidate, slotWidth float64, density float64) *SrcsetCandidate {
+	var best *SrcsetCandidate
+	bestDensity := 0.0
+	for i := range candidates {
+		candidateDensity := candidates[i].Density
+		if candidates[i].Width > 0 {
+			if slotWidth <= 0 {
+				continue
+			}
+			candidateDensity = float64(candidates[i].Width) / slotWidth
+		}
+
+		switch {
+		case best == nil:
+		case bestDensity < density && candidateDensity > bestDensity:
+		case candidateDensity >= density && candidateDensity < bestDensity:
+		default:
+			continue
+		}
+		best, bestDensity = &candidates[i], candidateDensity
+	}
+
+	return best
+}
+
+//
+// ImageSource : The URL an img or source node displays for the viewport width
+// and pixel density, chosen from its srcset and sizes. As in browsers, src
+// is the 1x candidate unless the srcset has one or uses width descriptors.
+// Empty when the node has no candidate.
+//
+func (id *DOMNode) ImageSource(viewport int, density float64) string {
+	candidates := ParseSrcset(id.Attr("srcset"))
+	src := strings.TrimSpace(id.Attr("src"))
+	for _, candidate := range candidates {
+		if candidate.Width > 0 || candidate.Density == 1 {
+			src = ""
+		}
+	}
+	if len(src) > 0 {
+		candidates = append(candidates, SrcsetCandidate{URL: src, Density: 1})
+	}
+
+	best := BestSrcsetCandidate(candidates, SourceSize(id.Attr("sizes"), viewport), density)
+	if best == nil {
+		return ""
+	}
+
+	return best.URL
+}
@@ -0,0 +1,71 @@
+// Copyright 2016, Marc Lavergne <mlavergn@gmail.com>. All rights reserved.
+// Use of this source code is governed by the MIT
+// license that can be found in the LICENSE file.
+
+package godom
+
+import (
+	"testing"
+)
+
+func TestParseSrcset(t *testing.T) {
+	candidates := ParseSrcset(" a.jpg 480w, b.jpg?x=1,2 800w,c.jpg,, data:image/png;base64,AB== 2x, bad.jpg 1q")
+	expected := []SrcsetCandidate{
+		{URL: "a.jpg", Width: 480},
+		{URL: "b.jpg?x=1,2", Width: 800},
+		{URL: "c.jpg", Density: 1},
+		{URL: "data:image/png;base64,AB==", Density: 2},
+	}
+	if len(candidates) != len(expected) {
+		t.Fatalf("unexpected candidates %+v", candidates)
+	}
+	for i := range expected {
+		if candidates[i] != expected[i] {
+			t.Errorf("unexpected candidate %+v", candidates[i])
+		}
+	}
+}
+
+func TestSourceSize(t *testing.T) {
+	sizes := "(max-width: 600px) 100vw, (min-width: 601px) and (max-width: 60em) 50vw, 400px"
+	checks := map[int]float64{320: 320, 800: 400, 1200: 400}
+	for viewport, expected := range checks {
+		if size := SourceSize(sizes, viewport); size != expected {
+			t.Errorf("unexpected size %f for viewport %d", size, viewport)
+		}
+	}
+	if SourceSize("", 1024) != 1024 {
+		t.Errorf("failed to default to the viewport")
+	}
+}
+
+func TestImageSource(t *testing.T) {
+	d := NewDOM()
+	d.SetContents("<html><body><img src=\"s.jpg\" srcset=\"m.jpg 800w, l.jpg 1600w\" sizes=\"(max-width: 600px) 100vw, 50vw\"><img src=\"x1.jpg\" srcset=\"x2.jpg 2x\"></body></html>")
+	imgs := d.Find("img", nil)
+	if len(imgs) != 2 {
+		d.Dump()
+		t.Fatalf("failed to find images")
+	}
+
+	checks := []struct {
+		node     *DOMNode
+		viewport int
+		density  float64
+		expected string
+	}{
+		{imgs[0], 400, 1, "m.jpg"},
+		{imgs[0], 400, 2, "m.jpg"},
+		{imgs[0], 400, 3, "l.jpg"},
+		{imgs[0], 1600, 1, "m.jpg"},
+		{imgs[0], 1600, 2, "l.jpg"},
+		{imgs[0], 4000, 2, "l.jpg"},
+		{imgs[1], 1024, 1, "x1.jpg"},
+		{imgs[1], 1024, 1.5, "x2.jpg"},
+	}
+	for _, check := range checks {
+		if source := check.node.ImageSource(check.viewport, check.density); source != check.expected {
+			t.Errorf("unexpected source %s for %d at %.1fx", source, check.viewport, check.density)
+		}
+	}
+}
@@ -0,0 +1,58 @@
+// Copyright 2016 Marc Lavergne <mlavergn@gmail.com>. All rights reserved.
+// Use of this source code is governed by
+// license that can be found in the LICENSE file.
+
+package godom
+
+// DOMStats def
+// Counts describing the shape of a document
+type DOMStats struct {
+	// Nodes is the number of elements, NodesByTag the number by tag
+	Nodes      int
+	NodesByTag map[string]int
+	// MaxDepth is the nesting of the deepest element, 1 for the root
+	MaxDepth int
+	// TextBytes is the length of the element text fragments
+	TextBytes  int
+	Attributes int
+	Comments   int
+	Scripts    int
+}
+
+//
+// Stats : The element, attribute, comment, and script counts of the document
+// along with its depth and text length, in a single pass.
+//
+func (id *DOM) Stats() DOMStats {
+	stats := DOMStats{NodesByTag: map[string]int{}}
+	depths := make(map[*DOMNode]int, len(id.document))
+
+	// parents precede their children in document order
+	for _, node := range id.document {
+		if node.Tag == "comment" {
+			stats.Comments++
+			continue
+		}
+		if pseudoTags[node.Tag] == 1 {
+			continue
+		}
+
+		depth := depths[node.Parent] + 1
+		depths[node] = depth
+		if depth > stats.MaxDepth {
+			stats.MaxDepth = depth
+		}
+
+		stats.Nodes++
+		stats.NodesByTag[node.Tag]++
+		stats.Attributes += len(node.Attributes)
+		for _, fragment := range node.TextFragments {
+			stats.TextBytes += len(fragment)
+		}
+		if node.Tag == "script" {
+			stats.Scripts++
+		}
+	}
+
+	return stats
+}
@@ -0,0 +1,33 @@
+// Copyright 2016, Marc Lavergne <mlavergn@gmail.com>. All rights reserved.
+// Use of this source code is governed by the MIT
+// license that can be found in the LICENSE file.
+
+package godom
+
+import (
+	"testing"
+)
+
+func TestStats(t *testing.T) {
+	d := NewDOM()
+	d.SetContents(`<!DOCTYPE html><html><head><script src="a.js"></script><script>var x = 1;</script></head><body><!-- note --><div id="a" class="b"><p>One <b>two</b></p></div></body></html>`)
+
+	stats := d.Stats()
+	if stats.Nodes != 8 || stats.NodesByTag["script"] != 2 || stats.NodesByTag["p"] != 1 {
+		t.Errorf("unexpected node counts %d %v", stats.Nodes, stats.NodesByTag)
+	}
+	if stats.MaxDepth != 5 {
+		t.Errorf("expected depth 5 found %d", stats.MaxDepth)
+	}
+	if stats.TextBytes != len("var x = 1;Onetwo") {
+		t.Errorf("unexpected text bytes %d", stats.TextBytes)
+	}
+	if stats.Attributes != 3 || stats.Comments != 1 || stats.Scripts != 2 {
+		t.Errorf("unexpected counts %+v", stats)
+	}
+
+	empty := NewDOM()
+	if stats := empty.Stats(); stats.Nodes != 0 || stats.MaxDepth != 0 {
+		t.Errorf("unexpected empty stats %+v", stats)
+	}
+}
@@ -0,0 +1,295 @@
+// Copyright 2016 Marc Lavergne <mlavergn@gmail.com>. All rights reserved.
+// Use of this source code is governed by
+// license that can be found in the LICENSE file.
+
+package godom
+
+import (
+	"context"
+	"errors"
+	"golang.org/x/net/html"
+	"io"
+	"strings"
+)
+
+// ErrStopStream returned by a TokenHandler to end StreamParse without error
+var ErrStopStream = errors.New("godom: stop stream")
+
+// TokenHandler interface
+// Receives the tokens of StreamParse, returning an error ends the parse
+type TokenHandler interface {
+	StartTag(tag string, attributes DOMNodeAttributes) error
+	Text(text string) error
+	EndTag(tag string) error
+}
+
+// TokenHandlerFuncs def
+// Adapts functions to a TokenHandler, nil functions ignore their tokens
+type TokenHandlerFuncs struct {
+	StartTagFunc func(tag string, attributes DOMNodeAttributes) error
+	TextFunc     func(text string) error
+	EndTagFunc   func(tag string) error
+}
+
+//
+// StartTag : Call StartTagFunc when set.
+//
+func (id TokenHandlerFuncs) StartTag(tag string, attributes DOMNodeAttributes) error {
+	if id.StartTagFunc == nil {
+		return nil
+	}
+
+	return id.StartTagFunc(tag, attributes)
+}
+
+//
+// Text : Call TextFunc when set.
+//
+func (id TokenHandlerFuncs) Text(text string) error {
+	if id.TextFunc == nil {
+		return nil
+	}
+
+	return id.TextFunc(text)
+}
+
+//
+// EndTag : Call EndTagFunc when set.
+//
+func (id TokenHandlerFuncs) EndTag(tag string) error {
+	if id.EndTagFunc == nil {
+		return nil
+	}
+
+	return id.EndTagFunc(tag)
+}
+
+//
+// StreamParse : Tokenize the html stream into handler callbacks without
+// building a DOM. Tags are lowercased and text is unescaped but untrimmed.
+// Void elements and self-closing tags are ended immediately, end tags are
+// reported as written. Returning ErrStopStream from the handler ends the
+// parse early without error.
+//
+func StreamParse(r io.Reader, handler TokenHandler) error {
+	z := html.NewTokenizer(r)
+
+	for {
+		var err error
+		tokenType := z.Next()
+		switch tokenType {
+		case html.ErrorToken:
+			if z.Err() == io.EOF {
+				return nil
+			}
+			return z.Err()
+		case html.StartTagToken, html.SelfClosingTagToken:
+			token := z.Token()
+			attributes := make(DOMNodeAttributes, len(token.Attr))
+			for _, attr := range token.Attr {
+				attributes[attr.Key] = attr.Val
+			}
+			err = handler.StartTag(token.Data, attributes)
+			if err == nil && (tokenType == html.SelfClosingTagToken || voidTags[token.Data] == 1) {
+				err = handler.EndTag(token.Data)
+			}
+		case html.EndTagToken:
+			name, _ := z.TagName()
+			err = handler.EndTag(string(name))
+		case html.TextToken:
+			err = handler.Text(string(z.Text()))
+		}
+
+		if err == ErrStopStream {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+	}
+}
+
+// voidTags elements which never have content or an end tag
+var voidTags = map[string]int{
+	"area": 1, "base": 1, "br": 1, "col": 1, "embed": 1, "hr": 1, "img": 1, "input": 1,
+	"keygen": 1, "link": 1, "meta": 1, "param": 1, "source": 1, "track": 1, "wbr": 1,
+}
+
+//
+// SetContentsFromReader : tokenize the html stream directly into the DOM.
+// Unlike SetContents, the raw contents are not retained and the tree is built
+// as written, without the implied html/head/body elements of a full parse.
+//
+func (id *DOM) SetContentsFromReader(r io.Reader) error {
+	_, err := id.parseTokens(r, nil)
+	return err
+}
+
+//
+// ParseUntil : tokenize the html contents into the DOM like
+// SetContentsFromReader, stopping as soon as an element of type tag with the
+// specified attributes has been closed. Returns the element, or nil when the
+// contents hold no match.
+//
+func (id *DOM) ParseUntil(contents string, tag string, attributes DOMNodeAttributes) (*DOMNode, error) {
+	tag = strings.ToLower(tag)
+	id.contents = contents
+
+	return id.parseTokens(strings.NewReader(contents), func(node *DOMNode) bool {
+		return node.Tag == tag && node.matchAttributes(attributes)
+	})
+}
+
+//
+// ParseUntilSelector : ParseUntil the first element matching selector is closed.
+// Selectors relying on following siblings of the element can't match.
+//
+func (id *DOM) ParseUntilSelector(contents string, selector *Selector) (*DOMNode, error) {
+	id.contents = contents
+
+	return id.parseTokens(strings.NewReader(contents), selector.Match)
+}
+
+//
+// DOM: Build the tree from the tokens, stopping once done returns true for
+// a closed element. Returns the element done was satisfied by.
+//
+func (id *DOM) parseTokens(r io.Reader, done func(node *DOMNode) bool) (*DOMNode, error) {
+	if id.frozen {
+		return nil, ErrFrozen
+	}
+	z := html.NewTokenizer(id.entityReader(r))
+	limiter := &parseLimiter{ctx: context.Background(), options: id.options}
+	first, diagnostics := len(id.document), len(id.diagnostics)
+	// mirror the parse tree by leading with the document node
+	id.addNode(nil, "document", DOMNodeAttributes{})
+
+	// open element stack, the top is the parent of the next node
+	stack := []*DOMNode{}
+	parent := func() *DOMNode {
+		if len(stack) == 0 {
+			return nil
+		}
+		return stack[len(stack)-1]
+	}
+
+	// the skipped element being passed over and its nesting depth
+	skipTag := ""
+	skipDepth := 0
+
+	tracker := sourceTracker{line: 1, column: 1}
+	for {
+		tokenType := z.Next()
+		start := tracker
+		if id.options.SourcePositions {
+			tracker.advance([]byte(id.entities(string(z.Raw()), false)))
+		}
+
+		if skipDepth > 0 && tokenType != html.ErrorToken {
+			name, _ := z.TagName()
+			switch {
+			case tokenType == html.StartTagToken && string(name) == skipTag:
+				skipDepth++
+			case tokenType == html.EndTagToken && string(name) == skipTag:
+				skipDepth--
+			}
+			continue
+		}
+
+		switch tokenType {
+		case html.ErrorToken:
+			if z.Err() != io.EOF {
+				return nil, z.Err()
+			}
+			// the open elements are closed by the end of the contents
+			id.diagnoseUnclosed(stack)
+			for i := len(stack) - 1; i >= 0; i-- {
+				if done != nil && done(stack[i]) {
+					return stack[i], nil
+				}
+			}
+			return nil, nil
+		case html.StartTagToken, html.SelfClosingTagToken:
+			raw := ""
+			if id.options.PreserveCase {
+				// the token is lowercased once read
+				raw = string(z.Raw())
+			}
+			token := z.Token()
+			if id.tags.skip[token.Data] == 1 {
+				id.diagnose(DiagnosticSkipped, parent(), token.Data)
+				if tokenType == html.StartTagToken && voidTags[token.Data] == 0 {
+					skipTag = token.Data
+					skipDepth = 1
+				}
+				continue
+			}
+			if !limiter.element(len(stack) + 1) {
+				id.truncate(first, diagnostics)
+				id.Logger().Warn("godom: parse aborted", "error", limiter.err)
+				return nil, limiter.err
+			}
+			domNode := id.addElementNode(parent(), token.Data, id.parseHTMLAttributes(token.Attr))
+			if id.options.SourcePositions {
+				domNode.setSource(start, tracker.offset)
+			}
+			if id.options.PreserveCase {
+				domNode.setCase(rawTagCase(raw))
+			}
+			if tokenType == html.StartTagToken && voidTags[domNode.Tag] == 0 {
+				stack = append(stack, domNode)
+			} else if done != nil && done(domNode) {
+				return domNode, nil
+			}
+		case html.EndTagToken:
+			token := z.Token()
+			tag := strings.ToLower(token.Data)
+			// unwind to the matching open element, stray end tags are ignored
+			matched := false
+			for i := len(stack) - 1; i >= 0; i-- {
+				if stack[i].Tag == tag {
+					matched = true
+					id.diagnoseUnclosed(stack[i+1:])
+					if id.options.SourcePositions {
+						// elements left open are closed by the end tag
+						for _, open := range stack[i+1:] {
+							open.sourceEnd = start.offset
+						}
+						stack[i].sourceEnd = tracker.offset
+					}
+					closed := stack[i:]
+					stack = stack[:i]
+					for j := len(closed) - 1; j >= 0; j-- {
+						if done != nil && done(closed[j]) {
+							return closed[j], nil
+						}
+					}
+					break
+				}
+			}
+			if !matched {
+				id.diagnose(DiagnosticStrayEndTag, parent(), tag)
+			}
+		case html.TextToken:
+			if current := parent(); current != nil && !id.discardText(current.Tag) {
+				text := parseText(current, string(z.Text()))
+				if !limiter.text(len(text)) {
+					id.truncate(first, diagnostics)
+					id.Logger().Warn("godom: parse aborted", "error", limiter.err)
+					return nil, limiter.err
+				}
+				current.appendText(id.entities(text, scriptTags[current.Tag] == 0))
+			}
+		case html.CommentToken:
+			comment := id.addNode(parent(), "comment", DOMNodeAttributes{})
+			comment.appendText(id.entities(string(z.Text()), false))
+			if id.options.IndexComments {
+				id.indexNode(comment)
+			}
+		case html.DoctypeToken:
+			name, attrs := parseDoctype(string(z.Text()))
+			doctype := id.addNode(parent(), "doctype", attrs)
+			doctype.appendText(name)
+		}
+	}
+}
@@ -0,0 +1,118 @@
+// Copyright 2016, Marc Lavergne <mlavergn@gmail.com>. All rights reserved.
+// Use of this source code is governed by the MIT
+// license that can be found in the LICENSE file.
+
+package godom
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestSetContentsFromReader(t *testing.T) {
+	d := NewDOM()
+	err := d.SetContentsFromReader(strings.NewReader("<html><form action='/foo'><select id='s'><option id='1'>Foo</option><option id='2'>Bar<br></option></select></form><div id=\"a\">Hello <strong>there</strong> world</div></html>"))
+	if err != nil {
+		t.Errorf("failed to parse reader %s", err)
+	}
+
+	p := d.Find("select", map[string]string{"id": "s"})
+	if len(p) != 1 {
+		d.Dump()
+		t.Fatalf("failed to find SELECT node")
+	}
+	c := d.ChildFind(p[0], "option", map[string]string{"id": "2"})
+	if len(c) != 1 || c[0].Text() != "Bar" {
+		d.Dump()
+		t.Errorf("failed to find OPTION node")
+	}
+
+	p = d.Find("div", map[string]string{"id": "a"})
+	if len(p) != 1 || p[0].Text() != "Hello world" {
+		d.Dump()
+		t.Errorf("failed to recombine text")
+	}
+}
+
+func TestSetContentsFromReaderFile(t *testing.T) {
+	contents := loadData(t, "test_b.html")
+
+	d := NewDOM()
+	err := d.SetContentsFromReader(strings.NewReader(contents))
+	if err != nil {
+		t.Errorf("failed to parse reader %s", err)
+	}
+
+	node := d.Find("input", map[string]string{"name": "user"})
+	if len(node) != 1 || node[0].Attr("value") != "AAAAAAAA-AAAA-AAAA-AAAA-AAAAAAAAAAAA@private" {
+		d.Dump()
+		t.Error("failed to parse arguments")
+	}
+	if d.ContentLength() != 0 {
+		t.Errorf("ContentLength %d vs expected %d", d.ContentLength(), 0)
+	}
+}
+
+func TestStreamParse(t *testing.T) {
+	titles := []string{}
+	inTitle := false
+	ends := 0
+	handler := TokenHandlerFuncs{
+		StartTagFunc: func(tag string, attributes DOMNodeAttributes) error {
+			inTitle = tag == "h2" && attributes["class"] == "title"
+			return nil
+		},
+		TextFunc: func(text string) error {
+			if inTitle {
+				titles = append(titles, text)
+			}
+			return nil
+		},
+		EndTagFunc: func(tag string) error {
+			ends++
+			inTitle = false
+			if len(titles) == 2 {
+				return ErrStopStream
+			}
+			return nil
+		},
+	}
+
+	err := StreamParse(strings.NewReader("<div><h2 class=\"title\">One &amp; two</h2><br><h2 class=\"title\">Three</h2><h2 class=\"title\">Four</h2></div>"), handler)
+	if err != nil {
+		t.Fatalf("failed to parse stream %v", err)
+	}
+	if len(titles) != 2 || titles[0] != "One & two" || titles[1] != "Three" {
+		t.Errorf("failed to collect text %v", titles)
+	}
+	if ends != 3 {
+		t.Errorf("failed to end void element or stop early [%d]", ends)
+	}
+}
+
+func TestParseUntil(t *testing.T) {
+	contents := "<html><head><title>Page</title><meta name=\"description\" content=\"about\"></head><body><div id=\"a\">skip</div></body></html>"
+
+	d := NewDOM()
+	node, err := d.ParseUntil(contents, "title", nil)
+	if err != nil || node == nil || node.Text() != "Page" {
+		d.Dump()
+		t.Fatalf("failed to parse until title")
+	}
+	if len(d.Find("meta", nil)) != 0 {
+		t.Errorf("failed to stop parsing")
+	}
+
+	d = NewDOM()
+	node, err = d.ParseUntilSelector(contents, MustParseSelector("meta[name=description]"))
+	if err != nil || node == nil || node.Attr("content") != "about" || len(d.Find("body", nil)) != 0 {
+		d.Dump()
+		t.Errorf("failed to parse until selector")
+	}
+
+	d = NewDOM()
+	node, err = d.ParseUntil(contents, "span", nil)
+	if err != nil || node != nil || len(d.Find("div", nil)) != 1 {
+		t.Errorf("failed to parse without a match")
+	}
+}
@@ -0,0 +1,265 @@
+// Copyright 2016 Marc Lavergne <mlavergn@gmail.com>. All rights reserved.
+// Use of this source code is governed by
+// license that can be found in the LICENSE file.
+
+package godom
+
+import (
+	"regexp"
+	"strings"
+)
+
+// styleComments CSS comments removed before parsing
+var styleComments = regexp.MustCompile(`(?s)/\*.*?\*/`)
+
+// StyleRule def
+// A rule of a stylesheet with a single selector, a rule with a selector list
+// is split into one rule per selector
+type StyleRule struct {
+	Selector     *Selector
+	Declarations []StyleDeclaration
+	// specificity is the id, class, and tag counts of the selector
+	specificity [3]int
+}
+
+// StyleDeclaration def
+// A property and its value, the property lowercased
+type StyleDeclaration struct {
+	Property  string
+	Value     string
+	Important bool
+}
+
+//
+// ParseStylesheet : Parse the CSS into its rules. The rules of @media blocks
+// apply whatever the media, other at-rules are skipped, as are rules whose
+// selector is not supported by ParseSelector, eg. pseudo-classes.
+//
+func ParseStylesheet(css string) (result []*StyleRule) {
+	css = styleComments.ReplaceAllString(css, " ")
+	for len(css) > 0 {
+		open := strings.IndexByte(css, '{')
+		if open < 0 {
+			break
+		}
+		prelude := strings.TrimSpace(css[:open])
+
+		// a statement at-rule such as @import ends at its semicolon
+		if strings.HasPrefix(prelude, "@") && strings.Contains(prelude, ";") {
+			css = css[strings.IndexByte(css, ';')+1:]
+			continue
+		}
+
+		end := styleBlockEnd(css, open)
+		body := css[open+1 : end]
+		css = css[min(end+1, len(css)):]
+
+		switch {
+		case strings.HasPrefix(prelude, "@media"), strings.HasPrefix(prelude, "@supports"):
+			result = append(result, ParseStylesheet(body)...)
+		case strings.HasPrefix(prelude, "@"):
+		default:
+			declarations := ParseStyleDeclarations(body)
+			for _, group := range splitSelectorGroups(prelude) {
+				selector, err := ParseSelector(group)
+				if err != nil {
+					continue
+				}
+				result = append(result, &StyleRule{
+					Selector:     selector,
+					Declarations: declarations,
+					specificity:  selectorSpecificity(selector.groups[0]),
+				})
+			}
+		}
+	}
+
+	return result
+}
+
+//
+// styleBlockEnd : The index of the brace closing the block opened at open,
+// the end of the CSS when it is unterminated.
+//
+func styleBlockEnd(css string, open int) int {
+	depth := 0
+	for i := open; i < len(css); i++ {
+		switch css[i] {
+		case '{':
+			depth++
+		case '}':
+			depth--
+			if depth == 0 {
+				return i
+			}
+		}
+	}
+
+	return len(css)
+}
+
+//
+// ParseStyleDeclarations : Parse a declaration block or a style attribute.
+//
+func ParseStyleDeclarations(block string) (result []StyleDeclaration) {
+	for _, declaration := range strings.Split(block, ";") {
+		property, value, found := strings.Cut(declaration, ":")
+		property = strings.ToLower(strings.TrimSpace(property))
+		if !found || len(property) == 0 {
+			continue
+		}
+
+		value = strings.TrimSpace(value)
+		important := false
+		if idx := strings.LastIndexByte(value, '!'); idx >= 0 && strings.EqualFold(strings.TrimSpace(value[idx+1:]), "important") {
+			value = strings.TrimSpace(value[:idx])
+			important = true
+		}
+		result = append(result, StyleDeclaration{Property: property, Value: value, Important: important})
+	}
+
+	return result
+}
+
+//
+// selectorSpecificity : The id, class and attribute, and tag counts of the steps.
+//
+func selectorSpecificity(steps []selectorStep) (result [3]int) {
+	for _, step := range steps {
+		for _, attr := range step.attrs {
+			if attr.key == "id" && attr.op == "=" {
+				result[0]++
+			} else {
+				result[1]++
+			}
+		}
+		if len(step.tag) > 0 {
+			result[2]++
+		}
+	}
+
+	return result
+}
+
+//
+// AddStylesheet : Add the rules of an external stylesheet, eg. one fetched from
+// a <link rel="stylesheet">. Added rules precede the <style> elements in the
+// cascade.
+//
+func (id *DOM) AddStylesheet(css string) error {
+	if id.frozen {
+		return ErrFrozen
+	}
+	id.externalStyles = append(id.externalStyles, ParseStylesheet(css)...)
+	id.styles = nil
+
+	return nil
+}
+
+//
+// DOM: The rules of the added stylesheets followed by those of the <style>
+// elements, parsed once until the document changes.
+//
+func (id *DOM) styleRules() []*StyleRule {
+	if id.styles == nil && !id.frozen {
+		id.styles = append([]*StyleRule{}, id.externalStyles...)
+		for _, node := range id.Find("style", nil) {
+			id.styles = append(id.styles, ParseStylesheet(node.Text())...)
+		}
+	}
+
+	return id.styles
+}
+
+//
+// Style : The value of the property for the node from its style attribute and
+// the rules matching it, by importance, specificity, then order. Properties
+// are not inherited.
+//
+func (id *DOMNode) Style(property string) string {
+	property = strings.ToLower(property)
+
+	value := ""
+	important := false
+	specificity := [3]int{-1, -1, -1}
+	if id.dom != nil {
+		for _, rule := range id.dom.styleRules() {
+			for _, declaration := range rule.Declarations {
+				if declaration.Property != property || (important && !declaration.Important) {
+					continue
+				}
+				if declaration.Important == important && styleLess(rule.specificity, specificity) {
+					continue
+				}
+				if rule.Selector.Match(id) {
+					value, important, specificity = declaration.Value, declaration.Important, rule.specificity
+				}
+			}
+		}
+	}
+
+	// the style attribute outranks the rules of the same importance
+	for _, declaration := range ParseStyleDeclarations(id.Attr("style")) {
+		if declaration.Property == property && (declaration.Important || !important) {
+			value, important = declaration.Value, declaration.Important
+		}
+	}
+
+	return value
+}
+
+//
+// styleLess : Is specificity a lower than b?
+//
+func styleLess(a [3]int, b [3]int) bool {
+	for i := range a {
+		if a[i] != b[i] {
+			return a[i] < b[i]
+		}
+	}
+
+	return false
+}
+
+//
+// IsHidden : Is the node invisible, by the hidden attribute, a hidden input,
+// or display: none on the node or an ancestor, or the visibility: hidden it
+// inherits? Honeypot form fields are commonly hidden this way.
+//
+func (id *DOMNode) IsHidden() bool {
+	if id.Tag == "input" && strings.EqualFold(strings.TrimSpace(id.Attr("type")), "hidden") {
+		return true
+	}
+
+	visibility := ""
+	for node := id; node != nil; node = node.Parent {
+		if node.HasAttr("hidden") || strings.EqualFold(node.Style("display"), "none") {
+			return true
+		}
+		// the nearest visibility is inherited
+		if len(visibility) == 0 {
+			visibility = strings.ToLower(node.Style("visibility"))
+		}
+	}
+
+	return visibility == "hidden" || visibility == "collapse"
+}
+
+//
+// MatchesRule : Does a rule of the stylesheets written with the selector,
+// eg. ".honeypot", apply to the node?
+//
+func (id *DOMNode) MatchesRule(selector string) bool {
+	if id.dom == nil {
+		return false
+	}
+
+	selector = strings.Join(strings.Fields(selector), " ")
+	for _, rule := range id.dom.styleRules() {
+		if strings.Join(strings.Fields(rule.Selector.String()), " ") == selector && rule.Selector.Match(id) {
+			return true
+		}
+	}
+
+	return false
+}
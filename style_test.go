@@ -0,0 +1,58 @@
+// Copyright 2016, Marc Lavergne <mlavergn@gmail.com>. All rights reserved.
+// Use of this source code is governed by the MIT
+// license that can be found in the LICENSE file.
+
+package godom
+
+import (
+	"testing"
+)
+
+func TestParseStylesheet(t *testing.T) {
+	rules := ParseStylesheet("/* x */ @import url(a.css); h1, .a > p { color: red; margin : 0 !important } @media (max-width: 10px) { #b { display: none } } a:hover { color: blue } @font-face { font-family: x }")
+	if len(rules) != 3 {
+		t.Fatalf("unexpected rule count %d", len(rules))
+	}
+	if rules[1].Selector.String() != " .a > p" || rules[1].specificity != [3]int{0, 1, 1} {
+		t.Errorf("unexpected rule %s %v", rules[1].Selector, rules[1].specificity)
+	}
+	if declarations := rules[0].Declarations; len(declarations) != 2 || declarations[1] != (StyleDeclaration{"margin", "0", true}) {
+		t.Errorf("unexpected declarations %+v", declarations)
+	}
+	if rules[2].specificity != [3]int{1, 0, 0} {
+		t.Errorf("failed to parse @media rule")
+	}
+}
+
+func TestIsHidden(t *testing.T) {
+	d := NewDOM()
+	d.SetContents(`<html><head><style>.hp { display: none } .ghost { visibility: hidden } #shown { display: block } p.shown { display: none }</style></head><body><form>` +
+		`<input name="a"><div class="hp"><input name="b"></div><input name="c" type="hidden"><input name="d" style="display:none">` +
+		`<div class="ghost"><input name="e"><input name="f" style="visibility: visible"></div><input name="g" hidden>` +
+		`<p id="shown" class="shown">x</p></form></body></html>`)
+
+	hidden := map[string]bool{"a": false, "b": true, "c": true, "d": true, "e": true, "f": false, "g": true}
+	for _, input := range d.Find("input", nil) {
+		if input.IsHidden() != hidden[input.Attr("name")] {
+			t.Errorf("unexpected visibility of %s", input.Attr("name"))
+		}
+	}
+
+	p := d.Find("p", nil)[0]
+	if p.Style("display") != "block" || p.IsHidden() {
+		t.Errorf("failed to apply specificity [%s]", p.Style("display"))
+	}
+	if !d.Find("div", nil)[0].MatchesRule(".hp") {
+		t.Errorf("failed to match rule")
+	}
+	if d.Find("input", nil)[1].MatchesRule(".hp") || d.Find("div", nil)[0].MatchesRule("div") {
+		t.Errorf("unexpected rule match")
+	}
+
+	if err := d.AddStylesheet("input[name=a] { display: none !important }"); err != nil {
+		t.Fatalf("failed to add stylesheet [%v]", err)
+	}
+	if !d.Find("input", nil)[0].IsHidden() {
+		t.Errorf("failed to apply external stylesheet")
+	}
+}
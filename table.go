@@ -0,0 +1,234 @@
+// Copyright 2016 Marc Lavergne <mlavergn@gmail.com>. All rights reserved.
+// Use of this source code is governed by
+// license that can be found in the LICENSE file.
+
+package godom
+
+import (
+	"strconv"
+	"strings"
+)
+
+// Table def
+// The text of a <table> split into the header and body rows
+type Table struct {
+	Node   *DOMNode
+	Header []string
+	Rows   [][]string
+}
+
+//
+// Tables : Extract every table in the document, including nested tables.
+//
+func (id *DOM) Tables() (result []*Table) {
+	for _, node := range id.Find("table", nil) {
+		result = append(result, node.AsTable())
+	}
+
+	return result
+}
+
+//
+// NormalizedTables : Extract every table in the document with spans expanded.
+//
+func (id *DOM) NormalizedTables() (result []*Table) {
+	for _, node := range id.Find("table", nil) {
+		result = append(result, node.AsNormalizedTable())
+	}
+
+	return result
+}
+
+//
+// AsTable : Extract the text of the table node into header and body rows.
+// The header is the first row of <thead>, or a leading row made up of <th>
+// cells when there is no <thead>. Returns nil when the node is not a table.
+//
+func (id *DOMNode) AsTable() *Table {
+	return id.asTable(false)
+}
+
+//
+// AsNormalizedTable : Extract the table like AsTable, expanding colspan and
+// rowspan so that the text of a spanning cell fills every slot it covers and
+// every row has the same number of cells.
+//
+func (id *DOMNode) AsNormalizedTable() *Table {
+	return id.asTable(true)
+}
+
+//
+// DOMNode: Extract the table, optionally expanding spans into a grid.
+//
+func (id *DOMNode) asTable(normalize bool) *Table {
+	if id.Tag != "table" {
+		return nil
+	}
+
+	rows := id.tableRows()
+	var grid [][]string
+	if normalize {
+		grid = tableGrid(rows)
+	} else {
+		for _, row := range rows {
+			cells := []string{}
+			for _, cell := range row.tableCells() {
+				cells = append(cells, cell.cellText())
+			}
+			grid = append(grid, cells)
+		}
+	}
+
+	result := &Table{Node: id, Rows: [][]string{}}
+	for i, row := range rows {
+		if result.Header == nil && len(result.Rows) == 0 && row.isHeaderRow() {
+			result.Header = grid[i]
+		} else if row.Parent.Tag != "thead" {
+			result.Rows = append(result.Rows, grid[i])
+		}
+	}
+
+	return result
+}
+
+//
+// tableGrid : Lay out the cells of the rows into a rectangular grid.
+//
+func tableGrid(rows []*DOMNode) [][]string {
+	// maxSpan guards against absurd span values
+	const maxSpan = 1000
+
+	grid := make([][]string, len(rows))
+	filled := make([][]bool, len(rows))
+	place := func(r int, c int, text string) {
+		for len(grid[r]) <= c {
+			grid[r] = append(grid[r], "")
+			filled[r] = append(filled[r], false)
+		}
+		grid[r][c] = text
+		filled[r][c] = true
+	}
+
+	groupEnd := len(rows)
+	for r, row := range rows {
+		// rows of the same group, eg. a tbody, follow each other
+		if r == 0 || row.Parent != rows[r-1].Parent {
+			groupEnd = r + 1
+			for groupEnd < len(rows) && rows[groupEnd].Parent == row.Parent {
+				groupEnd++
+			}
+		}
+
+		c := 0
+		for _, cell := range row.tableCells() {
+			// skip slots covered by rowspans from earlier rows
+			for c < len(filled[r]) && filled[r][c] {
+				c++
+			}
+
+			// a colspan of zero is one, rowspans stay within the row group
+			// and zero spans its remaining rows
+			colspan := max(cell.spanAttr("colspan", 1, maxSpan), 1)
+			rowspan := cell.spanAttr("rowspan", 1, maxSpan)
+			if rowspan == 0 || r+rowspan > groupEnd {
+				rowspan = groupEnd - r
+			}
+
+			text := cell.cellText()
+			for i := 0; i < rowspan; i++ {
+				for j := 0; j < colspan; j++ {
+					place(r+i, c+j, text)
+				}
+			}
+			c += colspan
+		}
+	}
+
+	// pad to a rectangle
+	width := 0
+	for _, cells := range grid {
+		if len(cells) > width {
+			width = len(cells)
+		}
+	}
+	for r := range grid {
+		for len(grid[r]) < width {
+			grid[r] = append(grid[r], "")
+		}
+	}
+
+	return grid
+}
+
+//
+// DOMNode: The trimmed reader text of a cell.
+//
+func (id *DOMNode) cellText() string {
+	return strings.TrimSpace(id.ReaderText())
+}
+
+//
+// DOMNode: Parse a span attribute, falling back to def when invalid.
+//
+func (id *DOMNode) spanAttr(key string, def int, max int) int {
+	val, err := strconv.Atoi(strings.TrimSpace(id.Attr(key)))
+	if err != nil || val < 0 {
+		return def
+	}
+	if val > max {
+		return max
+	}
+
+	return val
+}
+
+//
+// DOMNode: The rows of the table in document order, excluding nested tables.
+//
+func (id *DOMNode) tableRows() (result []*DOMNode) {
+	for _, child := range id.Children {
+		switch child.Tag {
+		case "tr":
+			result = append(result, child)
+		case "thead", "tbody", "tfoot":
+			for _, row := range child.Children {
+				if row.Tag == "tr" {
+					result = append(result, row)
+				}
+			}
+		}
+	}
+
+	return result
+}
+
+//
+// DOMNode: The td and th cells of the row.
+//
+func (id *DOMNode) tableCells() (result []*DOMNode) {
+	for _, child := range id.Children {
+		if child.Tag == "td" || child.Tag == "th" {
+			result = append(result, child)
+		}
+	}
+
+	return result
+}
+
+//
+// DOMNode: Is the row part of the table header?
+//
+func (id *DOMNode) isHeaderRow() bool {
+	if id.Parent != nil && id.Parent.Tag == "thead" {
+		return true
+	}
+
+	cells := id.tableCells()
+	for _, cell := range cells {
+		if cell.Tag != "th" {
+			return false
+		}
+	}
+
+	return len(cells) > 0
+}
@@ -0,0 +1,83 @@
+// Copyright 2016, Marc Lavergne <mlavergn@gmail.com>. All rights reserved.
+// Use of this source code is governed by the MIT
+// license that can be found in the LICENSE file.
+
+package godom
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestTables(t *testing.T) {
+	d := NewDOM()
+	d.SetContents(`<html><body>
+<table id="a">
+  <thead><tr><th>Name</th><th>Price</th></tr></thead>
+  <tbody>
+    <tr><td><a href="/w">Widget</a></td><td>$<b>10</b></td></tr>
+    <tr><td>Gadget</td><td><table><tr><td>nested</td></tr></table></td></tr>
+  </tbody>
+</table>
+<table id="b"><tr><th>Key</th><td>Value</td></tr></table>
+</body></html>`)
+
+	tables := d.Tables()
+	if len(tables) != 3 {
+		d.Dump()
+		t.Fatalf("failed to find tables %d", len(tables))
+	}
+
+	table := tables[0]
+	if fmt.Sprint(table.Header) != "[Name Price]" {
+		t.Errorf("failed to extract header %v", table.Header)
+	}
+	if fmt.Sprint(table.Rows) != "[[Widget $ 10] [Gadget nested]]" {
+		t.Errorf("failed to extract rows %v", table.Rows)
+	}
+
+	table = d.Find("table", DOMNodeAttributes{"id": "b"})[0].AsTable()
+	if table.Header != nil || fmt.Sprint(table.Rows) != "[[Key Value]]" {
+		t.Errorf("failed to extract headerless table %v %v", table.Header, table.Rows)
+	}
+
+	if d.RootNode().AsTable() != nil {
+		t.Errorf("failed to reject non-table")
+	}
+}
+
+func TestNormalizedTable(t *testing.T) {
+	d := NewDOM()
+	d.SetContents(`<html><body><table>
+<tr><th rowspan="2">Region</th><th colspan="2">Sales</th></tr>
+<tr><th>Q1</th><th>Q2</th></tr>
+<tr><td>North</td><td colspan="2">10</td></tr>
+<tr><td rowspan="2">South</td><td>5</td><td>6</td></tr>
+<tr><td>7</td></tr>
+</table></body></html>`)
+
+	tables := d.NormalizedTables()
+	if len(tables) != 1 {
+		d.Dump()
+		t.Fatalf("failed to find table")
+	}
+
+	table := tables[0]
+	if fmt.Sprint(table.Header) != "[Region Sales Sales]" {
+		t.Errorf("failed to expand header %v", table.Header)
+	}
+	expected := "[[Region Q1 Q2] [North 10 10] [South 5 6] [South 7 ]]"
+	if fmt.Sprint(table.Rows) != expected {
+		t.Errorf("failed to expand rows %v", table.Rows)
+	}
+
+	d = NewDOM()
+	d.SetContents(`<html><body><table>
+<tbody><tr><td rowspan="0">A</td><td colspan="0">B</td></tr><tr><td>C</td></tr></tbody>
+<tbody><tr><td rowspan="5">D</td><td>E</td></tr></tbody>
+</table></body></html>`)
+	expected = "[[A B] [A C] [D E]]"
+	if rows := d.NormalizedTables()[0].Rows; fmt.Sprint(rows) != expected {
+		t.Errorf("expected the spans to stay within their row groups, got %v", rows)
+	}
+}
@@ -0,0 +1,223 @@
+// Copyright 2016 Marc Lavergne <mlavergn@gmail.com>. All rights reserved.
+// Use of this source code is governed by
+// license that can be found in the LICENSE file.
+
+package godom
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// fillScope def
+// The data in scope while filling, innermost first
+type fillScope struct {
+	value  interface{}
+	parent *fillScope
+}
+
+//
+// Fill : A filled copy of the DOM, which is left unchanged so a template may be
+// parsed once and filled many times. A node marked data-slot="name" takes the
+// text of the named value, or is repeated for each item of a slice with the
+// item in scope, kept with a map in scope, or removed when the value is false
+// or nil. A node marked data-bind="href:url; title:name" takes the attributes
+// of the named values, which are removed when false or nil and set empty when
+// true. Names are looked up from the innermost scope outwards, dotted names
+// descend into maps, and "." names the item in scope. Marked nodes with
+// missing names are left unchanged, the marks are removed from the copy.
+//
+func (id *DOM) Fill(data map[string]interface{}) (*DOM, error) {
+	result := id.Clone()
+	scope := &fillScope{value: data}
+
+	var roots []*DOMNode
+	for _, node := range result.document {
+		if node.Parent == nil && pseudoTags[node.Tag] == 0 {
+			roots = append(roots, node)
+		}
+	}
+	for _, node := range roots {
+		if err := fillNode(node, scope); err != nil {
+			return nil, err
+		}
+	}
+
+	return result, nil
+}
+
+//
+// fillNode : Fill the node and its descendants from the scope.
+//
+func fillNode(node *DOMNode, scope *fillScope) error {
+	if node.HasAttr("data-slot") {
+		name := strings.TrimSpace(node.Attr("data-slot"))
+		node.RemoveAttr("data-slot")
+		if val, ok := scope.lookup(name); ok {
+			return fillSlot(node, scope, val)
+		}
+	}
+
+	if err := fillBind(node, scope); err != nil {
+		return err
+	}
+
+	return fillChildren(node, scope)
+}
+
+//
+// fillSlot : Fill the node from the slot value.
+//
+func fillSlot(node *DOMNode, scope *fillScope, val interface{}) error {
+	v := reflect.ValueOf(val)
+	switch {
+	case val == nil || val == false:
+		if node.Parent != nil {
+			return node.Parent.RemoveChild(node)
+		}
+		return nil
+	case val == true:
+	case v.Kind() == reflect.Map:
+		scope = &fillScope{value: val, parent: scope}
+	case (v.Kind() == reflect.Slice || v.Kind() == reflect.Array) && v.Type().Elem().Kind() != reflect.Uint8:
+		return fillRepeat(node, scope, v)
+	default:
+		if err := fillBind(node, scope); err != nil {
+			return err
+		}
+		return node.SetText(fmt.Sprint(val))
+	}
+
+	if err := fillBind(node, scope); err != nil {
+		return err
+	}
+
+	return fillChildren(node, scope)
+}
+
+//
+// fillRepeat : Replace the node with a filled copy for each item of the slice,
+// items which are not maps fill the text of their copy.
+//
+func fillRepeat(node *DOMNode, scope *fillScope, items reflect.Value) error {
+	parent := node.Parent
+	itemNodes := make([]*DOMNode, 0, items.Len())
+	for i := 0; i < items.Len(); i++ {
+		item := items.Index(i).Interface()
+		itemNode := node.clone()
+		itemScope := &fillScope{value: item, parent: scope}
+		if err := fillSlot(itemNode, itemScope, item); err != nil {
+			return err
+		}
+		itemNodes = append(itemNodes, itemNode)
+	}
+
+	if parent == nil {
+		return nil
+	}
+
+	// splice the copies in together to update the DOM once
+	if err := parent.insertDetached(parent.childPosition(node), itemNodes); err != nil {
+		return err
+	}
+
+	return parent.RemoveChild(node)
+}
+
+//
+// fillChildren : Fill the children of the node from the scope.
+//
+func fillChildren(node *DOMNode, scope *fillScope) error {
+	// filling may replace children, so work from a copy
+	children := append([]*DOMNode(nil), node.Children...)
+	for _, child := range children {
+		if err := fillNode(child, scope); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+//
+// fillBind : Set the attributes named by the data-bind of the node.
+//
+func fillBind(node *DOMNode, scope *fillScope) error {
+	if !node.HasAttr("data-bind") {
+		return nil
+	}
+	bind := node.Attr("data-bind")
+	node.RemoveAttr("data-bind")
+
+	for _, pair := range strings.FieldsFunc(bind, func(r rune) bool { return r == ';' || r == ',' }) {
+		colon := strings.IndexByte(pair, ':')
+		if colon < 0 {
+			return fmt.Errorf("godom: invalid data-bind %q", bind)
+		}
+		key := strings.TrimSpace(pair[:colon])
+		name := strings.TrimSpace(pair[colon+1:])
+		if len(key) == 0 || len(name) == 0 {
+			return fmt.Errorf("godom: invalid data-bind %q", bind)
+		}
+
+		val, ok := scope.lookup(name)
+		switch {
+		case !ok:
+		case val == nil || val == false:
+			node.RemoveAttr(key)
+		case val == true:
+			node.SetAttr(key, "")
+		default:
+			node.SetAttr(key, fmt.Sprint(val))
+		}
+	}
+
+	return nil
+}
+
+//
+// fillScope: The value of the name, searching outwards for its first segment.
+//
+func (id *fillScope) lookup(name string) (interface{}, bool) {
+	if name == "." {
+		return id.value, true
+	}
+
+	segments := strings.Split(name, ".")
+	for scope := id; scope != nil; scope = scope.parent {
+		val, ok := fillField(scope.value, segments[0])
+		if !ok {
+			continue
+		}
+		for _, segment := range segments[1:] {
+			if val, ok = fillField(val, segment); !ok {
+				return nil, false
+			}
+		}
+		return val, true
+	}
+
+	return nil, false
+}
+
+//
+// fillField : The value of the key in a map with string keys.
+//
+func fillField(data interface{}, key string) (interface{}, bool) {
+	if m, ok := data.(map[string]interface{}); ok {
+		val, ok := m[key]
+		return val, ok
+	}
+
+	v := reflect.ValueOf(data)
+	if v.Kind() != reflect.Map || v.Type().Key().Kind() != reflect.String {
+		return nil, false
+	}
+	val := v.MapIndex(reflect.ValueOf(key).Convert(v.Type().Key()))
+	if !val.IsValid() {
+		return nil, false
+	}
+
+	return val.Interface(), true
+}
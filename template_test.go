@@ -0,0 +1,78 @@
+// Copyright 2016, Marc Lavergne <mlavergn@gmail.com>. All rights reserved.
+// Use of this source code is governed by the MIT
+// license that can be found in the LICENSE file.
+
+package godom
+
+import (
+	"testing"
+)
+
+func TestFill(t *testing.T) {
+	d := NewDOM()
+	d.SetContents(`<html><body>
+<h1 data-slot="title">Placeholder</h1>
+<p data-slot="missing">Kept</p>
+<div data-slot="user"><a data-bind="href:url; title:name" data-slot="name">Name</a></div>
+<ul><li data-slot="items"><a data-bind="href:href" data-slot="label"></a></li></ul>
+<ol><li data-slot="tags">tag</li></ol>
+<p data-slot="hidden">Hidden</p>
+<input data-bind="checked:done; disabled:locked">
+</body></html>`)
+	d.Freeze()
+
+	data := map[string]interface{}{
+		"title": "Hello <World>",
+		"user":  map[string]interface{}{"name": "Ann", "url": "/ann"},
+		"items": []map[string]interface{}{
+			{"href": "/a", "label": "A"},
+			{"href": "/b", "label": "B"},
+		},
+		"tags":   []string{"x", "y", "z"},
+		"hidden": false,
+		"done":   true,
+		"locked": nil,
+	}
+	filled, err := d.Fill(data)
+	if err != nil {
+		t.Fatalf("failed to fill %v", err)
+	}
+
+	if h1 := filled.Find("h1", nil); len(h1) != 1 || h1[0].Text() != "Hello <World>" || h1[0].HasAttr("data-slot") {
+		t.Errorf("failed to fill text slot")
+	}
+	if p := filled.Find("p", nil); len(p) != 1 || p[0].Text() != "Kept" {
+		t.Errorf("failed to keep missing slot or remove false slot")
+	}
+	if a := filled.Find("a", DOMNodeAttributes{"href": "/ann"}); len(a) != 1 || a[0].Attr("title") != "Ann" || a[0].Text() != "Ann" {
+		t.Errorf("failed to fill nested scope")
+	}
+	if html := filled.Find("ul", nil)[0].InnerHTML(); html != `<li><a href="/a">A</a></li><li><a href="/b">B</a></li>` {
+		t.Errorf("unexpected list %s", html)
+	}
+	if html := filled.Find("ol", nil)[0].InnerHTML(); html != `<li>x</li><li>y</li><li>z</li>` {
+		t.Errorf("unexpected list %s", html)
+	}
+	if input := filled.Find("input", nil)[0]; !input.HasAttr("checked") || input.HasAttr("disabled") || input.HasAttr("data-bind") {
+		t.Errorf("unexpected input attributes %v", input.Attributes)
+	}
+	for i, node := range filled.document {
+		if node.Index != i+1 {
+			t.Fatalf("unexpected index %d at %d", node.Index, i)
+		}
+	}
+
+	// the template is unchanged
+	if h1 := d.Find("h1", nil); h1[0].Text() != "Placeholder" || len(d.Find("li", nil)) != 2 {
+		t.Errorf("template was modified")
+	}
+
+	if _, err := d.Fill(map[string]interface{}{}); err != nil {
+		t.Errorf("failed to fill empty data %v", err)
+	}
+	bad := NewDOM()
+	bad.SetContents(`<html><body><a data-bind="href">x</a></body></html>`)
+	if _, err := bad.Fill(nil); err == nil {
+		t.Errorf("expected invalid data-bind error")
+	}
+}
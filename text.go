@@ -0,0 +1,199 @@
+// Copyright 2016 Marc Lavergne <mlavergn@gmail.com>. All rights reserved.
+// Use of this source code is governed by
+// license that can be found in the LICENSE file.
+
+package godom
+
+import (
+	"strconv"
+	"strings"
+)
+
+// textBlockTags elements that start and end on their own line
+var textBlockTags = map[string]int{
+	"address": 1, "article": 1, "aside": 1, "blockquote": 1, "body": 1, "caption": 1, "dd": 1, "details": 1,
+	"div": 1, "dl": 1, "dt": 1, "fieldset": 1, "figcaption": 1, "figure": 1, "footer": 1, "form": 1,
+	"h1": 1, "h2": 1, "h3": 1, "h4": 1, "h5": 1, "h6": 1, "header": 1, "hr": 1, "html": 1, "li": 1,
+	"main": 1, "nav": 1, "ol": 1, "p": 1, "pre": 1, "section": 1, "summary": 1, "table": 1, "tbody": 1,
+	"tfoot": 1, "thead": 1, "tr": 1, "ul": 1,
+}
+
+// textSkipTags elements whose text is not rendered
+var textSkipTags = map[string]int{
+	"head": 1, "script": 1, "style": 1, "template": 1, "noscript": 1, "iframe": 1,
+}
+
+// TextOptions def
+// Controls the rendering of the node text
+type TextOptions struct {
+	// ListPrefix prefixes list items with "- ", or their number in an ordered list
+	ListPrefix bool
+	// Separator joins the text fragments in TextWithOptions and ReaderTextWithOptions
+	Separator string
+}
+
+//
+// TextContent : Render the text of the node as a reader would see it, like
+// innerText. Block elements start on a new line, table cells are separated
+// by tabs, whitespace within a block is collapsed, and <pre> is kept as is.
+//
+func (id *DOMNode) TextContent(opts TextOptions) string {
+	writer := &textWriter{options: opts}
+	writer.node(id, false)
+
+	return writer.sb.String()
+}
+
+//
+// TextWithOptions : The text fragments of the node joined by the separator.
+// An empty separator concatenates the fragments, which reproduces the source
+// text of a node parsed with preserved whitespace.
+//
+func (id *DOMNode) TextWithOptions(opts TextOptions) string {
+	return strings.Join(id.TextFragments, opts.Separator)
+}
+
+//
+// ReaderTextWithOptions : The non-empty text fragments of the node and its
+// descendants in document order, joined by the separator.
+//
+func (id *DOMNode) ReaderTextWithOptions(opts TextOptions) string {
+	return strings.Join(id.readerText(nil), opts.Separator)
+}
+
+//
+// DOMNode: Collect the non-empty text fragments of the subtree in document order.
+//
+func (id *DOMNode) readerText(result []string) []string {
+	id.eachContent(func(text string) {
+		if len(text) > 0 {
+			result = append(result, text)
+		}
+	}, func(child *DOMNode) {
+		result = child.readerText(result)
+	})
+
+	return result
+}
+
+// textWriter def
+// Accumulates the rendered text, deferring separators until text follows
+type textWriter struct {
+	sb      strings.Builder
+	options TextOptions
+	// sep is owed before the next text, a newline outranks a tab or space
+	sep string
+}
+
+//
+// textWriter: Owe the separator before the next text.
+//
+func (id *textWriter) separate(sep string) {
+	if sep == "\n" || id.sep != "\n" {
+		id.sep = sep
+	}
+}
+
+//
+// textWriter: Write the text after any owed separator.
+//
+func (id *textWriter) write(text string) {
+	if len(text) == 0 {
+		return
+	}
+	if id.sb.Len() > 0 && !(id.sep == " " && joinsWithoutSpace(text)) {
+		id.sb.WriteString(id.sep)
+	}
+	id.sb.WriteString(text)
+	id.sep = " "
+}
+
+//
+// textWriter: Render the node, pre is set within a <pre>.
+//
+func (id *textWriter) node(node *DOMNode, pre bool) {
+	if textSkipTags[node.Tag] == 1 || pseudoTags[node.Tag] == 1 && node.Tag != "document" {
+		return
+	}
+
+	switch node.Tag {
+	case "br":
+		if id.sep == "\n" && id.sb.Len() > 0 {
+			id.sb.WriteString("\n")
+		}
+		id.sep = "\n"
+		return
+	case "pre":
+		pre = true
+	case "td", "th":
+		if node.PrevElement() != nil {
+			id.separate("\t")
+		}
+	}
+
+	block := textBlockTags[node.Tag] == 1
+	if block {
+		id.separate("\n")
+	}
+	if node.Tag == "li" && id.options.ListPrefix {
+		id.write(listItemPrefix(node))
+		id.sep = ""
+	}
+
+	node.eachContent(func(text string) {
+		if !pre {
+			text = strings.Join(strings.Fields(text), " ")
+		} else if node.preserveSpace && id.sep == " " {
+			// preserved text already carries its own spacing
+			id.sep = ""
+		}
+		id.write(text)
+	}, func(child *DOMNode) {
+		id.node(child, pre)
+	})
+
+	if block {
+		id.separate("\n")
+	}
+}
+
+//
+// listItemPrefix : The marker of the list item, indented by the nesting of
+// its list.
+//
+func listItemPrefix(item *DOMNode) string {
+	indent := ""
+	for ancestor := range item.Ancestors() {
+		if ancestor != item.Parent && (ancestor.Tag == "ul" || ancestor.Tag == "ol") {
+			indent += "  "
+		}
+	}
+
+	list := item.Parent
+	if list == nil || list.Tag != "ol" {
+		return indent + "- "
+	}
+
+	number, err := strconv.Atoi(list.Attr("start"))
+	if err != nil {
+		number = 1
+	}
+	for _, child := range list.Children {
+		if child == item {
+			break
+		}
+		if child.Tag == "li" {
+			number++
+		}
+	}
+
+	return indent + strconv.Itoa(number) + ". "
+}
+
+//
+// joinsWithoutSpace : Does the text attach to the preceding text, as closing
+// punctuation does?
+//
+func joinsWithoutSpace(text string) bool {
+	return len(text) > 0 && strings.ContainsAny(text[:1], ".,;:!?)")
+}
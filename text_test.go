@@ -0,0 +1,55 @@
+// Copyright 2016, Marc Lavergne <mlavergn@gmail.com>. All rights reserved.
+// Use of this source code is governed by the MIT
+// license that can be found in the LICENSE file.
+
+package godom
+
+import (
+	"testing"
+)
+
+func TestTextContent(t *testing.T) {
+	d := NewDOM()
+	d.SetContents("<html><body><div id=\"a\"><p>Hello   <b>bold</b> world.</p><div>line one<br>line two</div>" +
+		"<ol><li>first</li><li>second<ul><li>nested</li></ul></li></ol>" +
+		"<table><tr><td>a</td><td>b</td></tr><tr><td>c</td><td>d</td></tr></table></div></body></html>")
+	p := d.Find("div", map[string]string{"id": "a"})
+	if len(p) != 1 {
+		d.Dump()
+		t.Fatalf("failed to find node")
+	}
+
+	expected := "Hello bold world.\nline one\nline two\nfirst\nsecond\nnested\na\tb\nc\td"
+	if p[0].TextContent(TextOptions{}) != expected {
+		t.Errorf("failed to render text [%q]", p[0].TextContent(TextOptions{}))
+	}
+
+	expected = "Hello bold world.\nline one\nline two\n1. first\n2. second\n  - nested\na\tb\nc\td"
+	if p[0].TextContent(TextOptions{ListPrefix: true}) != expected {
+		t.Errorf("failed to render list prefixes [%q]", p[0].TextContent(TextOptions{ListPrefix: true}))
+	}
+}
+
+func TestPreserveWhitespace(t *testing.T) {
+	d := NewDOM()
+	d.SetParseOptions(ParseOptions{PreserveWhitespace: []string{"pre"}})
+	d.SetContents("<html><body><p>  trimmed  </p><pre>  a := 1\n  <b>b</b> := 2\n</pre></body></html>")
+
+	p := d.Find("p", nil)
+	if len(p) != 1 || p[0].Text() != "trimmed" {
+		d.Dump()
+		t.Fatalf("failed to trim text")
+	}
+
+	pre := d.Find("pre", nil)
+	if len(pre) != 1 {
+		d.Dump()
+		t.Fatalf("failed to find node")
+	}
+	if pre[0].ReaderTextWithOptions(TextOptions{}) != "  a := 1\n  b := 2\n" {
+		t.Errorf("failed to preserve whitespace [%q]", pre[0].ReaderTextWithOptions(TextOptions{}))
+	}
+	if pre[0].TextWithOptions(TextOptions{Separator: "|"}) != "  a := 1\n  | := 2\n" {
+		t.Errorf("failed to join fragments [%q]", pre[0].TextWithOptions(TextOptions{Separator: "|"}))
+	}
+}
@@ -0,0 +1,134 @@
+// Copyright 2016 Marc Lavergne <mlavergn@gmail.com>. All rights reserved.
+// Use of this source code is governed by
+// license that can be found in the LICENSE file.
+
+package godom
+
+import (
+	"fmt"
+	"strings"
+)
+
+// TransformAction type
+// The action a TransformRule applies to its matches
+type TransformAction int
+
+// TransformAction values
+const (
+	// TransformRemove removes the node and its subtree
+	TransformRemove TransformAction = iota
+	// TransformUnwrap replaces the node with its text and children
+	TransformUnwrap
+	// TransformRename replaces the tag of the node with Tag
+	TransformRename
+	// TransformSetAttr sets the attribute Key to Value
+	TransformSetAttr
+	// TransformRemoveAttr removes the attribute Key
+	TransformRemoveAttr
+	// TransformRewriteText replaces each text fragment of the subtree with
+	// the result of Text
+	TransformRewriteText
+)
+
+// TransformRule def
+// A CSS selector and the action applied to the nodes it matches
+type TransformRule struct {
+	Selector string
+	Action   TransformAction
+	Tag      string
+	Key      string
+	Value    string
+	Text     func(text string) string
+}
+
+//
+// Transform : Apply the rules in order, each to the nodes matching its
+// selector once the preceding rules have been applied. The document root
+// cannot be unwrapped and is left in place. Rules are checked before any is
+// applied, so an invalid rule leaves the DOM unchanged.
+//
+func (id *DOM) Transform(rules []TransformRule) error {
+	if id.frozen {
+		return ErrFrozen
+	}
+
+	selectors := make([]*Selector, len(rules))
+	for i, rule := range rules {
+		selector, err := ParseSelector(rule.Selector)
+		if err != nil {
+			return fmt.Errorf("godom: invalid transform selector %q: %v", rule.Selector, err)
+		}
+		switch {
+		case rule.Action == TransformRename && len(strings.TrimSpace(rule.Tag)) == 0,
+			(rule.Action == TransformSetAttr || rule.Action == TransformRemoveAttr) && len(attrKey(rule.Key)) == 0,
+			rule.Action == TransformRewriteText && rule.Text == nil,
+			rule.Action < TransformRemove || rule.Action > TransformRewriteText:
+			return fmt.Errorf("godom: invalid transform rule for %q", rule.Selector)
+		}
+		selectors[i] = selector
+	}
+
+	for i, rule := range rules {
+		var matches []*DOMNode
+		for _, node := range id.document {
+			if pseudoTags[node.Tag] == 0 && selectors[i].Match(node) {
+				matches = append(matches, node)
+			}
+		}
+		for _, node := range matches {
+			// skip the descendants of nodes removed by the rule
+			if node.dom != id {
+				continue
+			}
+			id.transformNode(node, rule)
+		}
+		id.reindex()
+	}
+
+	return nil
+}
+
+//
+// DOM: Apply the rule to the node, the caller reindexes.
+//
+func (id *DOM) transformNode(node *DOMNode, rule TransformRule) {
+	switch rule.Action {
+	case TransformRemove:
+		id.removeDocumentNodes(id.subtreeNodes(node))
+		if parent := node.Parent; parent != nil {
+			parent.unlinkChild(parent.childPosition(node))
+		}
+		node.setDOM(nil)
+	case TransformUnwrap:
+		parent := node.Parent
+		if parent == nil {
+			return
+		}
+		parent.unwrapChild(parent.childPosition(node))
+		// comments of the node are left in place under the parent
+		for _, docNode := range id.document {
+			if docNode.Parent == node {
+				docNode.Parent = parent
+			}
+		}
+		id.removeDocumentNodes([]*DOMNode{node})
+		node.dom = nil
+	case TransformRename:
+		tag := strings.TrimSpace(rule.Tag)
+		if !id.xml {
+			tag = strings.ToLower(tag)
+		}
+		// the case the tag was written in no longer applies
+		if node.Tag != tag || len(node.name) > 0 {
+			node.Tag = tag
+			node.name = ""
+			node.dirty = true
+		}
+	case TransformSetAttr:
+		node.SetAttr(rule.Key, rule.Value)
+	case TransformRemoveAttr:
+		node.RemoveAttr(rule.Key)
+	case TransformRewriteText:
+		node.ReplaceTextFunc(rule.Text)
+	}
+}
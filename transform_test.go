@@ -0,0 +1,76 @@
+// Copyright 2016, Marc Lavergne <mlavergn@gmail.com>. All rights reserved.
+// Use of this source code is governed by the MIT
+// license that can be found in the LICENSE file.
+
+package godom
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestTransform(t *testing.T) {
+	d := NewDOM()
+	d.SetContents(`<html><body>
+<div class="ad"><p>Buy now</p></div>
+<div class="wrapper"><p>One <b>bold</b> move</p><!-- note --><p>Two</p></div>
+<a href="http://example.com/x">link</a>
+<font color="red">old</font>
+</body></html>`)
+
+	err := d.Transform([]TransformRule{
+		{Selector: ".ad", Action: TransformRemove},
+		{Selector: "div.wrapper", Action: TransformUnwrap},
+		{Selector: "font", Action: TransformRename, Tag: "SPAN"},
+		{Selector: "span", Action: TransformRemoveAttr, Key: "color"},
+		{Selector: "a[href^=http:]", Action: TransformSetAttr, Key: "rel", Value: "nofollow"},
+		{Selector: "p", Action: TransformRewriteText, Text: strings.ToUpper},
+	})
+	if err != nil {
+		t.Fatalf("failed to transform %v", err)
+	}
+
+	if len(d.Find("div", nil)) != 0 {
+		d.Dump()
+		t.Errorf("failed to remove and unwrap")
+	}
+	p := d.Find("p", nil)
+	if len(p) != 2 || p[0].Parent.Tag != "body" || p[0].OuterHTML() != "<p>ONE<b>BOLD</b>MOVE</p>" {
+		t.Fatalf("failed to unwrap or rewrite text %d %s", len(p), p[0].OuterHTML())
+	}
+	if span := d.Find("span", nil); len(span) != 1 || span[0].HasAttr("color") || len(d.Find("font", nil)) != 0 {
+		t.Errorf("failed to rename")
+	}
+	if a := d.Find("a", nil); a[0].Attr("rel") != "nofollow" {
+		t.Errorf("failed to set attribute")
+	}
+	if comments := d.Comments(); len(comments) != 1 || comments[0].Parent.Tag != "body" {
+		t.Errorf("failed to keep the comment of the unwrapped node")
+	}
+	for i, node := range d.document {
+		if node.Index != i+1 || node.dom != &d {
+			t.Fatalf("unexpected document entry %d", i)
+		}
+	}
+
+	if err := d.Transform([]TransformRule{{Selector: "p", Action: TransformRewriteText}}); err == nil {
+		t.Errorf("expected invalid rule error")
+	}
+	if err := d.Transform([]TransformRule{{Selector: "[", Action: TransformRemove}}); err == nil {
+		t.Errorf("expected invalid selector error")
+	}
+	d.Freeze()
+	if err := d.Transform(nil); err != ErrFrozen {
+		t.Errorf("expected frozen error")
+	}
+
+	// a renamed tag drops the case it was written in
+	d = NewDOM(WithPreserveCase())
+	d.SetContents("<html><body><DIV>x</DIV></body></html>")
+	if err := d.Transform([]TransformRule{{Selector: "div", Action: TransformRename, Tag: "section"}}); err != nil {
+		t.Fatalf("failed to transform %v", err)
+	}
+	if html := d.Find("section", nil)[0].OuterHTML(); html != "<section>x</section>" {
+		t.Errorf("unexpected renamed tag %s", html)
+	}
+}
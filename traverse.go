@@ -0,0 +1,343 @@
+// Copyright 2016 Marc Lavergne <mlavergn@gmail.com>. All rights reserved.
+// Use of this source code is governed by
+// license that can be found in the LICENSE file.
+
+package godom
+
+import (
+	"iter"
+)
+
+//
+// NextElement : The element immediately following the node under the same parent.
+//
+func (id *DOMNode) NextElement() *DOMNode {
+	if id.Parent == nil {
+		return nil
+	}
+	position := id.Parent.childPosition(id)
+	if position < 0 || position+1 >= len(id.Parent.Children) {
+		return nil
+	}
+
+	return id.Parent.Children[position+1]
+}
+
+//
+// PrevElement : The element immediately preceding the node under the same parent.
+//
+func (id *DOMNode) PrevElement() *DOMNode {
+	if id.Parent == nil {
+		return nil
+	}
+	position := id.Parent.childPosition(id)
+	if position < 1 {
+		return nil
+	}
+
+	return id.Parent.Children[position-1]
+}
+
+//
+// Child : The child element at position i from 0, nil when out of range.
+//
+func (id *DOMNode) Child(i int) *DOMNode {
+	if i < 0 || i >= len(id.Children) {
+		return nil
+	}
+
+	return id.Children[i]
+}
+
+//
+// ChildCount : The number of child elements.
+//
+func (id *DOMNode) ChildCount() int {
+	return len(id.Children)
+}
+
+//
+// NthOfType : The nth child element of type tag counting from 1, as the CSS
+// :nth-of-type(n) does, the tag "*" counts every element. Nil when there is
+// no such child.
+//
+func (id *DOMNode) NthOfType(tag string, n int) *DOMNode {
+	if n < 1 {
+		return nil
+	}
+	for _, child := range id.Children {
+		if tag == "*" || child.Tag == tag {
+			n--
+			if n == 0 {
+				return child
+			}
+		}
+	}
+
+	return nil
+}
+
+//
+// SiblingIndex : The position of the node in the child elements of its
+// parent from 0, -1 without a parent.
+//
+func (id *DOMNode) SiblingIndex() int {
+	if id.Parent == nil {
+		return -1
+	}
+
+	return id.Parent.childPosition(id)
+}
+
+//
+// NextSibling : The node immediately following the node under the same parent,
+// including comment nodes. Falls back to NextElement for detached nodes.
+//
+func (id *DOMNode) NextSibling() *DOMNode {
+	if id.dom == nil {
+		return id.NextElement()
+	}
+	position := id.dom.documentPosition(id)
+	if position < 0 {
+		return id.NextElement()
+	}
+
+	// skip past the subtree of the node, stopping once we leave the parent
+	for _, node := range id.dom.document[position+1:] {
+		if isAncestorNode(id, node) {
+			continue
+		}
+		if node.Parent == id.Parent {
+			return node
+		}
+		if id.Parent == nil || !isAncestorNode(id.Parent, node) {
+			break
+		}
+	}
+
+	return nil
+}
+
+//
+// PrevSibling : The node immediately preceding the node under the same parent,
+// including comment nodes. Falls back to PrevElement for detached nodes.
+//
+func (id *DOMNode) PrevSibling() *DOMNode {
+	if id.dom == nil {
+		return id.PrevElement()
+	}
+	position := id.dom.documentPosition(id)
+	if position < 0 {
+		return id.PrevElement()
+	}
+
+	// walk back over the subtree of the preceding sibling
+	for i := position - 1; i >= 0; i-- {
+		node := id.dom.document[i]
+		if node.Parent == id.Parent {
+			return node
+		}
+		if id.Parent != nil && (node == id.Parent || !isAncestorNode(id.Parent, node)) {
+			break
+		}
+	}
+
+	return nil
+}
+
+//
+// Closest : The nearest of the node and its ancestors of type tag with the
+// specified attributes, an HTML tag ignores case as Find does.
+//
+func (id *DOMNode) Closest(tag string, attributes DOMNodeAttributes) *DOMNode {
+	for node := id; node != nil; node = node.Parent {
+		if node.isTag(tag) && node.matchAttributes(attributes) {
+			return node
+		}
+	}
+
+	return nil
+}
+
+//
+// ClosestSelector : The nearest of the node and its ancestors matching selector.
+//
+func (id *DOMNode) ClosestSelector(selector *Selector) *DOMNode {
+	for node := id; node != nil; node = node.Parent {
+		if selector.Match(node) {
+			return node
+		}
+	}
+
+	return nil
+}
+
+// WalkAction type
+// Returned by Walk callbacks to steer the traversal
+type WalkAction int
+
+const (
+	// WalkContinue visit the children and siblings of the node
+	WalkContinue WalkAction = iota
+	// WalkSkipChildren do not visit the children of the node
+	WalkSkipChildren
+	// WalkStop end the traversal
+	WalkStop
+)
+
+// WalkFunc type
+// Called for each node with its depth relative to the start of the walk
+type WalkFunc func(node *DOMNode, depth int) WalkAction
+
+//
+// Walk : Visit the element nodes from the root node in pre-order.
+//
+func (id *DOM) Walk(fn WalkFunc) {
+	if root := id.RootNode(); root != nil {
+		root.Walk(fn)
+	}
+}
+
+//
+// WalkPostOrder : Visit the element nodes from the root node in post-order,
+// children before their parent.
+//
+func (id *DOM) WalkPostOrder(fn WalkFunc) {
+	if root := id.RootNode(); root != nil {
+		root.WalkPostOrder(fn)
+	}
+}
+
+//
+// Walk : Visit the node and its descendants in pre-order.
+//
+func (id *DOMNode) Walk(fn WalkFunc) {
+	id.walk(fn, 0)
+}
+
+//
+// WalkPostOrder : Visit the node and its descendants in post-order, the
+// WalkSkipChildren action has no effect.
+//
+func (id *DOMNode) WalkPostOrder(fn WalkFunc) {
+	id.walkPostOrder(fn, 0)
+}
+
+//
+// DOMNode: Pre-order traversal, returns false once stopped.
+//
+func (id *DOMNode) walk(fn WalkFunc, depth int) bool {
+	switch fn(id, depth) {
+	case WalkStop:
+		return false
+	case WalkSkipChildren:
+		return true
+	}
+
+	for _, child := range id.Children {
+		if !child.walk(fn, depth+1) {
+			return false
+		}
+	}
+
+	return true
+}
+
+//
+// DOMNode: Post-order traversal, returns false once stopped.
+//
+func (id *DOMNode) walkPostOrder(fn WalkFunc, depth int) bool {
+	for _, child := range id.Children {
+		if !child.walkPostOrder(fn, depth+1) {
+			return false
+		}
+	}
+
+	return fn(id, depth) != WalkStop
+}
+
+//
+// All : Iterate every node in document order, including comment and doctype
+// nodes. The DOM must not be mutated during iteration.
+//
+func (id *DOM) All() iter.Seq[*DOMNode] {
+	return func(yield func(*DOMNode) bool) {
+		for _, node := range id.document {
+			if !yield(node) {
+				return
+			}
+		}
+	}
+}
+
+//
+// Descendants : Iterate the element descendants of the node in pre-order.
+//
+func (id *DOMNode) Descendants() iter.Seq[*DOMNode] {
+	return func(yield func(*DOMNode) bool) {
+		id.yieldDescendants(yield)
+	}
+}
+
+//
+// Ancestors : Iterate the ancestors of the node, nearest first.
+//
+func (id *DOMNode) Ancestors() iter.Seq[*DOMNode] {
+	return func(yield func(*DOMNode) bool) {
+		for node := id.Parent; node != nil; node = node.Parent {
+			if !yield(node) {
+				return
+			}
+		}
+	}
+}
+
+//
+// DOMNode: Yield the descendants, returns false once the consumer stops.
+//
+func (id *DOMNode) yieldDescendants(yield func(*DOMNode) bool) bool {
+	for _, child := range id.Children {
+		if !yield(child) || !child.yieldDescendants(yield) {
+			return false
+		}
+	}
+
+	return true
+}
+
+//
+// Depth : The number of ancestors of the node, 0 for the root node.
+//
+func (id *DOMNode) Depth() int {
+	if id.dom != nil {
+		return id.depth
+	}
+
+	// detached nodes are not kept up to date
+	depth := 0
+	for node := id.Parent; node != nil; node = node.Parent {
+		depth++
+	}
+
+	return depth
+}
+
+//
+// SubtreeSize : The number of element descendants of the node, counted on
+// first use and kept until the DOM is modified.
+//
+func (id *DOMNode) SubtreeSize() int {
+	if id.subtreeSize > 0 && id.dom != nil {
+		return id.subtreeSize - 1
+	}
+
+	size := 0
+	for _, child := range id.Children {
+		size += child.SubtreeSize() + 1
+	}
+	if id.dom != nil && !id.dom.frozen {
+		id.subtreeSize = size + 1
+	}
+
+	return size
+}
@@ -0,0 +1,215 @@
+// Copyright 2016, Marc Lavergne <mlavergn@gmail.com>. All rights reserved.
+// Use of this source code is governed by the MIT
+// license that can be found in the LICENSE file.
+
+package godom
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestSiblings(t *testing.T) {
+	d := NewDOM()
+	d.SetContents("<html><dl><dt id='name'>Name</dt><dd>Foo <b>Bar</b></dd><!-- note --><dt id='age'>Age</dt><dd>42</dd></dl></html>")
+	p := d.Find("dt", map[string]string{"id": "name"})
+	if len(p) != 1 {
+		d.Dump()
+		t.Fatalf("failed to find node")
+	}
+
+	value := p[0].NextElement()
+	if value == nil || value.Tag != "dd" || value.Text() != "Foo" {
+		t.Fatalf("failed to find next element %v", value)
+	}
+	if value.PrevElement() != p[0] || p[0].PrevElement() != nil {
+		t.Errorf("failed to find previous element")
+	}
+
+	comment := value.NextSibling()
+	if comment == nil || comment.Tag != "comment" {
+		t.Fatalf("failed to find next sibling %v", comment)
+	}
+	age := comment.NextSibling()
+	if age == nil || age.Attr("id") != "age" || value.NextElement() != age {
+		t.Errorf("failed to find next sibling past comment %v", age)
+	}
+	if age.PrevSibling() != comment || comment.PrevSibling() != value || p[0].PrevSibling() != nil {
+		t.Errorf("failed to find previous sibling")
+	}
+	if age.NextElement().NextSibling() != nil {
+		t.Errorf("failed to stop at last sibling")
+	}
+
+	// top level siblings skip the subtree
+	d = NewDOM()
+	d.SetContentsFromReader(strings.NewReader("<p><b>x</b></p><p>y</p><!-- end -->"))
+	top := d.Find("p", nil)
+	if len(top) != 2 || top[0].NextSibling() != top[1] || top[1].PrevSibling() != top[0] {
+		t.Fatalf("failed to find top level siblings")
+	}
+	if end := top[1].NextSibling(); end == nil || end.Tag != "comment" || end.PrevSibling() != top[1] {
+		t.Errorf("failed to find trailing comment %v", end)
+	}
+}
+
+func TestClosest(t *testing.T) {
+	d := NewDOM()
+	d.SetContents("<html><article class='card featured' id='x'><div class='body'><p>Price <span>$10</span></p></div></article></html>")
+	p := d.FindWithKey("span", "$10")
+	if len(p) != 1 {
+		d.Dump()
+		t.Fatalf("failed to find node")
+	}
+
+	card := p[0].Closest("article", DOMNodeAttributes{"class": "card"})
+	if card == nil || card.Attr("id") != "x" {
+		t.Errorf("failed to find closest ancestor")
+	}
+	if p[0].Closest("ARTICLE", nil) != card {
+		t.Errorf("failed to ignore the case of the HTML tag")
+	}
+	if p[0].Closest("span", nil) != p[0] {
+		t.Errorf("failed to match self")
+	}
+	if p[0].Closest("section", nil) != nil {
+		t.Errorf("failed to reject missing ancestor")
+	}
+
+	if p[0].ClosestSelector(MustParseSelector("article.featured > div")).Attr("class") != "body" {
+		t.Errorf("failed to find closest ancestor by selector")
+	}
+	if p[0].ClosestSelector(MustParseSelector("section, #x")) != card {
+		t.Errorf("failed to find closest ancestor by selector group")
+	}
+	if p[0].ClosestSelector(MustParseSelector("article > p")) != nil {
+		t.Errorf("failed to reject child combinator")
+	}
+}
+
+func TestWalk(t *testing.T) {
+	d := NewDOM()
+	d.SetContents("<html><div id='a'><p>one</p><nav><a>skip</a></nav></div><div id='b'><p>two</p></div></html>")
+
+	visited := []string{}
+	d.Walk(func(node *DOMNode, depth int) WalkAction {
+		if node.Tag == "head" || node.Tag == "nav" {
+			return WalkSkipChildren
+		}
+		visited = append(visited, node.Tag)
+		if node.Attr("id") == "b" {
+			return WalkStop
+		}
+		return WalkContinue
+	})
+	if strings.Join(visited, ",") != "html,body,div,p,div" {
+		t.Errorf("failed to walk pre-order %v", visited)
+	}
+
+	visited = []string{}
+	d.WalkPostOrder(func(node *DOMNode, depth int) WalkAction {
+		visited = append(visited, fmt.Sprintf("%s:%d", node.Tag, depth))
+		if node.Tag == "nav" {
+			return WalkStop
+		}
+		return WalkContinue
+	})
+	if strings.Join(visited, ",") != "head:1,p:3,a:4,nav:3" {
+		t.Errorf("failed to walk post-order %v", visited)
+	}
+}
+
+func TestIterators(t *testing.T) {
+	d := NewDOM()
+	d.SetContents("<html><!-- top --><div id='a'><p>one <b>bold</b></p><p>two</p></div></html>")
+
+	count := 0
+	comments := 0
+	for node := range d.All() {
+		count++
+		if node.Tag == "comment" {
+			comments++
+		}
+	}
+	if count != len(d.document) || comments != 1 {
+		t.Errorf("failed to iterate all nodes %d", count)
+	}
+
+	div := d.Find("div", nil)
+	if len(div) != 1 {
+		d.Dump()
+		t.Fatalf("failed to find node")
+	}
+	tags := []string{}
+	for node := range div[0].Descendants() {
+		tags = append(tags, node.Tag)
+		if node.Tag == "b" {
+			break
+		}
+	}
+	if strings.Join(tags, ",") != "p,b" {
+		t.Errorf("failed to iterate descendants %v", tags)
+	}
+
+	tags = []string{}
+	for node := range d.Find("b", nil)[0].Ancestors() {
+		tags = append(tags, node.Tag)
+	}
+	if strings.Join(tags, ",") != "p,div,body,html" {
+		t.Errorf("failed to iterate ancestors %v", tags)
+	}
+}
+
+func TestDepthSubtreeSize(t *testing.T) {
+	d := NewDOM()
+	d.SetContents("<html><body><div id='a'><p>One <b>two</b></p><p>Three</p></div></body></html>")
+
+	div := d.ByID("a")
+	b := d.Find("b", nil)[0]
+	if d.RootNode().Depth() != 0 || div.Depth() != 2 || b.Depth() != 4 {
+		t.Errorf("unexpected depths %d %d %d", d.RootNode().Depth(), div.Depth(), b.Depth())
+	}
+	if div.SubtreeSize() != 3 || d.RootNode().SubtreeSize() != 6 || b.SubtreeSize() != 0 {
+		t.Errorf("unexpected subtree sizes %d %d", div.SubtreeSize(), d.RootNode().SubtreeSize())
+	}
+
+	p := d.Find("p", nil)[1]
+	if err := p.AppendChild(b); err != nil {
+		t.Fatal(err)
+	}
+	if b.Depth() != 4 || div.SubtreeSize() != 3 || p.SubtreeSize() != 1 {
+		t.Errorf("unexpected counts after move %d %d", div.SubtreeSize(), p.SubtreeSize())
+	}
+	if err := d.RootNode().AppendChild(div); err != nil {
+		t.Fatal(err)
+	}
+	if div.Depth() != 1 || b.Depth() != 3 || d.Find("body", nil)[0].SubtreeSize() != 0 {
+		t.Errorf("unexpected depths after move %d %d", div.Depth(), b.Depth())
+	}
+	if err := d.RootNode().RemoveChild(div); err != nil {
+		t.Fatal(err)
+	}
+	if div.Depth() != 0 || b.Depth() != 2 || div.SubtreeSize() != 3 {
+		t.Errorf("unexpected detached counts %d %d %d", div.Depth(), b.Depth(), div.SubtreeSize())
+	}
+}
+
+func TestChildPositions(t *testing.T) {
+	d := NewDOM()
+	d.SetContents("<html><body><table><tr><th>h</th><td>1</td><td>2</td><td>3</td></tr></table></body></html>")
+
+	tr := d.Find("tr", nil)[0]
+	if tr.ChildCount() != 4 || tr.Child(1).Text() != "1" || tr.Child(4) != nil || tr.Child(-1) != nil {
+		t.Errorf("unexpected children of %d", tr.ChildCount())
+	}
+	if td := tr.NthOfType("td", 3); td == nil || td.Text() != "3" {
+		t.Errorf("unexpected third cell %v", td)
+	}
+	if tr.NthOfType("td", 4) != nil || tr.NthOfType("td", 0) != nil || tr.NthOfType("*", 1).Tag != "th" {
+		t.Error("unexpected nth of type")
+	}
+	if tr.Child(2).SiblingIndex() != 2 || d.RootNode().SiblingIndex() != -1 {
+		t.Errorf("unexpected sibling index %d", tr.Child(2).SiblingIndex())
+	}
+}
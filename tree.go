@@ -0,0 +1,118 @@
+// Copyright 2016 Marc Lavergne <mlavergn@gmail.com>. All rights reserved.
+// Use of this source code is governed by
+// license that can be found in the LICENSE file.
+
+package godom
+
+import (
+	"io"
+	"strconv"
+	"strings"
+)
+
+// TreeOptions def
+// Controls the tree written by Tree
+type TreeOptions struct {
+	// Root is the node the tree starts from, the root node when nil
+	Root *DOMNode
+	// Indent is repeated once per depth, two spaces when empty
+	Indent string
+	// MaxDepth collapses the elements at that depth below the root into a
+	// line counting their descendants, 0 is unlimited
+	MaxDepth int
+	// MaxText is the number of runes of text shown after each element, longer
+	// text is truncated with an ellipsis and 0 shows none
+	MaxText int
+	// Tags limits the tree to the elements of the tags and their ancestors
+	Tags []string
+	// SkipTags are the tags whose subtrees are left out, eg. script and style
+	SkipTags []string
+}
+
+// treeWriter def
+// Writes nodes as indented lines, keeping the first write error
+type treeWriter struct {
+	w       io.Writer
+	options TreeOptions
+	skip    map[string]int
+	// keep holds the nodes shown when limited to tags, nil when unlimited
+	keep map[*DOMNode]bool
+	err  error
+}
+
+//
+// Tree : Write the element tree as indented lines, each holding the tag, id,
+// and classes of an element followed by its text, like the elements panel of
+// the browser developer tools.
+//
+func (id *DOM) Tree(w io.Writer, opts TreeOptions) error {
+	if len(opts.Indent) == 0 {
+		opts.Indent = "  "
+	}
+	writer := &treeWriter{w: w, options: opts, skip: tagSet(opts.SkipTags)}
+
+	root := opts.Root
+	if root == nil {
+		root = id.RootNode()
+	}
+	if root == nil {
+		return nil
+	}
+
+	if len(opts.Tags) > 0 {
+		tags := tagSet(opts.Tags)
+		writer.keep = map[*DOMNode]bool{}
+		root.Walk(func(node *DOMNode, depth int) WalkAction {
+			if writer.skip[node.Tag] == 1 {
+				return WalkSkipChildren
+			}
+			if tags[node.Tag] == 1 {
+				for ancestor := node; ancestor != nil && !writer.keep[ancestor]; ancestor = ancestor.Parent {
+					writer.keep[ancestor] = true
+					if ancestor == root {
+						break
+					}
+				}
+			}
+			return WalkContinue
+		})
+	}
+	writer.node(root, 0)
+
+	return writer.err
+}
+
+//
+// treeWriter: Write the string unless a write already failed.
+//
+func (id *treeWriter) write(s string) {
+	if id.err == nil {
+		_, id.err = io.WriteString(id.w, s)
+	}
+}
+
+//
+// treeWriter: Write the line of the node and its subtree unless collapsed.
+//
+func (id *treeWriter) node(node *DOMNode, depth int) {
+	if id.skip[node.Tag] == 1 || (id.keep != nil && !id.keep[node]) {
+		return
+	}
+
+	line := strings.Repeat(id.options.Indent, depth) + nodeSummary(node)
+	collapsed := len(node.Children) > 0 && id.options.MaxDepth > 0 && depth >= id.options.MaxDepth
+	if collapsed {
+		line += " (+" + strconv.Itoa(node.SubtreeSize()) + ")"
+	}
+	if text := truncateText(node.Text(), id.options.MaxText); len(text) > 0 {
+		line += " \"" + text + "\""
+	}
+	id.write(line + "\n")
+
+	if collapsed {
+		return
+	}
+	for _, child := range node.Children {
+		id.node(child, depth+1)
+	}
+}
@@ -0,0 +1,40 @@
+// Copyright 2016, Marc Lavergne <mlavergn@gmail.com>. All rights reserved.
+// Use of this source code is governed by the MIT
+// license that can be found in the LICENSE file.
+
+package godom
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestTree(t *testing.T) {
+	d := NewDOM()
+	d.SetContents(`<html><head><script>var x = 1;</script></head><body><div id="a" class="x y"><p>Some long paragraph text</p><ul><li>one</li><li>two</li></ul></div></body></html>`)
+
+	sb := strings.Builder{}
+	if err := d.Tree(&sb, TreeOptions{MaxText: 9, SkipTags: []string{"script"}}); err != nil {
+		t.Fatal(err)
+	}
+	expected := "html\n  head\n  body\n    div#a.x.y\n      p \"Some long…\"\n      ul\n        li \"one\"\n        li \"two\"\n"
+	if tree := sb.String(); tree != expected {
+		t.Errorf("expected\n%s\ngot\n%s", expected, tree)
+	}
+
+	sb.Reset()
+	if err := d.Tree(&sb, TreeOptions{Root: d.ByID("a"), Indent: "\t", MaxDepth: 1}); err != nil {
+		t.Fatal(err)
+	}
+	if expected := "div#a.x.y\n\tp\n\tul (+2)\n"; sb.String() != expected {
+		t.Errorf("expected\n%s\ngot\n%s", expected, sb.String())
+	}
+
+	sb.Reset()
+	if err := d.Tree(&sb, TreeOptions{Tags: []string{"li"}}); err != nil {
+		t.Fatal(err)
+	}
+	if expected := "html\n  body\n    div#a.x.y\n      ul\n        li\n        li\n"; sb.String() != expected {
+		t.Errorf("expected\n%s\ngot\n%s", expected, sb.String())
+	}
+}
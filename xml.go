@@ -0,0 +1,140 @@
+// Copyright 2016 Marc Lavergne <mlavergn@gmail.com>. All rights reserved.
+// Use of this source code is governed by
+// license that can be found in the LICENSE file.
+
+package godom
+
+import (
+	"encoding/xml"
+	"fmt"
+	"golang.org/x/net/html/charset"
+	"io"
+	"strings"
+)
+
+//
+// SetContentsXML : Parse the raw XML contents, eg. a sitemap or feed, into
+// the DOM. Tags and attribute names keep their case and namespace prefix, eg.
+// dc:creator, so Find works with the qualified name; FindNS matches by
+// namespace URI. HTML entities are accepted. The first element without a
+// parent is the root node.
+//
+func (id *DOM) SetContentsXML(xmlString string) error {
+	if id.frozen {
+		return ErrFrozen
+	}
+	id.contents = xmlString
+	id.parseErr = nil
+	id.xml = true
+	id.rootNode = nil
+
+	decoder := xml.NewDecoder(strings.NewReader(xmlString))
+	decoder.Entity = xml.HTMLEntity
+	decoder.CharsetReader = charset.NewReaderLabel
+
+	id.addNode(nil, "document", nil)
+	var parent *DOMNode
+	for {
+		token, err := decoder.RawToken()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return err
+		}
+
+		switch token := token.(type) {
+		case xml.StartElement:
+			attrs := make(DOMNodeAttributes, len(token.Attr))
+			for _, attr := range token.Attr {
+				attrs[xmlName(attr.Name)] = attr.Value
+			}
+			parent = id.addElementNode(parent, xmlName(token.Name), attrs)
+		case xml.EndElement:
+			// RawToken leaves the nesting to the caller
+			if parent == nil || parent.Tag != xmlName(token.Name) {
+				return fmt.Errorf("godom: unexpected end element %s", xmlName(token.Name))
+			}
+			parent = parent.Parent
+		case xml.CharData:
+			text := parseText(parent, string(token))
+			if parent != nil && len(text) > 0 {
+				parent.appendText(text)
+			}
+		case xml.Comment:
+			comment := id.addNode(parent, "comment", nil)
+			comment.appendText(string(token))
+			if id.options.IndexComments {
+				id.indexNode(comment)
+			}
+		case xml.Directive:
+			id.addNode(parent, "doctype", nil)
+		}
+	}
+	if parent != nil {
+		return fmt.Errorf("godom: unclosed element %s", parent.Tag)
+	}
+
+	return id.parseErr
+}
+
+//
+// xmlName : The qualified name with its prefix, as in the source.
+//
+func xmlName(name xml.Name) string {
+	if len(name.Space) > 0 {
+		return name.Space + ":" + name.Local
+	}
+
+	return name.Local
+}
+
+//
+// LocalName : The tag without its namespace prefix.
+//
+func (id *DOMNode) LocalName() string {
+	if idx := strings.IndexByte(id.Tag, ':'); idx >= 0 {
+		return id.Tag[idx+1:]
+	}
+
+	return id.Tag
+}
+
+//
+// Namespace : The namespace URI of the node, declared by the xmlns
+// attributes of the node or its nearest ancestor for the tag prefix.
+//
+func (id *DOMNode) Namespace() string {
+	key := "xmlns"
+	if idx := strings.IndexByte(id.Tag, ':'); idx >= 0 {
+		key = "xmlns:" + id.Tag[:idx]
+	}
+	for node := id; node != nil; node = node.Parent {
+		if uri, ok := node.Attributes[key]; ok {
+			return uri
+		}
+	}
+
+	return ""
+}
+
+//
+// FindNS : Find the Nodes with the local name in the namespace URI, whatever
+// prefix the document binds the namespace to
+//
+func (id *DOM) FindNS(namespace string, local string) (result []*DOMNode) {
+	return id.ChildFindNS(id.RootNode(), namespace, local)
+}
+
+//
+// ChildFindNS : Find the child Nodes with the local name in the namespace URI
+//
+func (id *DOM) ChildFindNS(parent *DOMNode, namespace string, local string) (result []*DOMNode) {
+	for _, node := range id.ChildFind(parent, "*", nil) {
+		if node.LocalName() == local && node.Namespace() == namespace {
+			result = append(result, node)
+		}
+	}
+
+	return result
+}
@@ -0,0 +1,57 @@
+// Copyright 2016, Marc Lavergne <mlavergn@gmail.com>. All rights reserved.
+// Use of this source code is governed by the MIT
+// license that can be found in the LICENSE file.
+
+package godom
+
+import (
+	"testing"
+)
+
+func TestSetContentsXML(t *testing.T) {
+	contents := `<?xml version="1.0" encoding="UTF-8"?>
+<rss version="2.0" xmlns:dc="http://purl.org/dc/elements/1.1/">
+  <channel>
+    <title>Feed &amp; more&nbsp;</title>
+    <!-- items -->
+    <item><title>First</title><dc:creator>Ann</dc:creator><pubDate>Mon</pubDate></item>
+    <item><title><![CDATA[<b>Second</b>]]></title><creator xmlns="http://purl.org/dc/elements/1.1/">Bo</creator></item>
+  </channel>
+</rss>`
+
+	d := NewDOM()
+	if err := d.SetContentsXML(contents); err != nil {
+		t.Fatalf("failed to parse XML [%v]", err)
+	}
+
+	if root := d.RootNode(); root == nil || root.Tag != "rss" || root.Attr("version") != "2.0" {
+		d.Dump()
+		t.Fatalf("failed to find XML root")
+	}
+	items := d.Find("item", nil)
+	if len(items) != 2 || d.ChildFind(items[1], "title", nil)[0].Text() != "<b>Second</b>" {
+		t.Fatalf("failed to find items")
+	}
+	if len(d.Find("pubDate", nil)) != 1 || len(d.Find("pubdate", nil)) != 0 {
+		t.Errorf("failed to keep tag case")
+	}
+	pubDate := d.Find("pubDate", nil)[0]
+	if !MustParseSelector("item > pubDate").Match(pubDate) || MustParseSelector("pubdate").Match(pubDate) ||
+		len(MustCompileQuery("item pubDate").Run(&d)) != 1 || pubDate.ClosestSelector(MustParseSelector("rss channel")) == nil {
+		t.Errorf("failed to select by tag case")
+	}
+	if nodes := d.Find("dc:creator", nil); len(nodes) != 1 || nodes[0].Text() != "Ann" || nodes[0].LocalName() != "creator" {
+		t.Errorf("failed to find by qualified name")
+	}
+	if nodes := d.FindNS("http://purl.org/dc/elements/1.1/", "creator"); len(nodes) != 2 || nodes[1].Text() != "Bo" {
+		t.Errorf("failed to find by namespace")
+	}
+	if d.Find("title", nil)[0].Text() != "Feed & more" {
+		t.Errorf("failed to decode entities [%q]", d.Find("title", nil)[0].Text())
+	}
+
+	d = NewDOM()
+	if err := d.SetContentsXML("<a><b></a>"); err == nil {
+		t.Errorf("failed to report mismatched element")
+	}
+}